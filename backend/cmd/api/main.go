@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -18,10 +21,13 @@ import (
 	"search_engine/internal/api"
 	"search_engine/internal/api/handlers"
 	"search_engine/internal/config"
+	"search_engine/internal/domain/entities"
 	"search_engine/internal/domain/scoring"
 	"search_engine/internal/infrastructure/cache"
+	"search_engine/internal/infrastructure/circuitbreaker"
 	"search_engine/internal/infrastructure/database"
 	"search_engine/internal/infrastructure/jobs"
+	"search_engine/internal/infrastructure/leader"
 	infraproviders "search_engine/internal/infrastructure/providers"
 	"search_engine/internal/infrastructure/ratelimiter"
 	"search_engine/internal/infrastructure/repository/postgres"
@@ -56,6 +62,34 @@ func main() {
 		log.Fatal("redis connection error", zap.Error(err))
 	}
 	defer func() { _ = redisClient.Close() }()
+	// The probe switches caches and rate limits to process-local stores while Redis is down
+	var redisHealth *cache.RedisHealth
+	redisProbeEvery, _ := time.ParseDuration(cfg.RedisProbeInterval)
+	fallbackEntries, _ := strconv.Atoi(cfg.RedisFallbackMaxEntries)
+	if redisProbeEvery > 0 {
+		redisHealth = cache.NewRedisHealth(redisClient, log)
+		redisHealth.FailureThreshold, _ = strconv.Atoi(cfg.RedisProbeFailures)
+	}
+	newCacheStore := func() *cache.Store {
+		if redisHealth == nil {
+			return cache.NewStore(redisClient, nil, nil)
+		}
+		return cache.NewStore(redisClient, redisHealth, cache.NewMemory(fallbackEntries))
+	}
+	cacheStore := newCacheStore()
+
+	// Scheduled jobs run only on the replica holding their lease
+	var elector *leader.Elector
+	if cfg.LeaderElectionEnabled == "true" {
+		instanceID := cfg.InstanceID
+		if instanceID == "" {
+			instanceID = leader.DefaultID()
+		}
+		leaseTTL, _ := time.ParseDuration(cfg.LeaderLeaseTTL)
+		elector = leader.NewElector(redisClient, instanceID, leaseTTL, log)
+		elector.Start(context.Background())
+		defer elector.Stop()
+	}
 
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
@@ -73,16 +107,13 @@ func main() {
 	// Public rate limit middleware (per IP)
 	publicLimit, _ := strconv.Atoi(cfg.PublicRateLimit)
 	publicWindow, _ := time.ParseDuration(cfg.PublicRateLimitWindow)
-	publicRL := pubmw.PublicRateLimit(redisClient, publicLimit > 0, publicLimit, publicWindow)
+	publicRL := pubmw.PublicRateLimit(newCacheStore(), publicLimit > 0, publicLimit, publicWindow)
 	router.Use(publicRL)
 
 	version := os.Getenv("APP_VERSION")
 	if version == "" {
 		version = "1.0.0"
 	}
-	healthHandler := handlers.NewHealthHandler(dbPool, redisClient, appStart, version, log)
-	router.GET("/health", healthHandler)
-
 	// Detailed metrics endpoint (for monitoring)
 	metricsHandler := handlers.DetailedMetricsHandler(dbPool, redisClient, appStart, log)
 	router.GET("/api/v1/admin/metrics/system", metricsHandler)
@@ -96,18 +127,103 @@ func main() {
 
 	// Provider factory and service wiring (for use in future endpoints/jobs)
 	providerTimeout, _ := time.ParseDuration(cfg.ProviderTimeout)
+	fetchTimeout, _ := time.ParseDuration(cfg.ProviderFetchTimeout)
+	maxPayload, _ := strconv.ParseInt(cfg.ProviderMaxPayloadBytes, 10, 64)
+	defaultReadingTime, _ := strconv.Atoi(cfg.ProviderDefaultReadingTime)
+	pageSize, _ := strconv.Atoi(cfg.ProviderPageSize)
+	maxPages, _ := strconv.Atoi(cfg.ProviderMaxPages)
+	pageDelay, _ := time.ParseDuration(cfg.ProviderPageDelay)
+	retryAttempts, _ := strconv.Atoi(cfg.ProviderRetryMaxAttempts)
+	retryBase, _ := time.ParseDuration(cfg.ProviderRetryBaseDelay)
+	retryMax, _ := time.ParseDuration(cfg.ProviderRetryMaxDelay)
+	retryBudget, _ := time.ParseDuration(cfg.ProviderRetryBudget)
+	retry := infraproviders.RetryPolicy{MaxAttempts: retryAttempts, BaseDelay: retryBase, MaxDelay: retryMax, Budget: retryBudget}
 	factory := infraproviders.NewProviderFactory()
-	jsonProvider := infraproviders.NewJSONProvider(cfg.Provider1BaseURL, providerTimeout)
-	xmlProvider := infraproviders.NewXMLProvider(cfg.Provider2BaseURL, providerTimeout)
-	factory.RegisterProvider(jsonProvider)
-	factory.RegisterProvider(xmlProvider)
-	rateLimiter := ratelimiter.NewRedisLimiter(redisClient, cfg.RateLimitEnabled == "true")
-	providerSvc := &services.ProviderService{
+	registry := &services.ProviderRegistry{
+		Repo:    postgres.NewProviderRepository(dbPool),
 		Factory: factory,
-		Limiter: rateLimiter,
+		Builder: infraproviders.Builder{Timeout: providerTimeout, PageSize: pageSize, MaxPages: maxPages, PageDelay: pageDelay, Retry: retry, MaxPayloadBytes: maxPayload, DefaultReadingTime: defaultReadingTime},
 		Logger:  log,
-		Timeout: providerTimeout,
 	}
+	// Providers configured through the environment are reconciled into the registry
+	seed := []entities.Provider{
+		{ID: "provider1", Kind: entities.ProviderKindJSON, BaseURL: cfg.Provider1BaseURL, Enabled: true},
+		{ID: "provider2", Kind: entities.ProviderKindXML, BaseURL: cfg.Provider2BaseURL, Enabled: true},
+	}
+	infraproviders.SecretsDir = cfg.ProviderSecretsDir
+	for i, raw := range []string{cfg.Provider1Transport, cfg.Provider2Transport} {
+		if raw == "" {
+			continue
+		}
+		if _, err := infraproviders.ParseTransportConfig([]byte(raw)); err != nil {
+			log.Fatal("invalid provider transport", zap.String("provider_id", seed[i].ID), zap.Error(err))
+		}
+		seed[i].Transport = json.RawMessage(raw)
+	}
+	if cfg.ProviderDefinitionsPath != "" {
+		defs, err := infraproviders.LoadProviderDefinitions(cfg.ProviderDefinitionsPath)
+		if err != nil {
+			log.Fatal("failed to load provider definitions", zap.Error(err))
+		}
+		for _, def := range defs {
+			raw, _ := json.Marshal(def)
+			seed = append(seed, entities.Provider{ID: def.ID, Kind: entities.ProviderKindGeneric, Enabled: true, Definition: raw})
+		}
+	}
+	feeds, err := infraproviders.ParseFeedSources(cfg.FeedURLs)
+	if err != nil {
+		log.Fatal("invalid FEED_URLS", zap.Error(err))
+	}
+	for _, f := range feeds {
+		seed = append(seed, entities.Provider{ID: f.ID, Kind: entities.ProviderKindFeed, BaseURL: f.URL, Enabled: true})
+	}
+	var fileProvider *infraproviders.FileProvider
+	if cfg.ImportPath != "" {
+		// This instance only watches the path for changes; syncs go through the registry
+		fileProvider = infraproviders.NewFileProvider(cfg.ImportProviderID, cfg.ImportPath)
+		seed = append(seed, entities.Provider{ID: cfg.ImportProviderID, Kind: entities.ProviderKindFile, BaseURL: cfg.ImportPath, Enabled: true})
+	}
+	if err := registry.Seed(context.Background(), seed); err != nil {
+		log.Fatal("failed to seed provider registry", zap.Error(err))
+	}
+	if err := registry.Reload(context.Background()); err != nil {
+		log.Fatal("failed to load provider registry", zap.Error(err))
+	}
+	rateLimiter := ratelimiter.NewRedisLimiter(redisClient, cfg.RateLimitEnabled == "true")
+	if fetchTimeout > 0 {
+		// An in-flight slot outlives the longest fetch, so a crashed instance's slots expire
+		rateLimiter.Lease = fetchTimeout + time.Minute
+	}
+	if redisHealth != nil {
+		rateLimiter.Health = redisHealth
+		rateLimiter.Fallback = ratelimiter.NewMemoryLimiter()
+	}
+	rateLimitWait, _ := time.ParseDuration(cfg.RateLimitMaxWait)
+	maxInFlight, _ := strconv.Atoi(cfg.ProviderMaxInFlight)
+	cbWindow, _ := time.ParseDuration(cfg.CircuitBreakerWindow)
+	cbMinRequests, _ := strconv.Atoi(cfg.CircuitBreakerMinRequests)
+	cbFailureRate, _ := strconv.ParseFloat(cfg.CircuitBreakerFailureRate, 64)
+	cbConsecutive, _ := strconv.Atoi(cfg.CircuitBreakerConsecutiveFailures)
+	cbCoolDown, _ := time.ParseDuration(cfg.CircuitBreakerCoolDown)
+	breaker := circuitbreaker.NewRedisBreaker(redisClient, cfg.CircuitBreakerEnabled == "true", circuitbreaker.Settings{
+		Window:              cbWindow,
+		MinRequests:         cbMinRequests,
+		FailureRate:         cbFailureRate,
+		ConsecutiveFailures: cbConsecutive,
+		CoolDown:            cbCoolDown,
+	})
+	breaker.Health = redisHealth
+	providerSvc := &services.ProviderService{
+		Factory:          factory,
+		Limiter:          rateLimiter,
+		MaxRateLimitWait: rateLimitWait,
+		MaxInFlight:      maxInFlight,
+		Breaker:          breaker,
+		Logger:           log,
+		Timeout:          fetchTimeout,
+	}
+	healthHandler := handlers.NewHealthHandler(dbPool, redisClient, appStart, version, log, providerSvc, redisHealth)
+	router.GET("/health", healthHandler)
 
 	// Scoring services wiring
 	// Parse scoring configs
@@ -116,6 +232,8 @@ func main() {
 	fresh1w, _ := strconv.ParseFloat(cfg.Freshness1Week, 64)
 	fresh1m, _ := strconv.ParseFloat(cfg.Freshness1Month, 64)
 	fresh3m, _ := strconv.ParseFloat(cfg.Freshness3Months, 64)
+	watchWeight, _ := strconv.ParseFloat(cfg.WatchTimeWeight, 64)
+	commentWeight, _ := strconv.ParseFloat(cfg.CommentWeight, 64)
 	engine := &scoring.ScoringEngine{
 		VideoTypeMultiplier: videoMul,
 		TextTypeMultiplier:  textMul,
@@ -124,6 +242,8 @@ func main() {
 			WithinOneMonthScore:    fresh1m,
 			WithinThreeMonthsScore: fresh3m,
 		},
+		WatchTimeWeight: watchWeight,
+		CommentWeight:   commentWeight,
 	}
 	scoreCalc := &services.ScoreCalculatorService{
 		Contents: postgres.NewContentRepository(dbPool),
@@ -136,6 +256,10 @@ func main() {
 		recalcEvery, _ := time.ParseDuration(cfg.ScoreRecalcInterval)
 		batchSize, _ := strconv.Atoi(cfg.ScoreBatchSize)
 		job := jobs.NewScoreRecalculationJob(log, postgres.NewContentRepository(dbPool), scoreCalc, batchSize, recalcEvery)
+		if elector != nil {
+			job.Leader = elector.Lease("score-recalculation")
+			job.Lock = postgres.NewAdvisoryLock(dbPool, "score-recalculation")
+		}
 		job.Start()
 		defer job.Stop()
 	}
@@ -145,6 +269,7 @@ func main() {
 	thAbsViews, _ := strconv.Atoi(cfg.MetricsChangeThresholdAbsViews)
 	thAbsLikes, _ := strconv.Atoi(cfg.MetricsChangeThresholdAbsLikes)
 	thAbsReac, _ := strconv.Atoi(cfg.MetricsChangeThresholdAbsReactions)
+	thAbsComments, _ := strconv.Atoi(cfg.MetricsChangeThresholdAbsComments)
 	syncSvc := &services.ContentSyncService{
 		Logger:         log,
 		Factory:        factory,
@@ -153,25 +278,64 @@ func main() {
 		Metrics:        postgres.NewContentMetricsRepository(dbPool),
 		ScoreCalc:      scoreCalc,
 		HistoryRepo:    postgres.NewSyncHistoryRepository(dbPool),
-		Thresholds:     services.MetricsThresholds{Percent: thPercent, AbsViews: thAbsViews, AbsLikes: thAbsLikes, AbsReactions: thAbsReac},
+		TagRepo:        postgres.NewTagRepository(dbPool),
+		CursorRepo:     postgres.NewSyncCursorRepository(dbPool),
+		Quarantine:     postgres.NewQuarantineRepository(dbPool),
+		Thresholds:     services.MetricsThresholds{Percent: thPercent, AbsViews: thAbsViews, AbsLikes: thAbsLikes, AbsReactions: thAbsReac, AbsComments: thAbsComments},
+	}
+	syncSvc.RemovalMisses, _ = strconv.Atoi(cfg.ContentRemovalMisses)
+	syncSvc.Concurrency, _ = strconv.Atoi(cfg.MaxConcurrentJobs)
+	syncSvc.ProviderTimeout, _ = time.ParseDuration(cfg.ContentSyncProviderTimeout)
+	if syncBatch, _ := strconv.Atoi(cfg.ContentSyncBatchSize); syncBatch > 0 {
+		syncSvc.Batch = postgres.NewContentBatchRepository(dbPool)
+		syncSvc.BatchSize = syncBatch
 	}
 	if cfg.ContentSyncEnabled == "true" {
 		syncEvery, _ := time.ParseDuration(cfg.ContentSyncInterval)
-		retryCnt, _ := strconv.Atoi(cfg.ContentSyncRetryCount)
-		retryDelay, _ := time.ParseDuration(cfg.ContentSyncRetryDelay)
-		sjob := jobs.NewContentSyncJob(log, syncSvc, syncEvery, true, retryCnt, retryDelay)
+		jobTimeout, _ := time.ParseDuration(cfg.JobTimeout)
+		sjob := jobs.NewContentSyncJob(log, syncSvc, syncEvery, true, jobTimeout)
+		sjob.Schedule = registry
+		if elector != nil {
+			sjob.Leader = elector.Lease("content-sync")
+			sjob.Lock = postgres.NewAdvisoryLock(dbPool, "content-sync")
+		}
 		sjob.Start()
 		defer sjob.Stop()
 	}
 
+	// Provider health checks and optional background monitor
+	healthTimeout, _ := time.ParseDuration(cfg.ProviderHealthTimeout)
+	healthTTL, _ := time.ParseDuration(cfg.ProviderHealthCacheTTL)
+	providerHealth := &services.ProviderHealthService{
+		Factory:     factory,
+		HistoryRepo: postgres.NewSyncHistoryRepository(dbPool),
+		Logger:      log,
+		Timeout:     healthTimeout,
+		Cache:       cacheStore,
+		CacheTTL:    healthTTL,
+		Limits:      providerSvc,
+	}
+	if healthEvery, _ := time.ParseDuration(cfg.ProviderHealthInterval); healthEvery > 0 {
+		hjob := jobs.NewProviderHealthJob(log, providerHealth, healthEvery)
+		if elector != nil {
+			hjob.Leader = elector.Lease("provider-health")
+		}
+		hjob.Start()
+		defer hjob.Stop()
+	}
+
 	// Admin API (secured)
 	jobMgr := jobs.NewJobManager()
 	adminHandlers := &handlers.AdminHandlers{
-		Logger:    log,
-		Config:    cfg,
-		SyncSvc:   syncSvc,
-		ScoreCalc: scoreCalc,
-		JobMgr:    jobMgr,
+		Logger:      log,
+		Config:      cfg,
+		SyncSvc:     syncSvc,
+		ScoreCalc:   scoreCalc,
+		JobMgr:      jobMgr,
+		ProviderSvc: providerSvc,
+		HealthSvc:   providerHealth,
+		Registry:    registry,
+		Elector:     elector,
 	}
 	handlers.RegisterAdminRoutes(router, adminHandlers)
 
@@ -182,14 +346,34 @@ func main() {
 	searchSvc := &services.ContentSearchService{
 		Repo:            postgres.NewContentRepository(dbPool),
 		HistoryRepo:     postgres.NewSyncHistoryRepository(dbPool),
+		TagRepo:         postgres.NewTagRepository(dbPool),
 		DefaultPageSize: defPage,
 		MaxPageSize:     maxPage,
-		CacheClient:     redisClient,
+		Cache:           cacheStore,
 		CacheEnabled:    cfg.SearchCacheEnabled == "true",
 		CacheTTL:        searchCacheTTL,
 	}
 	handlers.RegisterContentRoutes(router, searchSvc, defPage, maxPage)
 
+	// Push ingestion, enabled for providers with a signing secret
+	ingestSecrets, err := services.ParseIngestSecrets(cfg.IngestSecrets)
+	if err != nil {
+		log.Fatal("invalid INGEST_SECRETS", zap.Error(err))
+	}
+	if len(ingestSecrets) > 0 {
+		ingestSkew, _ := time.ParseDuration(cfg.IngestMaxSkew)
+		ingestMaxBody, _ := strconv.ParseInt(cfg.IngestMaxBodyBytes, 10, 64)
+		ingestMaxItems, _ := strconv.Atoi(cfg.IngestMaxItems)
+		ingestSvc := &services.IngestService{
+			Sync:       syncSvc,
+			Deliveries: postgres.NewIngestDeliveryRepository(dbPool),
+			Secrets:    ingestSecrets,
+			MaxSkew:    ingestSkew,
+			Logger:     log,
+		}
+		handlers.RegisterIngestRoutes(router, ingestSvc, ingestMaxBody, ingestMaxItems)
+	}
+
 	addr := ":" + cfg.APIPort
 	if port := os.Getenv("PORT"); port != "" {
 		addr = ":" + port
@@ -201,8 +385,35 @@ func main() {
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
+	// Stop accepting requests on SIGINT/SIGTERM; deferred job Stop calls then cancel in-flight syncs
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if redisHealth != nil {
+		go redisHealth.Watch(sigCtx, redisProbeEvery)
+	}
+	if reloadEvery, _ := time.ParseDuration(cfg.ProviderRegistryReloadInterval); reloadEvery > 0 {
+		go registry.Watch(sigCtx, reloadEvery)
+	}
+	if watchEvery, _ := time.ParseDuration(cfg.ImportWatchInterval); fileProvider != nil && watchEvery > 0 {
+		go fileProvider.Watch(sigCtx, watchEvery, func() {
+			if _, err := syncSvc.SyncProvider(sigCtx, fileProvider.GetProviderID()); err != nil {
+				log.Error("import sync failed", zap.Error(err))
+			}
+		})
+	}
+	go func() {
+		<-sigCtx.Done()
+		log.Info("shutdown signal received")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Warn("server shutdown error", zap.Error(err))
+		}
+	}()
+
 	log.Info("API starting", zap.String("addr", addr))
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatal("server error", zap.Error(err))
 	}
+	log.Info("API stopped")
 }
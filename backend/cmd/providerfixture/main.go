@@ -0,0 +1,146 @@
+// Command providerfixture records provider HTTP exchanges into fixture files and
+// checks the mapped output of a fixture against its golden file.
+//
+//	providerfixture record -kind json -id provider1 -base-url http://localhost:8080/mock/provider1 \
+//	    -fixture testdata/provider1.fixture.json -golden testdata/provider1.golden.json
+//	providerfixture check -fixture testdata/provider1.fixture.json -golden testdata/provider1.golden.json [-update]
+//
+// record fetches the provider over the network and writes both files; check
+// replays the fixture offline and exits with status 1 when the mapping differs.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/infrastructure/providers"
+	"search_engine/internal/infrastructure/providers/replay"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "record":
+		err = record(os.Args[2:])
+	case "check":
+		err = check(os.Args[2:])
+	default:
+		usage()
+	}
+	if errors.Is(err, errDiff) {
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "providerfixture:", err)
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: providerfixture record|check [flags]; run with -h for the flags of a command")
+	os.Exit(2)
+}
+
+var errDiff = errors.New("mapped output differs from the golden file")
+
+func record(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	kind := fs.String("kind", "", "provider kind: json, xml, feed or generic")
+	id := fs.String("id", "", "provider ID")
+	baseURL := fs.String("base-url", "", "provider base URL (the feed URL for kind feed)")
+	defPath := fs.String("definition", "", "generic provider definition file (YAML or JSON)")
+	transport := fs.String("transport", "", "auth/transport JSON; secrets are referenced by PROVIDER_SECRET_* env var or file")
+	secretsDir := fs.String("secrets-dir", os.Getenv("PROVIDER_SECRETS_DIR"), "directory secret files must be under")
+	pageSize := fs.Int("page-size", 0, "page size (0 = provider default)")
+	maxPages := fs.Int("max-pages", 0, "maximum pages to fetch (0 = provider default)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "budget for the whole fetch")
+	fixturePath := fs.String("fixture", "", "fixture file to write")
+	goldenPath := fs.String("golden", "", "golden file to write (optional)")
+	_ = fs.Parse(args)
+	if *kind == "" || *id == "" || *fixturePath == "" {
+		return errors.New("record needs -kind, -id and -fixture")
+	}
+	providers.SecretsDir = *secretsDir
+	src := replay.Source{ProviderID: *id, Kind: entities.ProviderKind(*kind), BaseURL: *baseURL, PageSize: *pageSize, MaxPages: *maxPages}
+	if *defPath != "" {
+		raw, err := os.ReadFile(*defPath)
+		if err != nil {
+			return err
+		}
+		def, err := providers.ParseProviderDefinition(raw)
+		if err != nil {
+			return err
+		}
+		if src.Definition, err = json.Marshal(def); err != nil {
+			return err
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	f, items, err := replay.Record(ctx, src, json.RawMessage(*transport))
+	if err != nil {
+		return err
+	}
+	if err := f.Save(*fixturePath); err != nil {
+		return err
+	}
+	fmt.Printf("recorded %d exchanges, %d items -> %s\n", len(f.Exchanges), len(items), *fixturePath)
+	if *goldenPath != "" {
+		if err := replay.SaveGolden(*goldenPath, items); err != nil {
+			return err
+		}
+		fmt.Printf("golden -> %s\n", *goldenPath)
+	}
+	return nil
+}
+
+func check(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	fixturePath := fs.String("fixture", "", "fixture file to replay")
+	goldenPath := fs.String("golden", "", "golden file to compare with")
+	update := fs.Bool("update", false, "rewrite the golden file with the current output")
+	_ = fs.Parse(args)
+	if *fixturePath == "" || *goldenPath == "" {
+		return errors.New("check needs -fixture and -golden")
+	}
+	f, err := replay.Load(*fixturePath)
+	if err != nil {
+		return err
+	}
+	items, err := replay.Replay(context.Background(), f)
+	if err != nil {
+		return err
+	}
+	if *update {
+		if err := replay.SaveGolden(*goldenPath, items); err != nil {
+			return err
+		}
+		fmt.Printf("golden -> %s (%d items)\n", *goldenPath, len(items))
+		return nil
+	}
+	golden, err := os.ReadFile(*goldenPath)
+	if err != nil {
+		return err
+	}
+	diff, err := replay.Diff(golden, items)
+	if err != nil {
+		return err
+	}
+	if len(diff) == 0 {
+		fmt.Printf("ok: %d items match %s\n", len(items), *goldenPath)
+		return nil
+	}
+	for _, line := range diff {
+		fmt.Println(line)
+	}
+	return errDiff
+}
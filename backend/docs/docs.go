@@ -28,6 +28,9 @@ var doc = `{
           { "name":"q", "in":"query", "type":"string", "required": false, "description": "Search keyword (title, description)", "maxLength": 100, "example":"travel" },
           { "name":"type", "in":"query", "type":"string", "enum":["video","text"], "required": false, "description": "Content type filter", "example":"video" },
           { "name":"sort", "in":"query", "type":"string", "enum":["score_desc","score_asc","date_desc","date_asc"], "required": false, "description": "Sort order", "example":"score_desc" },
+          { "name":"tags", "in":"query", "type":"string", "required": false, "description": "Tag filter, comma-separated or repeated", "example":"devops,kubernetes" },
+          { "name":"tag_match", "in":"query", "type":"string", "enum":["any","all"], "required": false, "description": "Match any (default) or all of the tags", "example":"any" },
+          { "name":"lang", "in":"query", "type":"string", "enum":["en","tr"], "required": false, "description": "Language filter; matching uses that language's stemming. Contents stored before language detection count as en until their next sync", "example":"tr" },
           { "name":"page", "in":"query", "type":"integer", "required": false, "description": "Page number (default: 1)", "minimum": 1, "example":1 },
           { "name":"page_size", "in":"query", "type":"integer", "required": false, "description": "Items per page (default: 20, max: 100)", "minimum": 1, "maximum": 100, "example":20 }
         ],
@@ -60,7 +63,7 @@ var doc = `{
             "examples": { "application/json": {
               "success": true,
               "data": { "id":1, "title":"Amazing Video Title", "content_type":"video", "description":"Full description", "url":"https://example.com/v.mp4", "thumbnail_url":"https://example.com/t.jpg", "score":245.67, "published_at":"2024-11-01T10:00:00Z", "provider":"provider1",
-                "metrics": { "views":150000, "likes":5000, "reading_time": null, "reactions": null, "recalculated_at":"2024-11-16T10:30:00Z" } }
+                "metrics": { "views":150000, "likes":5000, "reading_time": null, "reactions": null, "duration_seconds": 930, "comments": 12, "recalculated_at":"2024-11-16T10:30:00Z" } }
             } }
           },
           "404": { "description":"Not Found", "schema": { "$ref":"#/definitions/APIContentResponse" },
@@ -69,6 +72,20 @@ var doc = `{
         }
       }
     },
+    "/api/v1/contents/tags": {
+      "get": {
+        "summary": "List tags with content counts",
+        "tags": ["Contents"],
+        "parameters": [
+          { "name":"limit", "in":"query", "type":"integer", "required": false, "description": "Max tags (default: 50, max: 200)", "minimum": 1, "maximum": 200, "example":50 }
+        ],
+        "responses": {
+          "200": { "description":"OK",
+            "examples": { "application/json": { "success": true, "data": [ { "name":"devops", "count":12 }, { "name":"kubernetes", "count":7 } ] } }
+          }
+        }
+      }
+    },
     "/api/v1/contents/stats": {
       "get": {
         "summary": "Get global statistics",
@@ -116,7 +133,7 @@ var doc = `{
         "security": [ { "ApiKeyAuth": [] } ],
         "parameters": [
           { "name":"provider_id", "in":"query", "type":"string", "required": false },
-          { "name":"status", "in":"query", "type":"string", "enum":["success","partial","failed","in_progress","skipped"], "required": false },
+          { "name":"status", "in":"query", "type":"string", "enum":["success","partial","failed","in_progress","skipped","rate_limited"], "required": false },
           { "name":"limit", "in":"query", "type":"integer", "required": false },
           { "name":"offset", "in":"query", "type":"integer", "required": false }
         ],
@@ -158,30 +175,221 @@ var doc = `{
             "examples": { "application/json": {
               "success": true,
               "data": [
-                { "provider_id":"provider1","provider_type":"json","base_url":"http://localhost:8080/mock/provider1","rate_limit":100,"status":"active","last_sync":"2024-11-16T08:00:00Z","last_sync_status":"success","content_count":650,"average_score":145.67 }
+                { "provider_id":"provider1","provider_type":"json","base_url":"http://localhost:8080/mock/provider1","rate_limit":100,"status":"active","last_sync":"2024-11-16T08:00:00Z","last_sync_status":"success","content_count":650,"average_score":145.67,"circuit":{ "provider_id":"provider1","state":"closed","requests":2,"failures":0 },"kind":"json","enabled":true }
               ]
             } }
           },
           "401": { "description":"Unauthorized" }
         }
+      },
+      "post": {
+        "summary": "Add a provider",
+        "description": "Store a provider in the registry and register it without a restart.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [ { "name":"provider", "in":"body", "required": true, "schema": { "$ref":"#/definitions/ProviderConfig" } } ],
+        "responses": {
+          "201": { "description":"Created", "schema": { "$ref":"#/definitions/ProviderConfig" },
+            "examples": { "application/json": { "success": true, "data": { "id":"blog","kind":"feed","base_url":"https://blog.example.com/rss","enabled":true,"registered":true,"source":"admin","rate_limit_per_minute":0,"timeout":"","sync_interval":"1h","created_at":"2024-11-16T10:00:00Z","updated_at":"2024-11-16T10:00:00Z" } } }
+          },
+          "400": { "description":"Invalid provider configuration" },
+          "401": { "description":"Unauthorized" },
+          "409": { "description":"Provider already exists" }
+        }
+      }
+    },
+    "/api/v1/admin/providers/{id}": {
+      "get": {
+        "summary": "Get a provider configuration",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [ { "name":"id", "in":"path", "required": true, "type":"string" } ],
+        "responses": {
+          "200": { "description":"OK", "schema": { "$ref":"#/definitions/ProviderConfig" } },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Unknown provider" }
+        }
+      },
+      "put": {
+        "summary": "Reconfigure a provider",
+        "description": "Fields left out keep their stored value; the provider is rebuilt without a restart. Changing kind, base_url, definition or transport of an env row makes it an admin row.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [
+          { "name":"id", "in":"path", "required": true, "type":"string" },
+          { "name":"provider", "in":"body", "required": true, "schema": { "$ref":"#/definitions/ProviderConfig" } }
+        ],
+        "responses": {
+          "200": { "description":"OK", "schema": { "$ref":"#/definitions/ProviderConfig" } },
+          "400": { "description":"Invalid provider configuration" },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Unknown provider" }
+        }
+      },
+      "delete": {
+        "summary": "Remove a provider",
+        "description": "Stored contents and sync history are kept. Providers configured through the environment cannot be removed.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [ { "name":"id", "in":"path", "required": true, "type":"string" } ],
+        "responses": {
+          "200": { "description":"OK" },
+          "401": { "description":"Unauthorized" },
+          "403": { "description":"Provider is configured through the environment" },
+          "404": { "description":"Unknown provider" }
+        }
+      }
+    },
+    "/api/v1/admin/providers/{id}/pause": {
+      "post": {
+        "summary": "Pause a provider",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [ { "name":"id", "in":"path", "required": true, "type":"string" } ],
+        "responses": {
+          "200": { "description":"OK", "schema": { "$ref":"#/definitions/ProviderConfig" } },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Unknown provider" }
+        }
+      }
+    },
+    "/api/v1/admin/providers/{id}/resume": {
+      "post": {
+        "summary": "Resume a provider",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [ { "name":"id", "in":"path", "required": true, "type":"string" } ],
+        "responses": {
+          "200": { "description":"OK", "schema": { "$ref":"#/definitions/ProviderConfig" } },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Unknown provider" }
+        }
+      }
+    },
+    "/api/v1/admin/providers/{id}/circuit/reset": {
+      "post": {
+        "summary": "Reset a provider circuit",
+        "description": "Close the provider's circuit breaker and clear its failure counters.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [ { "name":"id", "in":"path", "required": true, "type":"string" } ],
+        "responses": {
+          "200": { "description":"Circuit closed",
+            "examples": { "application/json": { "success": true, "data": { "provider_id":"provider2","state":"closed","requests":0,"failures":0 } } }
+          },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Unknown provider or breaker not configured" }
+        }
       }
     },
     "/api/v1/admin/providers/health-check": {
       "post": {
         "summary": "Check providers health",
+        "description": "Probe every registered provider with a single lightweight request and check that the payload parses. Results are cached; force=true probes again.",
         "tags": ["Admin"],
         "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [
+          { "name":"force", "in":"query", "required": false, "type":"boolean", "default": false, "description":"Ignore cached results" }
+        ],
         "responses": {
           "200": { "description":"OK", "schema": { "type":"array", "items": { "$ref":"#/definitions/ProviderHealth" } },
             "examples": { "application/json": {
               "success": true,
-              "data": [ { "provider_id":"provider1","is_healthy":true,"response_time_ms":245,"status_code":200,"checked_at":"2024-11-16T10:40:00Z","error":null } ]
+              "data": [ { "provider_id":"provider1","status":"healthy","is_healthy":true,"response_time_ms":245,"status_code":200,"checked_at":"2024-11-16T10:40:00Z","error":null,"last_successful_sync":"2024-11-16T06:00:12Z" } ]
             } }
           },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Health checks not configured" }
+        }
+      }
+    },
+    "/api/v1/admin/providers/preview": {
+      "post": {
+        "summary": "Preview a provider definition",
+        "description": "Fetch the first page of a feed through a generic provider definition (JSON or YAML body) and return the mapped items. Nothing is registered or stored. Only http(s) URLs on public addresses are fetched unless PROVIDER_PREVIEW_ALLOW_PRIVATE=true. Definitions with a transport (auth, proxy, CA bundle) are rejected; register the provider instead. Preview requests take tokens from the rate limit of the definition's provider ID, like syncs and health probes.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "consumes": ["application/json", "application/yaml"],
+        "parameters": [
+          { "name":"definition", "in":"body", "required": true, "schema": { "type":"object" } }
+        ],
+        "responses": {
+          "200": { "description":"OK",
+            "examples": { "application/json": {
+              "success": true,
+              "data": { "provider_id":"provider3","total":150,"count":1,"items":[ { "provider_id":"provider3","provider_content_id":"v1","title":"Go Tutorial","content_type":"video","url":"https://example.com/video/v1","views":1000,"published_at":"2024-03-15T00:00:00Z","tags":["go"] } ] }
+            } }
+          },
+          "400": { "description":"Invalid definition" },
+          "401": { "description":"Unauthorized" },
+          "429": { "description":"The provider's rate limit refused the request" },
+          "500": { "description":"Provider request failed" }
+        }
+      }
+    },
+    "/api/v1/admin/quarantine": {
+      "get": {
+        "summary": "List quarantined items",
+        "description": "Items that failed validation during sync or ingest, with the raw provider payload and reasons.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [
+          { "name":"provider_id", "in":"query", "type":"string" },
+          { "name":"status", "in":"query", "type":"string", "enum":["pending","discarded","all"], "default":"pending" },
+          { "name":"limit", "in":"query", "type":"integer", "default": 50, "maximum": 200 },
+          { "name":"offset", "in":"query", "type":"integer", "default": 0 }
+        ],
+        "responses": {
+          "200": { "description":"OK", "schema": { "type":"array", "items": { "$ref":"#/definitions/QuarantinedItem" } } },
           "401": { "description":"Unauthorized" }
         }
       }
     },
+    "/api/v1/admin/quarantine/{id}": {
+      "get": {
+        "summary": "Get a quarantined item",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [ { "name":"id", "in":"path", "required": true, "type":"integer", "format":"int64" } ],
+        "responses": {
+          "200": { "description":"OK", "schema": { "$ref":"#/definitions/QuarantinedItem" } },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Unknown item" }
+        }
+      }
+    },
+    "/api/v1/admin/quarantine/{id}/retry": {
+      "post": {
+        "summary": "Retry a quarantined item",
+        "description": "Validate the item again, optionally replaced by a corrected item, and store it when it passes; it then leaves the quarantine.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [
+          { "name":"id", "in":"path", "required": true, "type":"integer", "format":"int64" },
+          { "name":"body", "in":"body", "required": false, "schema": { "type":"object", "properties": { "item": { "$ref":"#/definitions/ProviderContent" } } } }
+        ],
+        "responses": {
+          "200": { "description":"Item stored" },
+          "400": { "description":"The item still fails validation; details.reasons lists why" },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Unknown item" }
+        }
+      }
+    },
+    "/api/v1/admin/quarantine/{id}/discard": {
+      "post": {
+        "summary": "Discard a quarantined item",
+        "description": "Mark the item discarded; later syncs delivering the same item keep it discarded.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "parameters": [ { "name":"id", "in":"path", "required": true, "type":"integer", "format":"int64" } ],
+        "responses": {
+          "200": { "description":"OK", "schema": { "$ref":"#/definitions/QuarantinedItem" } },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Unknown item" }
+        }
+      }
+    },
     "/api/v1/admin/contents/{id}": {
       "delete": {
         "summary": "Soft delete a content",
@@ -218,6 +426,24 @@ var doc = `{
         }
       }
     },
+    "/api/v1/admin/leases": {
+      "get": {
+        "summary": "List job leases",
+        "description": "Scheduled jobs run only on the replica holding their Redis lease. Content sync and score recalculation runs also hold a Postgres advisory lock while they run. Lists each lease's owner, token and remaining TTL, and whether the answering replica holds it.",
+        "tags": ["Admin"],
+        "security": [ { "ApiKeyAuth": [] } ],
+        "responses": {
+          "200": { "description":"OK",
+            "examples": { "application/json": {
+              "success": true,
+              "data": { "instance_id":"api-1-3f2a9c1d", "leases": [ { "name":"content-sync","owner":"api-1-3f2a9c1d","token":7,"acquired_at":"2024-11-16T10:30:00Z","expires_in_ms":12000,"held":true } ] }
+            } }
+          },
+          "401": { "description":"Unauthorized" },
+          "404": { "description":"Leader election is not enabled" }
+        }
+      }
+    },
     "/api/v1/admin/jobs/{jobId}": {
       "get": {
         "summary": "Get job status",
@@ -237,6 +463,32 @@ var doc = `{
           "401": { "description":"Unauthorized" }
         }
       }
+    },
+    "/api/v1/ingest/{providerId}": {
+      "post": {
+        "summary": "Push a content batch",
+        "description": "Signed with the provider secret: X-Signature = sha256=hex(HMAC-SHA256(secret, X-Timestamp + \".\" + X-Delivery-ID + \".\" + body)). A delivery ID is applied once; redeliveries return the stored result.",
+        "tags": ["Ingest"],
+        "consumes": ["application/json"],
+        "parameters": [
+          { "name":"providerId", "in":"path", "type":"string", "required": true },
+          { "name":"X-Delivery-ID", "in":"header", "type":"string", "required": true, "maxLength": 100 },
+          { "name":"X-Timestamp", "in":"header", "type":"integer", "required": true, "description":"Unix seconds, within INGEST_MAX_SKEW of server time" },
+          { "name":"X-Signature", "in":"header", "type":"string", "required": true },
+          { "name":"body", "in":"body", "required": true, "schema": { "$ref":"#/definitions/IngestRequest" } }
+        ],
+        "responses": {
+          "200": { "description":"Batch applied, or the stored result of a redelivery",
+            "examples": { "application/json": {
+              "success": true,
+              "data": { "delivery_id":"d-20241116-001", "duplicate": false, "result": { "ProviderID":"partner1", "TotalFetched":2, "NewContents":1, "UpdatedContents":1, "SkippedContents":0, "FailedContents":0 } }
+            } }
+          },
+          "400": { "description":"Missing headers, invalid JSON, or a body or batch over the limits" },
+          "401": { "description":"Unknown provider, bad signature or stale timestamp" },
+          "409": { "description":"The same delivery ID is still being processed" }
+        }
+      }
     }
   },
   "definitions": {
@@ -268,7 +520,9 @@ var doc = `{
         "thumbnail_url": { "type":"string", "description":"Thumbnail image URL", "example":"https://example.com/thumb.jpg" },
         "score": { "type":"number", "format":"double", "description":"Computed ranking score (2 decimal precision)", "minimum": 0, "example": 245.67 },
         "published_at": { "type":"string", "format":"date-time", "description":"Original publish timestamp (UTC)", "example":"2024-11-01T10:00:00Z" },
-        "provider": { "type":"string", "description":"Provider identifier", "example":"provider1" }
+        "provider": { "type":"string", "description":"Provider identifier", "example":"provider1" },
+        "tags": { "type":"array", "items": { "type":"string" }, "description":"Normalized provider tags", "example":["devops","kubernetes"] },
+        "language": { "type":"string", "enum":["en","tr","und"], "description":"Detected or provider-supplied language; und when undetermined", "example":"en" }
       }
     },
     "MetricsDTO": {
@@ -278,6 +532,8 @@ var doc = `{
         "likes": { "type":"integer", "format":"int64", "description":"Like count (video only)", "minimum": 0, "example":5000 },
         "reading_time": { "type":"integer", "description":"Estimated reading time in minutes (text only)", "minimum": 0, "example": 8 },
         "reactions": { "type":"integer", "description":"Reaction count (text only)", "minimum": 0, "example": 250 },
+        "duration_seconds": { "type":"integer", "description":"Video length in seconds (video only)", "minimum": 0, "example": 930 },
+        "comments": { "type":"integer", "description":"Comment count", "minimum": 0, "example": 12 },
         "recalculated_at": { "type":"string", "format":"date-time", "description":"Last score recalculation time (UTC)", "example":"2024-11-16T10:30:00Z" }
       }
     },
@@ -343,8 +599,9 @@ var doc = `{
       "type": "object",
       "properties": {
         "provider_id": { "type":"string", "description":"Specific provider to sync (optional)", "maxLength": 50, "pattern": "^[a-zA-Z0-9_-]+$", "example":"provider1" },
-        "force": { "type":"boolean", "description":"Ignore caches if implemented (optional)" },
-        "async": { "type":"boolean", "description":"Run as async job (default true if enabled)" }
+        "force": { "type":"boolean", "description":"Fetch in full ignoring the stored cursor and rewrite every item's attributes, metrics and score regardless of thresholds (optional)" },
+        "async": { "type":"boolean", "description":"Run as async job (default true if enabled)" },
+        "stream": { "type":"boolean", "description":"Run synchronously and stream one sync result per line (NDJSON) as each provider finishes" }
       }
     },
     "SyncHistory": {
@@ -352,16 +609,29 @@ var doc = `{
       "properties": {
         "id": { "type":"integer", "format":"int64", "description":"History record ID" },
         "provider_id": { "type":"string", "description":"Provider identifier" },
-        "sync_status": { "type":"string", "enum":["success","partial","failed","in_progress","skipped"], "description":"Final status" },
+        "sync_status": { "type":"string", "enum":["success","partial","failed","in_progress","skipped","rate_limited"], "description":"Final status" },
         "total_fetched": { "type":"integer", "description":"Fetched items count" },
         "new_contents": { "type":"integer", "description":"Newly created items" },
         "updated_contents": { "type":"integer", "description":"Updated items" },
         "skipped_contents": { "type":"integer", "description":"Skipped items" },
         "failed_contents": { "type":"integer", "description":"Failed items" },
+        "quarantined_contents": { "type":"integer", "description":"Items that failed validation and were quarantined" },
+        "removed_contents": { "type":"integer", "description":"Stored items soft-deleted because the provider stopped returning them" },
+        "changed_contents": { "type":"integer", "description":"Updated items whose title, type, description, URLs or publish date changed" },
         "error_message": { "type":"string", "description":"Optional error" },
         "started_at": { "type":"string", "format":"date-time", "description":"Start time (UTC)" },
         "completed_at": { "type":"string", "format":"date-time", "description":"Completion time (UTC)" },
-        "duration_ms": { "type":"integer", "description":"Duration in milliseconds" }
+        "duration_ms": { "type":"integer", "description":"Duration in milliseconds" },
+        "attempts": { "type":"array", "description":"Provider HTTP requests of the run, retries included",
+          "items": { "type":"object", "properties": {
+            "url": { "type":"string" },
+            "attempt": { "type":"integer" },
+            "status_code": { "type":"integer" },
+            "error": { "type":"string" },
+            "started_at": { "type":"string", "format":"date-time" },
+            "duration_ms": { "type":"integer" },
+            "backoff_ms": { "type":"integer", "description":"Wait before the next attempt" }
+          } } }
       }
     },
     "SyncHistoryListResponse": {
@@ -414,18 +684,115 @@ var doc = `{
         "content_count": { "type":"integer", "format":"int64", "description":"Total contents", "minimum": 0, "example": 650 },
         "average_score": { "type":"number", "format":"double", "description":"Average final score", "minimum": 0, "example": 145.67 },
         "last_sync": { "type":"string", "format":"date-time", "description":"Last sync time (optional)" },
-        "last_sync_status": { "type":"string", "description":"Last sync status (optional)" }
+        "last_sync_status": { "type":"string", "description":"Last sync status (optional)" },
+        "kind": { "type":"string", "description":"Registry kind: json, xml, generic, feed or file" },
+        "enabled": { "type":"boolean", "description":"False while the provider is paused" }
+      }
+    },
+    "ProviderConfig": {
+      "type": "object",
+      "properties": {
+        "id": { "type":"string", "description":"Provider identifier", "example":"blog" },
+        "kind": { "type":"string", "enum":["json","xml","generic","feed","file"] },
+        "base_url": { "type":"string", "description":"Feed URL for kind feed, import path for kind file" },
+        "enabled": { "type":"boolean" },
+        "registered": { "type":"boolean", "description":"Whether the provider is active in this instance (read-only)" },
+        "source": { "type":"string", "enum":["env","admin"], "description":"env rows are reconciled with the environment on every start (read-only)" },
+        "rate_limit_per_minute": { "type":"integer", "minimum": 0, "description":"0 uses the kind's default" },
+        "timeout": { "type":"string", "description":"Request timeout; empty uses PROVIDER_TIMEOUT", "example":"10s" },
+        "sync_interval": { "type":"string", "description":"Time between scheduled syncs; empty uses CONTENT_SYNC_INTERVAL", "example":"1h" },
+        "definition": { "type":"object", "description":"Generic provider definition; required for kind generic" },
+        "transport": { "$ref":"#/definitions/ProviderTransport" },
+        "created_at": { "type":"string", "format":"date-time" },
+        "updated_at": { "type":"string", "format":"date-time" }
+      }
+    },
+    "ProviderTransport": {
+      "type": "object",
+      "description": "Auth and HTTP settings for kinds json, xml, feed and generic; null clears them. Secrets are referenced by environment variable or file and never stored.",
+      "properties": {
+        "auth": {
+          "type": "object",
+          "properties": {
+            "type": { "type":"string", "enum":["api_key","bearer","oauth2_client_credentials"] },
+            "header": { "type":"string", "description":"api_key header; defaults to X-API-Key" },
+            "query_param": { "type":"string", "description":"Sends the api_key as this query parameter instead of a header" },
+            "secret": { "$ref":"#/definitions/SecretRef" },
+            "token_url": { "type":"string", "description":"OAuth2 token endpoint" },
+            "client_id": { "type":"string" },
+            "client_secret": { "$ref":"#/definitions/SecretRef" },
+            "scopes": { "type":"array", "items": { "type":"string" } }
+          }
+        },
+        "headers": { "type":"object", "additionalProperties": { "type":"string" }, "description":"Static, non-secret request headers" },
+        "ca_bundle": { "type":"string", "description":"PEM file of CAs trusted in addition to the system roots" },
+        "proxy": { "type":"string", "description":"Outbound proxy URL without a password", "example":"http://proxy.internal:3128" },
+        "proxy_password": { "$ref":"#/definitions/SecretRef" }
+      }
+    },
+    "SecretRef": {
+      "type": "object",
+      "description": "Exactly one of env or file. env must start with PROVIDER_SECRET_; file must be under PROVIDER_SECRETS_DIR, relative paths are resolved against it",
+      "properties": {
+        "env": { "type":"string", "example":"PROVIDER_SECRET_PARTNER_API_KEY" },
+        "file": { "type":"string", "example":"/run/secrets/providers/partner" }
       }
     },
     "ProviderHealth": {
       "type": "object",
       "properties": {
         "provider_id": { "type":"string", "description":"Provider identifier", "example":"provider1" },
-        "is_healthy": { "type":"boolean", "description":"Provider health boolean", "example": true },
+        "status": { "type":"string", "enum":["healthy","unhealthy","unknown"], "description":"unknown for providers that cannot be checked without a full fetch or whose probe their rate limit refused", "example":"healthy" },
+        "is_healthy": { "type":"boolean", "description":"True only when the probe succeeded", "example": true },
         "response_time_ms": { "type":"integer", "description":"Response time in ms", "minimum": 0, "example": 245 },
-        "status_code": { "type":"integer", "description":"HTTP status code", "minimum": 100, "maximum": 599, "example": 200 },
+        "status_code": { "type":"integer", "description":"HTTP status code; 0 when no response was received", "minimum": 0, "maximum": 599, "example": 200 },
         "checked_at": { "type":"string", "format":"date-time", "description":"Check time (UTC)", "example":"2024-11-16T10:40:00Z" },
-        "error": { "type":"string", "description":"Error details if any" }
+        "error": { "type":"string", "description":"Error details if any" },
+        "last_successful_sync": { "type":"string", "format":"date-time", "description":"Completion time of the latest successful or partial sync", "example":"2024-11-16T06:00:12Z" }
+      }
+    },
+    "ProviderContent": {
+      "type": "object",
+      "required": ["provider_content_id", "title", "content_type"],
+      "properties": {
+        "provider_content_id": { "type":"string", "example":"v42" },
+        "title": { "type":"string", "example":"Intro to Go" },
+        "content_type": { "type":"string", "enum":["video","text"] },
+        "description": { "type":"string" },
+        "url": { "type":"string" },
+        "thumbnail_url": { "type":"string" },
+        "views": { "type":"integer", "format":"int64" },
+        "likes": { "type":"integer", "format":"int64" },
+        "reading_time": { "type":"integer" },
+        "reactions": { "type":"integer" },
+        "duration_seconds": { "type":"integer" },
+        "comments": { "type":"integer" },
+        "published_at": { "type":"string", "format":"date-time" },
+        "tags": { "type":"array", "items": { "type":"string" } },
+        "language": { "type":"string", "description":"Optional language (en, tr); detected from title and description when absent" }
+      }
+    },
+    "QuarantinedItem": {
+      "type": "object",
+      "properties": {
+        "id": { "type":"integer", "format":"int64" },
+        "provider_id": { "type":"string", "example":"provider2" },
+        "item_key": { "type":"string", "description":"Provider content ID, or sha256 of the raw payload when the item has none" },
+        "provider_content_id": { "type":"string" },
+        "reasons": { "type":"array", "items": { "type":"string" }, "example": ["publication_date: invalid date \"15/03/2024\""] },
+        "raw_payload": { "type":"string", "description":"The item as the provider sent it" },
+        "item": { "$ref":"#/definitions/ProviderContent" },
+        "status": { "type":"string", "enum":["pending","discarded"] },
+        "occurrences": { "type":"integer", "description":"How many runs delivered the item" },
+        "first_seen_at": { "type":"string", "format":"date-time" },
+        "last_seen_at": { "type":"string", "format":"date-time" }
+      }
+    },
+    "IngestRequest": {
+      "type": "object",
+      "required": ["items"],
+      "properties": {
+        "items": { "type":"array", "maxItems": 1000, "items": { "$ref":"#/definitions/ProviderContent" } }
       }
     }
   }
@@ -9,6 +9,9 @@ type SearchRequest struct {
 	Keyword     string
 	ContentType string // "video" | "text" | ""
 	SortBy      string // "score_desc" | "score_asc" | "date_desc" | "date_asc"
+	Tags        []string
+	TagMatch    string // "any" | "all"
+	Language    string // "en" | "tr" | ""
 	Page        int
 	PageSize    int
 }
@@ -24,6 +27,9 @@ func (r *SearchRequest) Normalize(defaultPage, defaultPageSize, maxPageSize int)
 	if r.SortBy == "" {
 		r.SortBy = "score_desc"
 	}
+	if r.TagMatch == "" {
+		r.TagMatch = "any"
+	}
 }
 
 type ErrorDTO struct {
@@ -49,14 +55,18 @@ type ContentSummaryDTO struct {
 	Score        float64    `json:"score"`
 	PublishedAt  *time.Time `json:"published_at,omitempty"`
 	Provider     string     `json:"provider"`
+	Tags         []string   `json:"tags,omitempty"`
+	Language     string     `json:"language,omitempty"`
 }
 
 type MetricsDTO struct {
-	Views          *int64     `json:"views,omitempty"`
-	Likes          *int64     `json:"likes,omitempty"`
-	ReadingTime    *int       `json:"reading_time,omitempty"`
-	Reactions      *int       `json:"reactions,omitempty"`
-	RecalculatedAt *time.Time `json:"recalculated_at,omitempty"`
+	Views           *int64     `json:"views,omitempty"`
+	Likes           *int64     `json:"likes,omitempty"`
+	ReadingTime     *int       `json:"reading_time,omitempty"`
+	Reactions       *int       `json:"reactions,omitempty"`
+	DurationSeconds *int       `json:"duration_seconds,omitempty"`
+	Comments        *int       `json:"comments,omitempty"`
+	RecalculatedAt  *time.Time `json:"recalculated_at,omitempty"`
 }
 
 type ContentDetailDTO struct {
@@ -92,6 +102,16 @@ type StatsDTO struct {
 	Providers     []StatsProviderDTO `json:"providers"`
 }
 
+type TagDTO struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type TagsResponse struct {
+	Success bool     `json:"success"`
+	Data    []TagDTO `json:"data"`
+}
+
 type StatsResponse struct {
 	Success bool     `json:"success"`
 	Data    StatsDTO `json:"data"`
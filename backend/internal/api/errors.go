@@ -186,6 +186,12 @@ func ErrInternal(message string) *Error {
 	return NewError(ErrCodeInternalError, message)
 }
 
+func ErrProvider(providerID, reason string) *Error {
+	return NewError(ErrCodeProviderError, "Provider request failed").
+		WithDetails("provider_id", providerID).
+		WithDetails("reason", reason)
+}
+
 func ErrRateLimitExceeded() *Error {
 	return NewError(ErrCodeRateLimitError, "Rate limit exceeded")
 }
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"search_engine/internal/api"
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/repositories"
+	"search_engine/internal/infrastructure/services"
+)
+
+// providerBody is the admin representation of a registry row. On update, absent
+// fields keep their stored value.
+type providerBody struct {
+	ID                 *string         `json:"id"`
+	Kind               *string         `json:"kind"`
+	BaseURL            *string         `json:"base_url"`
+	Enabled            *bool           `json:"enabled"`
+	RateLimitPerMinute *int            `json:"rate_limit_per_minute"`
+	Timeout            *string         `json:"timeout"`
+	SyncInterval       *string         `json:"sync_interval"`
+	Definition         json.RawMessage `json:"definition"`
+	Transport          json.RawMessage `json:"transport"`
+}
+
+// apply copies the fields present in b onto row.
+func (b providerBody) apply(row *entities.Provider) *api.Error {
+	if b.Kind != nil {
+		row.Kind = entities.ProviderKind(strings.ToLower(strings.TrimSpace(*b.Kind)))
+	}
+	if b.BaseURL != nil {
+		row.BaseURL = strings.TrimSpace(*b.BaseURL)
+	}
+	if b.Enabled != nil {
+		row.Enabled = *b.Enabled
+	}
+	if b.RateLimitPerMinute != nil {
+		row.RateLimitPerMinute = *b.RateLimitPerMinute
+	}
+	if b.Timeout != nil {
+		d, err := parseOptionalDuration(*b.Timeout)
+		if err != nil {
+			return api.ErrInvalidParameter("timeout", "must be a duration such as 10s")
+		}
+		row.Timeout = d
+	}
+	if b.SyncInterval != nil {
+		d, err := parseOptionalDuration(*b.SyncInterval)
+		if err != nil {
+			return api.ErrInvalidParameter("sync_interval", "must be a duration such as 6h")
+		}
+		row.SyncInterval = d
+	}
+	if len(b.Definition) > 0 {
+		if string(b.Definition) == "null" {
+			row.Definition = nil
+		} else {
+			row.Definition = b.Definition
+		}
+	}
+	if len(b.Transport) > 0 {
+		if string(b.Transport) == "null" {
+			row.Transport = nil
+		} else {
+			row.Transport = b.Transport
+		}
+	}
+	return nil
+}
+
+// parseOptionalDuration treats an empty string as zero, meaning the default applies.
+func parseOptionalDuration(s string) (time.Duration, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func providerView(row entities.Provider, registered bool) gin.H {
+	out := gin.H{
+		"id":                    row.ID,
+		"kind":                  row.Kind,
+		"base_url":              row.BaseURL,
+		"enabled":               row.Enabled,
+		"registered":            registered,
+		"source":                row.Source,
+		"rate_limit_per_minute": row.RateLimitPerMinute,
+		"timeout":               "",
+		"sync_interval":         "",
+		"created_at":            row.CreatedAt,
+		"updated_at":            row.UpdatedAt,
+	}
+	if row.Timeout > 0 {
+		out["timeout"] = row.Timeout.String()
+	}
+	if row.SyncInterval > 0 {
+		out["sync_interval"] = row.SyncInterval.String()
+	}
+	if len(row.Definition) > 0 {
+		out["definition"] = row.Definition
+	}
+	// Transport only references secrets by env var or file, so it is safe to show
+	if len(row.Transport) > 0 {
+		out["transport"] = row.Transport
+	}
+	return out
+}
+
+// registerProviderRegistryRoutes adds CRUD over the providers table. Every change
+// reloads the provider factory so it takes effect without a restart.
+func registerProviderRegistryRoutes(grp *gin.RouterGroup, h *AdminHandlers) {
+	requireRegistry := func(c *gin.Context) bool {
+		if h.Registry == nil {
+			api.SendError(c, api.NewError(api.ErrCodeNotFound, "Provider registry is not configured"))
+			return false
+		}
+		return true
+	}
+	notFound := func(c *gin.Context, id string) {
+		api.SendError(c, api.NewError(api.ErrCodeNotFound, "Provider not found").WithDetails("provider_id", id))
+	}
+	registered := func(id string) bool {
+		if h.ProviderSvc == nil {
+			return false
+		}
+		_, err := h.ProviderSvc.Factory.GetProviderByID(id)
+		return err == nil
+	}
+	reload := func(c *gin.Context) {
+		if err := h.Registry.Reload(c.Request.Context()); err != nil {
+			h.Logger.Error("provider registry reload failed", zap.Error(err))
+		}
+	}
+	// save validates row, stores it through store and reloads the factory
+	save := func(c *gin.Context, row *entities.Provider, status int, store func() error) {
+		if err := h.Registry.Validate(*row); err != nil {
+			api.SendError(c, api.ErrInvalidParameter("provider", err.Error()))
+			return
+		}
+		if err := store(); err != nil {
+			switch {
+			case errors.Is(err, repositories.ErrProviderExists):
+				api.SendError(c, api.NewError(api.ErrCodeAlreadyExists, "Provider already exists").WithDetails("provider_id", row.ID))
+			case errors.Is(err, repositories.ErrProviderNotFound):
+				notFound(c, row.ID)
+			default:
+				h.Logger.Error("provider save failed", zap.String("provider_id", row.ID), zap.Error(err))
+				api.SendError(c, api.ErrInternal("Failed to save provider"))
+			}
+			return
+		}
+		reload(c)
+		c.JSON(status, gin.H{"success": true, "data": providerView(*row, registered(row.ID))})
+	}
+	// load fetches the row named by the :id parameter, answering 404 when absent
+	load := func(c *gin.Context) (*entities.Provider, bool) {
+		id := c.Param("id")
+		row, err := h.Registry.Repo.Get(c.Request.Context(), id)
+		if err != nil {
+			api.SendError(c, api.ErrInternal("Failed to load provider"))
+			return nil, false
+		}
+		if row == nil {
+			notFound(c, id)
+			return nil, false
+		}
+		return row, true
+	}
+
+	grp.POST("/providers", func(c *gin.Context) {
+		if !requireRegistry(c) {
+			return
+		}
+		var body providerBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			api.SendError(c, api.ErrInvalidParameter("body", "invalid JSON format"))
+			return
+		}
+		if body.ID == nil || strings.TrimSpace(*body.ID) == "" {
+			api.SendError(c, api.ErrMissingParameter("id"))
+			return
+		}
+		if body.Kind == nil {
+			api.SendError(c, api.ErrMissingParameter("kind"))
+			return
+		}
+		id := strings.TrimSpace(*body.ID)
+		if len(id) > 50 || strings.ContainsAny(id, " /?#") {
+			api.SendError(c, api.ErrInvalidParameter("id", "must be at most 50 characters without spaces, '/', '?' or '#'"))
+			return
+		}
+		row := entities.Provider{ID: id, Enabled: true, Source: entities.ProviderSourceAdmin}
+		if apiErr := body.apply(&row); apiErr != nil {
+			api.SendError(c, apiErr)
+			return
+		}
+		save(c, &row, http.StatusCreated, func() error { return h.Registry.Repo.Create(c.Request.Context(), &row) })
+	})
+
+	grp.GET("/providers/:id", func(c *gin.Context) {
+		if !requireRegistry(c) {
+			return
+		}
+		row, ok := load(c)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": providerView(*row, registered(row.ID))})
+	})
+
+	grp.PUT("/providers/:id", func(c *gin.Context) {
+		if !requireRegistry(c) {
+			return
+		}
+		var body providerBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			api.SendError(c, api.ErrInvalidParameter("body", "invalid JSON format"))
+			return
+		}
+		row, ok := load(c)
+		if !ok {
+			return
+		}
+		if body.ID != nil && *body.ID != row.ID {
+			api.SendError(c, api.ErrInvalidParameter("id", "cannot be changed"))
+			return
+		}
+		before := *row
+		if apiErr := body.apply(row); apiErr != nil {
+			api.SendError(c, apiErr)
+			return
+		}
+		// Changing what the environment configures takes the row over from it
+		if len(services.EnvManagedDiff(before, *row)) > 0 {
+			row.Source = entities.ProviderSourceAdmin
+		}
+		save(c, row, http.StatusOK, func() error { return h.Registry.Repo.Update(c.Request.Context(), row) })
+	})
+
+	setEnabled := func(enabled bool) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if !requireRegistry(c) {
+				return
+			}
+			row, ok := load(c)
+			if !ok {
+				return
+			}
+			row.Enabled = enabled
+			save(c, row, http.StatusOK, func() error { return h.Registry.Repo.Update(c.Request.Context(), row) })
+		}
+	}
+	grp.POST("/providers/:id/pause", setEnabled(false))
+	grp.POST("/providers/:id/resume", setEnabled(true))
+
+	grp.DELETE("/providers/:id", func(c *gin.Context) {
+		if !requireRegistry(c) {
+			return
+		}
+		// Stored contents and sync history of the provider are kept
+		id := c.Param("id")
+		row, ok := load(c)
+		if !ok {
+			return
+		}
+		if row.Source == entities.ProviderSourceEnv {
+			// It would be seeded again on the next start
+			api.SendError(c, api.NewError(api.ErrCodeForbidden, "Provider is configured through the environment; pause it or remove it from the environment").WithDetails("provider_id", id))
+			return
+		}
+		if err := h.Registry.Repo.Delete(c.Request.Context(), id); err != nil {
+			if errors.Is(err, repositories.ErrProviderNotFound) {
+				notFound(c, id)
+				return
+			}
+			api.SendError(c, api.ErrInternal("Failed to delete provider"))
+			return
+		}
+		reload(c)
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"search_engine/internal/api"
+	"search_engine/internal/domain/entities"
+	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/infrastructure/services"
+)
+
+func quarantineView(it entities.QuarantinedItem) gin.H {
+	out := gin.H{
+		"id":                  it.ID,
+		"provider_id":         it.ProviderID,
+		"item_key":            it.ItemKey,
+		"provider_content_id": it.ProviderContentID,
+		"reasons":             it.Reasons,
+		"raw_payload":         it.RawPayload,
+		"status":              it.Status,
+		"occurrences":         it.Occurrences,
+		"first_seen_at":       it.FirstSeenAt,
+		"last_seen_at":        it.LastSeenAt,
+	}
+	if len(it.Item) > 0 {
+		out["item"] = it.Item
+	}
+	return out
+}
+
+// registerQuarantineRoutes adds review of items that failed validation: list,
+// retry (optionally with a corrected item) and discard.
+func registerQuarantineRoutes(grp *gin.RouterGroup, h *AdminHandlers) {
+	requireQuarantine := func(c *gin.Context) bool {
+		if h.SyncSvc == nil || h.SyncSvc.Quarantine == nil {
+			api.SendError(c, api.NewError(api.ErrCodeNotFound, "Quarantine is not configured"))
+			return false
+		}
+		return true
+	}
+	notFound := func(c *gin.Context) {
+		api.SendError(c, api.NewError(api.ErrCodeNotFound, "Quarantined item not found").WithDetails("id", c.Param("id")))
+	}
+	// itemID parses the :id parameter, answering 400 when it is not a number
+	itemID := func(c *gin.Context) (int64, bool) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || id <= 0 {
+			api.SendError(c, api.ErrInvalidParameter("id", "must be a positive integer"))
+			return 0, false
+		}
+		return id, true
+	}
+
+	grp.GET("/quarantine", func(c *gin.Context) {
+		if !requireQuarantine(c) {
+			return
+		}
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if limit <= 0 || limit > 200 {
+			limit = 50
+		}
+		if offset < 0 {
+			offset = 0
+		}
+		var pidPtr *string
+		if pid := c.Query("provider_id"); pid != "" {
+			pidPtr = &pid
+		}
+		var stPtr *entities.QuarantineStatus
+		switch st := entities.QuarantineStatus(c.DefaultQuery("status", string(entities.QuarantinePending))); st {
+		case entities.QuarantinePending, entities.QuarantineDiscarded:
+			stPtr = &st
+		case "all":
+		default:
+			api.SendError(c, api.ErrInvalidParameter("status", "must be pending, discarded or all"))
+			return
+		}
+		items, err := h.SyncSvc.Quarantine.List(c.Request.Context(), pidPtr, stPtr, limit, offset)
+		if err != nil {
+			h.Logger.Error("quarantine list failed", zap.Error(err))
+			api.SendError(c, api.ErrInternal("Failed to list quarantined items"))
+			return
+		}
+		total, _ := h.SyncSvc.Quarantine.Count(c.Request.Context(), pidPtr, stPtr)
+		data := make([]gin.H, 0, len(items))
+		for _, it := range items {
+			data = append(data, quarantineView(it))
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": data, "pagination": gin.H{"limit": limit, "offset": offset, "total": total}})
+	})
+
+	grp.GET("/quarantine/:id", func(c *gin.Context) {
+		if !requireQuarantine(c) {
+			return
+		}
+		id, ok := itemID(c)
+		if !ok {
+			return
+		}
+		it, err := h.SyncSvc.Quarantine.Get(c.Request.Context(), id)
+		if err != nil {
+			api.SendError(c, api.ErrInternal("Failed to load quarantined item"))
+			return
+		}
+		if it == nil {
+			notFound(c)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": quarantineView(*it)})
+	})
+
+	grp.POST("/quarantine/:id/retry", func(c *gin.Context) {
+		if !requireQuarantine(c) {
+			return
+		}
+		id, ok := itemID(c)
+		if !ok {
+			return
+		}
+		// The body is optional; an item replaces the stored one entirely
+		var body struct {
+			Item *domainp.ProviderContent `json:"item"`
+		}
+		if err := json.NewDecoder(c.Request.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			api.SendError(c, api.ErrInvalidParameter("body", "invalid JSON format"))
+			return
+		}
+		res, reasons, err := h.SyncSvc.RetryQuarantined(c.Request.Context(), id, body.Item)
+		if errors.Is(err, services.ErrQuarantinedItemNotFound) {
+			notFound(c)
+			return
+		}
+		if err != nil {
+			h.Logger.Error("quarantine retry failed", zap.Int64("id", id), zap.Error(err))
+			api.SendError(c, api.ErrInternal("Failed to store quarantined item"))
+			return
+		}
+		if len(reasons) > 0 {
+			api.SendError(c, api.NewError(api.ErrCodeInvalidRequest, "Item still fails validation").
+				WithDetails("id", c.Param("id")).
+				WithDetails("reasons", strings.Join(reasons, "; ")))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"id": id, "result": res}})
+	})
+
+	grp.POST("/quarantine/:id/discard", func(c *gin.Context) {
+		if !requireQuarantine(c) {
+			return
+		}
+		id, ok := itemID(c)
+		if !ok {
+			return
+		}
+		// Discarded items stay recorded so later syncs do not bring them back for review
+		it, err := h.SyncSvc.Quarantine.Get(c.Request.Context(), id)
+		if err != nil {
+			api.SendError(c, api.ErrInternal("Failed to load quarantined item"))
+			return
+		}
+		if it == nil {
+			notFound(c)
+			return
+		}
+		if err := h.SyncSvc.Quarantine.SetStatus(c.Request.Context(), id, entities.QuarantineDiscarded); err != nil {
+			api.SendError(c, api.ErrInternal("Failed to discard quarantined item"))
+			return
+		}
+		it.Status = entities.QuarantineDiscarded
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": quarantineView(*it)})
+	})
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,7 +18,11 @@ import (
 	"search_engine/internal/api"
 	"search_engine/internal/config"
 	"search_engine/internal/domain/entities"
+	"search_engine/internal/infrastructure/circuitbreaker"
 	"search_engine/internal/infrastructure/jobs"
+	"search_engine/internal/infrastructure/leader"
+	"search_engine/internal/infrastructure/providers"
+	"search_engine/internal/infrastructure/ratelimiter"
 	"search_engine/internal/infrastructure/services"
 	"search_engine/internal/middleware"
 )
@@ -26,6 +33,14 @@ type AdminHandlers struct {
 	SyncSvc   *services.ContentSyncService
 	ScoreCalc *services.ScoreCalculatorService
 	JobMgr    *jobs.JobManager
+	// ProviderSvc is optional; when set, provider listings include circuit breaker state
+	ProviderSvc *services.ProviderService
+	// HealthSvc is optional; when nil the health-check endpoint reports not found
+	HealthSvc *services.ProviderHealthService
+	// Registry is optional; when set, providers can be managed through the admin API
+	Registry *services.ProviderRegistry
+	// Elector is optional; when set, job lease ownership is listed by the admin API
+	Elector *leader.Elector
 }
 
 func RegisterAdminRoutes(router *gin.Engine, h *AdminHandlers) {
@@ -38,6 +53,7 @@ func RegisterAdminRoutes(router *gin.Engine, h *AdminHandlers) {
 			ProviderID *string `json:"provider_id"`
 			Force      bool    `json:"force"`
 			Async      *bool   `json:"async"`
+			Stream     bool    `json:"stream"`
 		}
 		if err := c.ShouldBindJSON(&body); err != nil {
 			api.SendError(c, api.ErrInvalidParameter("body", "invalid JSON format"))
@@ -48,6 +64,10 @@ func RegisterAdminRoutes(router *gin.Engine, h *AdminHandlers) {
 		if body.Async != nil {
 			doAsync = *body.Async
 		}
+		if body.Stream {
+			// Results are streamed as each provider finishes, so the run is synchronous
+			doAsync = false
+		}
 		if doAsync {
 			jobID := "sync-" + uuid.NewString()
 			j := h.JobMgr.CreateJob(jobID, "sync")
@@ -58,12 +78,22 @@ func RegisterAdminRoutes(router *gin.Engine, h *AdminHandlers) {
 			go func(providerID string) {
 				ctx, cancel := jobContext(h.Config.JobTimeout)
 				defer cancel()
+				if body.Force {
+					ctx = services.WithForce(ctx)
+				}
 				h.JobMgr.Update(j.ID, jobs.JobRunning, 0, nil)
 				var err error
 				if providerID != "" {
-					_, err = h.SyncSvc.SyncProvider(ctx, providerID)
+					var r services.SyncResult
+					r, err = h.SyncSvc.SyncProvider(ctx, providerID)
+					h.JobMgr.AddResult(j.ID, r, 100)
 				} else {
-					_, err = h.SyncSvc.SyncAllProviders(ctx)
+					ids := h.SyncSvc.ProviderIDs()
+					done := 0
+					h.SyncSvc.SyncProviders(ctx, ids, func(r services.SyncResult) {
+						done++
+						h.JobMgr.AddResult(j.ID, r, done*100/len(ids))
+					})
 				}
 				if err != nil {
 					msg := err.Error()
@@ -80,12 +110,33 @@ func RegisterAdminRoutes(router *gin.Engine, h *AdminHandlers) {
 			results any
 			err     error
 		)
+		ctx := c.Request.Context()
+		if body.Force {
+			ctx = services.WithForce(ctx)
+		}
+		if body.Stream {
+			ids := h.SyncSvc.ProviderIDs()
+			if body.ProviderID != nil && *body.ProviderID != "" {
+				ids = []string{*body.ProviderID}
+			}
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			enc := json.NewEncoder(c.Writer)
+			h.SyncSvc.SyncProviders(ctx, ids, func(r services.SyncResult) {
+				if err := enc.Encode(r); err != nil {
+					h.Logger.Warn("sync result stream write failed", zap.Error(err))
+					return
+				}
+				c.Writer.Flush()
+			})
+			return
+		}
 		if body.ProviderID != nil && *body.ProviderID != "" {
-			r, e := h.SyncSvc.SyncProvider(c.Request.Context(), *body.ProviderID)
+			r, e := h.SyncSvc.SyncProvider(ctx, *body.ProviderID)
 			results = []services.SyncResult{r}
 			err = e
 		} else {
-			r, e := h.SyncSvc.SyncAllProviders(c.Request.Context())
+			r, e := h.SyncSvc.SyncAllProviders(ctx)
 			results = r
 			err = e
 		}
@@ -183,6 +234,30 @@ func RegisterAdminRoutes(router *gin.Engine, h *AdminHandlers) {
 
 	grp.GET("/providers", func(c *gin.Context) {
 		byProvider, _ := h.SyncSvc.Contents.CountByProvider(c.Request.Context())
+		if byProvider == nil {
+			byProvider = map[string]int64{}
+		}
+		circuits := map[string]circuitbreaker.Status{}
+		if h.ProviderSvc != nil {
+			// Registered providers are listed even before they have any content
+			for _, st := range h.ProviderSvc.CircuitStatuses(c.Request.Context()) {
+				circuits[st.ProviderID] = st
+				if _, ok := byProvider[st.ProviderID]; !ok {
+					byProvider[st.ProviderID] = 0
+				}
+			}
+		}
+		// Paused and unbuildable registry rows are listed too, with their configuration
+		rows := map[string]entities.Provider{}
+		if h.Registry != nil {
+			list, _ := h.Registry.Repo.List(c.Request.Context())
+			for _, row := range list {
+				rows[row.ID] = row
+				if _, ok := byProvider[row.ID]; !ok {
+					byProvider[row.ID] = 0
+				}
+			}
+		}
 		providers := []gin.H{}
 		for pid, count := range byProvider {
 			avg, _ := h.SyncSvc.Contents.GetAverageScoreByProvider(c.Request.Context(), pid)
@@ -196,29 +271,103 @@ func RegisterAdminRoutes(router *gin.Engine, h *AdminHandlers) {
 				entry["last_sync"] = last.CompletedAt
 				entry["last_sync_status"] = last.SyncStatus
 			}
+			if st, ok := circuits[pid]; ok {
+				entry["circuit"] = st
+			}
+			if row, ok := rows[pid]; ok {
+				entry["kind"] = row.Kind
+				entry["enabled"] = row.Enabled
+			}
 			providers = append(providers, entry)
 		}
+		sort.Slice(providers, func(i, j int) bool {
+			return providers[i]["provider_id"].(string) < providers[j]["provider_id"].(string)
+		})
 		c.JSON(http.StatusOK, gin.H{"success": true, "data": providers})
 	})
 
+	grp.POST("/providers/:id/circuit/reset", func(c *gin.Context) {
+		if h.ProviderSvc == nil || h.ProviderSvc.Breaker == nil {
+			api.SendError(c, api.NewError(api.ErrCodeNotFound, "Circuit breaker is not configured"))
+			return
+		}
+		pid := c.Param("id")
+		if _, err := h.ProviderSvc.Factory.GetProviderByID(pid); err != nil {
+			api.SendError(c, api.NewError(api.ErrCodeNotFound, "Provider not found").WithDetails("provider_id", pid))
+			return
+		}
+		if err := h.ProviderSvc.Breaker.Reset(c.Request.Context(), pid); err != nil {
+			api.SendError(c, api.ErrInternal("Failed to reset circuit"))
+			return
+		}
+		st, _ := h.ProviderSvc.Breaker.Status(c.Request.Context(), pid)
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": st})
+	})
+
+	registerProviderRegistryRoutes(grp, h)
+	registerQuarantineRoutes(grp, h)
+
 	grp.POST("/providers/health-check", func(c *gin.Context) {
-		// For demo purposes, return healthy with 0ms; real impl would probe provider endpoints
-		byProvider, _ := h.SyncSvc.Contents.CountByProvider(c.Request.Context())
-		out := []gin.H{}
-		now := time.Now().UTC()
-		for pid := range byProvider {
-			out = append(out, gin.H{
-				"provider_id":      pid,
-				"is_healthy":       true,
-				"response_time_ms": 0,
-				"status_code":      200,
-				"checked_at":       now,
-				"error":            nil,
-			})
+		if h.HealthSvc == nil {
+			api.SendError(c, api.NewError(api.ErrCodeNotFound, "Provider health checks are not configured"))
+			return
+		}
+		// Cached results are served unless force=true asks for a fresh probe
+		force := c.Query("force") == "true"
+		var out []services.ProviderHealth
+		var err error
+		if force {
+			out, err = h.HealthSvc.Refresh(c.Request.Context())
+		} else {
+			out, err = h.HealthSvc.Check(c.Request.Context())
+		}
+		if err != nil {
+			api.SendError(c, api.ErrInternal("Failed to check provider health"))
+			return
 		}
 		c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
 	})
 
+	grp.POST("/providers/preview", func(c *gin.Context) {
+		// Body is a provider definition in JSON or YAML; nothing is registered or stored
+		raw, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+		if err != nil || len(raw) == 0 {
+			api.SendError(c, api.ErrMissingParameter("body"))
+			return
+		}
+		def, err := providers.ParseProviderDefinition(raw)
+		if err != nil {
+			api.SendError(c, api.ErrInvalidParameter("definition", err.Error()))
+			return
+		}
+		timeout, _ := time.ParseDuration(h.Config.ProviderTimeout)
+		p, err := providers.NewPreviewProvider(def, timeout, h.Config.ProviderPreviewAllowPrivate == "true")
+		if err != nil {
+			api.SendError(c, api.ErrInvalidParameter("definition", err.Error()))
+			return
+		}
+		ctx := c.Request.Context()
+		if h.ProviderSvc != nil {
+			// Previews draw on the rate limit of the provider ID they name
+			ctx = h.ProviderSvc.LimitRequests(ctx, def.ID, p.GetRateLimit().RequestsPerMinute)
+		}
+		items, total, err := p.Preview(ctx)
+		if errors.Is(err, ratelimiter.ErrRateLimited) {
+			api.SendError(c, api.ErrRateLimitExceeded().WithDetails("provider_id", def.ID))
+			return
+		}
+		if err != nil {
+			api.SendError(c, api.ErrProvider(def.ID, err.Error()))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
+			"provider_id": def.ID,
+			"total":       total,
+			"count":       len(items),
+			"items":       items,
+		}})
+	})
+
 	grp.DELETE("/contents/:id", func(c *gin.Context) {
 		idStr := c.Param("id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
@@ -255,6 +404,20 @@ func RegisterAdminRoutes(router *gin.Engine, h *AdminHandlers) {
 		})
 	})
 
+	grp.GET("/leases", func(c *gin.Context) {
+		if h.Elector == nil {
+			api.SendError(c, api.NewError(api.ErrCodeNotFound, "Leader election is not enabled"))
+			return
+		}
+		leases, err := h.Elector.Status(c.Request.Context())
+		if err != nil {
+			h.Logger.Error("lease status failed", zap.Error(err))
+			api.SendError(c, api.NewError(api.ErrCodeCacheError, "Lease status unavailable"))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"instance_id": h.Elector.ID, "leases": leases}})
+	})
+
 	grp.GET("/jobs/:jobId", func(c *gin.Context) {
 		id := c.Param("jobId")
 		if j, ok := h.JobMgr.Get(id); ok {
@@ -9,6 +9,7 @@ import (
 
 	"search_engine/internal/api"
 	"search_engine/internal/api/dto"
+	"search_engine/internal/infrastructure/language"
 	"search_engine/internal/infrastructure/services"
 )
 
@@ -18,6 +19,21 @@ func RegisterContentRoutes(router *gin.Engine, svc *services.ContentSearchServic
 		q := strings.TrimSpace(c.Query("q"))
 		ct := strings.TrimSpace(c.Query("type"))
 		sort := strings.TrimSpace(c.Query("sort"))
+		tagMatch := strings.ToLower(strings.TrimSpace(c.Query("tag_match")))
+		if tagMatch != "" && tagMatch != "any" && tagMatch != "all" {
+			api.SendError(c, api.ErrInvalidParameter("tag_match", "must be 'any' or 'all'"))
+			return
+		}
+		lang := strings.TrimSpace(c.Query("lang"))
+		if l := language.Normalize(lang); lang != "" && (l == "" || l == language.Undetermined) {
+			api.SendError(c, api.ErrInvalidParameter("lang", "must be 'en' or 'tr'"))
+			return
+		}
+		// tags accepts repeated params and comma-separated lists: tags=devops,kubernetes
+		var tags []string
+		for _, v := range c.QueryArray("tags") {
+			tags = append(tags, strings.Split(v, ",")...)
+		}
 		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 		pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
@@ -30,7 +46,7 @@ func RegisterContentRoutes(router *gin.Engine, svc *services.ContentSearchServic
 		}
 
 		req := dto.SearchRequest{
-			Keyword: q, ContentType: ct, SortBy: sort, Page: page, PageSize: pageSize,
+			Keyword: q, ContentType: ct, SortBy: sort, Tags: tags, TagMatch: tagMatch, Language: lang, Page: page, PageSize: pageSize,
 		}
 		req.Normalize(1, defaultPageSize, maxPageSize)
 		items, total, err := svc.SearchContents(c.Request.Context(), req)
@@ -47,6 +63,18 @@ func RegisterContentRoutes(router *gin.Engine, svc *services.ContentSearchServic
 			},
 		})
 	})
+	v1.GET("/tags", func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if limit <= 0 || limit > 200 {
+			limit = 50
+		}
+		tags, err := svc.ListTags(c.Request.Context(), limit)
+		if err != nil {
+			api.SendError(c, api.ErrInternal("Failed to retrieve tags"))
+			return
+		}
+		c.JSON(http.StatusOK, dto.TagsResponse{Success: true, Data: tags})
+	})
 	v1.GET("/:id", func(c *gin.Context) {
 		idStr := c.Param("id")
 		id, err := strconv.ParseInt(idStr, 10, 64)
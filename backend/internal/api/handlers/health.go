@@ -10,6 +10,9 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+
+	"search_engine/internal/infrastructure/cache"
+	"search_engine/internal/infrastructure/circuitbreaker"
 )
 
 type HealthStatus struct {
@@ -18,9 +21,16 @@ type HealthStatus struct {
 	UptimeSeconds float64                  `json:"uptime_seconds"`
 	Version       string                   `json:"version"`
 	Services      map[string]ServiceHealth `json:"services"`
+	Providers     []circuitbreaker.Status  `json:"providers,omitempty"`
+	Redis         *cache.RedisStatus       `json:"redis_status,omitempty"`
 	System        SystemInfo               `json:"system"`
 }
 
+// CircuitReporter exposes provider circuit breaker states to the health check.
+type CircuitReporter interface {
+	CircuitStatuses(ctx context.Context) []circuitbreaker.Status
+}
+
 type ServiceHealth struct {
 	Healthy      bool    `json:"healthy"`
 	ResponseTime float64 `json:"response_time_ms,omitempty"`
@@ -35,7 +45,11 @@ type SystemInfo struct {
 	NumCPU        int     `json:"num_cpu"`
 }
 
-func NewHealthHandler(db *pgxpool.Pool, redisClient *redis.Client, appStart time.Time, version string, logger *zap.Logger) gin.HandlerFunc {
+// NewHealthHandler reports dependency health. Open provider circuits mark the API
+// "degraded" but keep 200, since search still works without that provider; circuits may be nil.
+// When redisHealth is set, Redis is reported from its last probe and an outage only
+// marks the API "degraded", since caches and rate limits then run process-local.
+func NewHealthHandler(db *pgxpool.Pool, redisClient *redis.Client, appStart time.Time, version string, logger *zap.Logger, circuits CircuitReporter, redisHealth *cache.RedisHealth) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 		start := time.Now()
@@ -52,19 +66,39 @@ func NewHealthHandler(db *pgxpool.Pool, redisClient *redis.Client, appStart time
 		health.Services["postgres"] = checkPostgresHealth(ctx, db)
 
 		// Check Redis
-		health.Services["redis"] = checkRedisHealth(ctx, redisClient)
+		degraded := false
+		if redisHealth != nil {
+			st := redisHealth.Status()
+			health.Redis = &st
+			health.Services["redis"] = probedRedisHealth(st)
+			degraded = st.Mode == cache.ModeFallback
+		} else {
+			health.Services["redis"] = checkRedisHealth(ctx, redisClient)
+		}
 
 		// Check overall status
 		allHealthy := true
-		for _, service := range health.Services {
-			if !service.Healthy {
+		for name, service := range health.Services {
+			if !service.Healthy && !(name == "redis" && degraded) {
 				allHealthy = false
 				break
 			}
 		}
 
+		if circuits != nil {
+			health.Providers = circuits.CircuitStatuses(ctx)
+			for _, p := range health.Providers {
+				if p.State != circuitbreaker.StateClosed {
+					degraded = true
+				}
+			}
+		}
+
 		if allHealthy {
 			health.Status = "healthy"
+			if degraded {
+				health.Status = "degraded"
+			}
 			c.JSON(http.StatusOK, health)
 		} else {
 			health.Status = "unhealthy"
@@ -128,6 +162,20 @@ func checkRedisHealth(ctx context.Context, client *redis.Client) ServiceHealth {
 	}
 }
 
+// probedRedisHealth reports Redis from the health probe instead of pinging it, so
+// /health stays fast while Redis is unreachable.
+func probedRedisHealth(st cache.RedisStatus) ServiceHealth {
+	if st.Mode == cache.ModeFallback {
+		return ServiceHealth{
+			Healthy:      false,
+			ResponseTime: st.ProbeMs,
+			Error:        "connection_failed",
+			Message:      "using process-local fallback: " + st.LastError,
+		}
+	}
+	return ServiceHealth{Healthy: true, ResponseTime: st.ProbeMs, Message: "PONG"}
+}
+
 func getSystemInfo() SystemInfo {
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
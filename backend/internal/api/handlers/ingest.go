@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"search_engine/internal/api"
+	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/infrastructure/services"
+)
+
+// IngestRequest is the body of a pushed batch.
+type IngestRequest struct {
+	Items []domainp.ProviderContent `json:"items"`
+}
+
+// RegisterIngestRoutes adds the signed push endpoint. Bodies over maxBodyBytes
+// and batches over maxItems are rejected.
+func RegisterIngestRoutes(router *gin.Engine, svc *services.IngestService, maxBodyBytes int64, maxItems int) {
+	router.POST("/api/v1/ingest/:providerId", func(c *gin.Context) {
+		providerID := c.Param("providerId")
+		deliveryID := c.GetHeader("X-Delivery-ID")
+		if deliveryID == "" {
+			api.SendError(c, api.ErrMissingParameter("X-Delivery-ID"))
+			return
+		}
+		if !services.ValidDeliveryID(deliveryID) {
+			api.SendError(c, api.ErrInvalidParameter("X-Delivery-ID", "must be at most 100 characters without surrounding spaces"))
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBodyBytes+1))
+		if err != nil {
+			api.SendError(c, api.ErrInvalidParameter("body", "could not be read"))
+			return
+		}
+		if int64(len(body)) > maxBodyBytes {
+			api.SendError(c, api.ErrInvalidParameter("body", "exceeds the maximum size"))
+			return
+		}
+		// Authenticate before looking at the payload
+		if err := svc.Verify(providerID, deliveryID, c.GetHeader("X-Timestamp"), c.GetHeader("X-Signature"), body, time.Now()); err != nil {
+			svc.Logger.Warn("ingest request rejected", zap.String("provider", providerID), zap.String("delivery_id", deliveryID))
+			api.SendError(c, api.ErrUnauthorized())
+			return
+		}
+		var req IngestRequest
+		dec := json.NewDecoder(bytes.NewReader(body))
+		if err := dec.Decode(&req); err != nil {
+			api.SendError(c, api.ErrInvalidParameter("body", "invalid JSON format"))
+			return
+		}
+		if len(req.Items) == 0 {
+			api.SendError(c, api.ErrMissingParameter("items"))
+			return
+		}
+		if len(req.Items) > maxItems {
+			api.SendError(c, api.ErrInvalidParameter("items", "too many items in one batch"))
+			return
+		}
+		res, err := svc.Ingest(c.Request.Context(), providerID, deliveryID, req.Items)
+		if err != nil {
+			if errors.Is(err, services.ErrDeliveryInProgress) {
+				api.SendError(c, api.NewError(api.ErrCodeAlreadyExists, "Delivery is being processed").WithDetails("delivery_id", deliveryID))
+				return
+			}
+			svc.Logger.Error("ingest failed", zap.String("provider", providerID), zap.String("delivery_id", deliveryID), zap.Error(err))
+			api.SendError(c, api.ErrInternal("Failed to ingest batch"))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": res})
+	})
+}
@@ -23,8 +23,10 @@ type provider1File struct {
 			Duration    string `json:"duration,omitempty"`
 			ReadingTime int    `json:"reading_time,omitempty"`
 			Reactions   int    `json:"reactions,omitempty"`
+			Comments    int    `json:"comments,omitempty"`
 		} `json:"metrics"`
 		PublishedAt time.Time `json:"published_at"`
+		Tags        []string  `json:"tags,omitempty"`
 	} `json:"contents"`
 	Pagination struct {
 		Total   int `json:"total"`
@@ -43,7 +45,16 @@ func MockProvider1Handler(c *gin.Context) {
 			defer func() { _ = f.Close() }()
 			var raw provider1File
 			if err := json.NewDecoder(f).Decode(&raw); err == nil {
-				// Return the file content as-is (in the new format)
+				// Return the file content (in the new format), sliced when limit/offset are given
+				if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+					offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+					total := len(raw.Contents)
+					lo, hi := pageBounds(total, offset, limit)
+					raw.Contents = raw.Contents[lo:hi]
+					raw.Pagination.Total = total
+					raw.Pagination.Page = offset/limit + 1
+					raw.Pagination.PerPage = limit
+				}
 				c.JSON(http.StatusOK, raw)
 				return
 			}
@@ -66,6 +77,11 @@ func MockProvider1Handler(c *gin.Context) {
 	if limit > 50 {
 		limit = 50
 	}
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+	const syntheticTotal = 40
 
 	now := time.Now().UTC()
 	contents := make([]struct {
@@ -73,25 +89,27 @@ func MockProvider1Handler(c *gin.Context) {
 		Title   string `json:"title"`
 		Type    string `json:"type"`
 		Metrics struct {
-			Views     int64  `json:"views,omitempty"`
-			Likes     int64  `json:"likes,omitempty"`
-			Duration  string `json:"duration,omitempty"`
-			Reactions int    `json:"reactions,omitempty"`
+			Views       int64  `json:"views,omitempty"`
+			Likes       int64  `json:"likes,omitempty"`
+			Duration    string `json:"duration,omitempty"`
+			ReadingTime int    `json:"reading_time,omitempty"`
+			Reactions   int    `json:"reactions,omitempty"`
 		} `json:"metrics"`
 		PublishedAt time.Time `json:"published_at"`
 		Tags        []string  `json:"tags,omitempty"`
 	}, 0, limit)
 
-	for i := 0; i < limit && i < 40; i++ {
+	for i := offset; i < offset+limit && i < syntheticTotal; i++ {
 		item := struct {
 			ID      string `json:"id"`
 			Title   string `json:"title"`
 			Type    string `json:"type"`
 			Metrics struct {
-				Views     int64  `json:"views,omitempty"`
-				Likes     int64  `json:"likes,omitempty"`
-				Duration  string `json:"duration,omitempty"`
-				Reactions int    `json:"reactions,omitempty"`
+				Views       int64  `json:"views,omitempty"`
+				Likes       int64  `json:"likes,omitempty"`
+				Duration    string `json:"duration,omitempty"`
+				ReadingTime int    `json:"reading_time,omitempty"`
+				Reactions   int    `json:"reactions,omitempty"`
 			} `json:"metrics"`
 			PublishedAt time.Time `json:"published_at"`
 			Tags        []string  `json:"tags,omitempty"`
@@ -109,6 +127,7 @@ func MockProvider1Handler(c *gin.Context) {
 			item.ID = "a" + strconv.Itoa(100+i)
 			item.Title = "Sample Article " + strconv.Itoa(i)
 			item.Type = "article"
+			item.Metrics.ReadingTime = 5 + i%4
 			item.Metrics.Reactions = 100 + i*5
 			item.Tags = []string{"programming", "article"}
 		}
@@ -119,8 +138,8 @@ func MockProvider1Handler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"contents": contents,
 		"pagination": gin.H{
-			"total":    150,
-			"page":     1,
+			"total":    syntheticTotal,
+			"page":     offset/limit + 1,
 			"per_page": limit,
 		},
 	})
@@ -164,7 +183,8 @@ type provider2File struct {
 				Reactions   *int   `xml:"reactions"`
 				Comments    *int   `xml:"comments"`
 			} `xml:"stats"`
-			PublicationDate string `xml:"publication_date"`
+			PublicationDate string   `xml:"publication_date"`
+			Categories      []string `xml:"categories>category,omitempty"`
 		} `xml:"item"`
 	} `xml:"items"`
 	Meta struct {
@@ -184,7 +204,19 @@ func MockProvider2Handler(c *gin.Context) {
 			defer func() { _ = f.Close() }()
 			var raw provider2File
 			if err := xml.NewDecoder(f).Decode(&raw); err == nil {
-				// Return the file content as-is (in the new format)
+				// Return the file content (in the new format), sliced when page/size are given
+				if size, err := strconv.Atoi(c.Query("size")); err == nil && size > 0 {
+					page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+					if page <= 0 {
+						page = 1
+					}
+					total := len(raw.Items.Item)
+					lo, hi := pageBounds(total, (page-1)*size, size)
+					raw.Items.Item = raw.Items.Item[lo:hi]
+					raw.Meta.TotalCount = total
+					raw.Meta.CurrentPage = page
+					raw.Meta.ItemsPerPage = size
+				}
 				c.Header("Content-Type", "application/xml; charset=utf-8")
 				c.XML(http.StatusOK, raw)
 				return
@@ -206,6 +238,11 @@ func MockProvider2Handler(c *gin.Context) {
 	if size > 50 {
 		size = 50
 	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page <= 0 {
+		page = 1
+	}
+	const syntheticTotal = 20
 
 	now := time.Now().UTC()
 	type xmlItem struct {
@@ -218,12 +255,14 @@ func MockProvider2Handler(c *gin.Context) {
 			Duration    string `xml:"duration,omitempty"`
 			ReadingTime *int   `xml:"reading_time,omitempty"`
 			Reactions   *int   `xml:"reactions,omitempty"`
+			Comments    *int   `xml:"comments,omitempty"`
 		} `xml:"stats"`
-		PublicationDate string `xml:"publication_date"`
+		PublicationDate string   `xml:"publication_date"`
+		Categories      []string `xml:"categories>category,omitempty"`
 	}
 
 	items := make([]xmlItem, 0, size)
-	for i := 0; i < size && i < 20; i++ {
+	for i := (page - 1) * size; i < page*size && i < syntheticTotal; i++ {
 		item := xmlItem{}
 		if i%2 == 0 {
 			item.ID = "v" + strconv.Itoa(200+i)
@@ -234,14 +273,18 @@ func MockProvider2Handler(c *gin.Context) {
 			item.Stats.Views = &v
 			item.Stats.Likes = &l
 			item.Stats.Duration = "20:30"
+			item.Categories = []string{"devops", "containers"}
 		} else {
 			item.ID = "a" + strconv.Itoa(200+i)
 			item.Headline = "Sample Article " + strconv.Itoa(i)
 			item.Type = "article"
 			rt := 8
 			r := 300 + i*10
+			cm := 10 + i
 			item.Stats.ReadingTime = &rt
 			item.Stats.Reactions = &r
+			item.Stats.Comments = &cm
+			item.Categories = []string{"programming", "architecture"}
 		}
 		item.PublicationDate = now.Add(-time.Duration(i) * 24 * time.Hour).Format("2006-01-02")
 		items = append(items, item)
@@ -261,10 +304,25 @@ func MockProvider2Handler(c *gin.Context) {
 
 	response := feed{}
 	response.Items.Item = items
-	response.Meta.TotalCount = 75
-	response.Meta.CurrentPage = 1
+	response.Meta.TotalCount = syntheticTotal
+	response.Meta.CurrentPage = page
 	response.Meta.ItemsPerPage = size
 
 	c.Header("Content-Type", "application/xml; charset=utf-8")
 	c.XML(http.StatusOK, response)
 }
+
+// pageBounds clamps an offset/limit window to a slice of length n.
+func pageBounds(n, offset, limit int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > n {
+		offset = n
+	}
+	end := offset + limit
+	if end > n {
+		end = n
+	}
+	return offset, end
+}
@@ -9,6 +9,16 @@ type Config struct {
 	RedisURL    string
 	APIPort     string
 	LogLevel    string
+	// Redis fallback: a probe every RedisProbeInterval switches caches and rate limits
+	// to process-local stores after RedisProbeFailures failed pings; "0s" disables it
+	RedisProbeInterval      string
+	RedisProbeFailures      string
+	RedisFallbackMaxEntries string
+	// Leader election: scheduled jobs run only on the replica holding their Redis lease,
+	// renewed three times per LeaderLeaseTTL; InstanceID defaults to the host name
+	LeaderElectionEnabled string
+	LeaderLeaseTTL        string
+	InstanceID            string
 	// Public API rate limiting
 	PublicRateLimit       string // requests per window (per IP)
 	PublicRateLimitWindow string // duration, e.g., "1m"
@@ -17,8 +27,62 @@ type Config struct {
 	SearchCacheTTL     string // duration, e.g., "60s"
 	Provider1BaseURL   string
 	Provider2BaseURL   string
+	// Provider1Transport and Provider2Transport are JSON auth/transport settings of the seeded providers
+	Provider1Transport string
+	Provider2Transport string
+	// ProviderSecretsDir is the only directory provider transports may read secret files from
+	ProviderSecretsDir string
 	ProviderTimeout    string
 	RateLimitEnabled   string
+	// RateLimitMaxWait is how long a fetch waits for a provider rate limit token
+	// before it is recorded as rate limited; 0 gives up at once
+	RateLimitMaxWait string
+	// ProviderMaxInFlight caps concurrent fetches of one provider across instances; 0 disables
+	ProviderMaxInFlight string
+	// Provider pagination
+	ProviderPageSize     string
+	ProviderMaxPages     string
+	ProviderPageDelay    string // duration; raised to the provider rate limit spacing
+	ProviderFetchTimeout string // duration budget for a full multi-page fetch
+	// ProviderMaxPayloadBytes caps a single provider response
+	ProviderMaxPayloadBytes string
+	// ProviderDefaultReadingTime is the reading time in minutes of JSON provider
+	// articles that report none; 0 leaves it unset
+	ProviderDefaultReadingTime string
+	// ProviderPreviewAllowPrivate lets admin previews reach loopback and private
+	// addresses, e.g. local mock providers; off, previews only reach public hosts
+	ProviderPreviewAllowPrivate string
+	// Provider request retries on network errors, 429 and 5xx
+	ProviderRetryMaxAttempts string // attempts per request including the first; 1 disables retries
+	ProviderRetryBaseDelay   string // duration of the first backoff, doubled per attempt
+	ProviderRetryMaxDelay    string // duration cap of a single backoff
+	ProviderRetryBudget      string // duration budget for one request across all attempts
+	// ProviderRegistryReloadInterval is how often the providers table is checked for changes
+	ProviderRegistryReloadInterval string
+	// ProviderDefinitionsPath is a YAML/JSON file or directory of generic provider definitions
+	ProviderDefinitionsPath string
+	// FeedURLs lists RSS/Atom feeds as comma-separated id=url pairs
+	FeedURLs string
+	// File import provider: an NDJSON/CSV file or directory, its provider ID and poll interval (0 disables watching)
+	ImportPath          string
+	ImportProviderID    string
+	ImportWatchInterval string
+	// Provider circuit breaker
+	CircuitBreakerEnabled             string
+	CircuitBreakerWindow              string // duration over which failures are counted
+	CircuitBreakerMinRequests         string // calls in the window before the failure rate is evaluated
+	CircuitBreakerFailureRate         string // 0-1 share of failed calls that opens the circuit
+	CircuitBreakerConsecutiveFailures string // failed calls in a row that open the circuit whatever the window (0 disables)
+	CircuitBreakerCoolDown            string // duration an open circuit waits before a half-open probe
+	// Provider health checks
+	ProviderHealthInterval string // duration between background probes (0 disables the monitor)
+	ProviderHealthCacheTTL string // duration health-check results are served from cache
+	ProviderHealthTimeout  string // duration bound of a single probe
+	// Push ingestion; secrets are comma-separated id=secret pairs and must never be logged
+	IngestSecrets      string
+	IngestMaxSkew      string // duration X-Timestamp may differ from server time
+	IngestMaxBodyBytes string
+	IngestMaxItems     string
 	// Scoring
 	ScoreRecalcEnabled  string
 	ScoreRecalcInterval string
@@ -28,15 +92,19 @@ type Config struct {
 	Freshness1Week      string
 	Freshness1Month     string
 	Freshness3Months    string
+	WatchTimeWeight     string // points per 1000 estimated watch hours of a video (0 = off)
+	CommentWeight       string // likes/reactions one comment counts as in engagement (0 = off)
 	// Sync
 	ContentSyncEnabled                 string
 	ContentSyncInterval                string
-	ContentSyncRetryCount              string
-	ContentSyncRetryDelay              string
+	ContentSyncBatchSize               string // items loaded and stored per transaction; 0 syncs item by item
+	ContentRemovalMisses               string // complete syncs an item must be missing from before removal; 0 disables
+	ContentSyncProviderTimeout         string // budget for one provider's sync in a multi-provider run; 0 disables
 	MetricsChangeThresholdPercent      string
 	MetricsChangeThresholdAbsViews     string
 	MetricsChangeThresholdAbsLikes     string
 	MetricsChangeThresholdAbsReactions string
+	MetricsChangeThresholdAbsComments  string
 	AdminAPIKey                        string
 	// API pagination
 	DefaultPageSize string
@@ -56,6 +124,12 @@ func Load() Config {
 	cfg := Config{
 		DatabaseURL:                        getenv("DATABASE_URL", "postgres://postgres:postgres@db:5432/searchdb?sslmode=disable"),
 		RedisURL:                           getenv("REDIS_URL", "redis://redis:6379"),
+		RedisProbeInterval:                 getenv("REDIS_PROBE_INTERVAL", "5s"),
+		RedisProbeFailures:                 getenv("REDIS_PROBE_FAILURES", "2"),
+		RedisFallbackMaxEntries:            getenv("REDIS_FALLBACK_MAX_ENTRIES", "10000"),
+		LeaderElectionEnabled:              getenv("LEADER_ELECTION_ENABLED", "true"),
+		LeaderLeaseTTL:                     getenv("LEADER_LEASE_TTL", "15s"),
+		InstanceID:                         getenv("INSTANCE_ID", ""),
 		APIPort:                            getenv("API_PORT", "8080"),
 		LogLevel:                           getenv("LOG_LEVEL", "info"),
 		PublicRateLimit:                    getenv("PUBLIC_RATE_LIMIT", "300"),
@@ -64,8 +138,43 @@ func Load() Config {
 		SearchCacheTTL:                     getenv("SEARCH_CACHE_TTL", "60s"),
 		Provider1BaseURL:                   getenv("PROVIDER1_BASE_URL", "http://localhost:8080/mock/provider1"),
 		Provider2BaseURL:                   getenv("PROVIDER2_BASE_URL", "http://localhost:8080/mock/provider2"),
+		Provider1Transport:                 getenv("PROVIDER1_TRANSPORT", ""),
+		Provider2Transport:                 getenv("PROVIDER2_TRANSPORT", ""),
+		ProviderSecretsDir:                 getenv("PROVIDER_SECRETS_DIR", ""),
 		ProviderTimeout:                    getenv("PROVIDER_TIMEOUT", "10s"),
 		RateLimitEnabled:                   getenv("RATE_LIMIT_ENABLED", "true"),
+		RateLimitMaxWait:                   getenv("PROVIDER_RATE_LIMIT_MAX_WAIT", "30s"),
+		ProviderMaxInFlight:                getenv("PROVIDER_MAX_IN_FLIGHT", "2"),
+		ProviderPageSize:                   getenv("PROVIDER_PAGE_SIZE", "40"),
+		ProviderMaxPages:                   getenv("PROVIDER_MAX_PAGES", "100"),
+		ProviderPageDelay:                  getenv("PROVIDER_PAGE_DELAY", "0s"),
+		ProviderFetchTimeout:               getenv("PROVIDER_FETCH_TIMEOUT", "5m"),
+		ProviderPreviewAllowPrivate:        getenv("PROVIDER_PREVIEW_ALLOW_PRIVATE", "false"),
+		ProviderMaxPayloadBytes:            getenv("PROVIDER_MAX_PAYLOAD_BYTES", "67108864"),
+		ProviderDefaultReadingTime:         getenv("PROVIDER_DEFAULT_READING_TIME", "5"),
+		ProviderRetryMaxAttempts:           getenv("PROVIDER_RETRY_MAX_ATTEMPTS", "4"),
+		ProviderRetryBaseDelay:             getenv("PROVIDER_RETRY_BASE_DELAY", "500ms"),
+		ProviderRetryMaxDelay:              getenv("PROVIDER_RETRY_MAX_DELAY", "10s"),
+		ProviderRetryBudget:                getenv("PROVIDER_RETRY_BUDGET", "30s"),
+		ProviderRegistryReloadInterval:     getenv("PROVIDER_REGISTRY_RELOAD_INTERVAL", "30s"),
+		ProviderDefinitionsPath:            getenv("PROVIDER_DEFINITIONS_PATH", ""),
+		FeedURLs:                           getenv("FEED_URLS", ""),
+		ImportPath:                         getenv("IMPORT_PATH", ""),
+		ImportProviderID:                   getenv("IMPORT_PROVIDER_ID", "import"),
+		ImportWatchInterval:                getenv("IMPORT_WATCH_INTERVAL", "0s"),
+		CircuitBreakerEnabled:              getenv("CIRCUIT_BREAKER_ENABLED", "true"),
+		CircuitBreakerWindow:               getenv("CIRCUIT_BREAKER_WINDOW", "10m"),
+		CircuitBreakerMinRequests:          getenv("CIRCUIT_BREAKER_MIN_REQUESTS", "3"),
+		CircuitBreakerFailureRate:          getenv("CIRCUIT_BREAKER_FAILURE_RATE", "0.5"),
+		CircuitBreakerConsecutiveFailures:  getenv("CIRCUIT_BREAKER_CONSECUTIVE_FAILURES", "3"),
+		CircuitBreakerCoolDown:             getenv("CIRCUIT_BREAKER_COOLDOWN", "5m"),
+		ProviderHealthInterval:             getenv("PROVIDER_HEALTH_CHECK_INTERVAL", "0s"),
+		ProviderHealthCacheTTL:             getenv("PROVIDER_HEALTH_CACHE_TTL", "30s"),
+		ProviderHealthTimeout:              getenv("PROVIDER_HEALTH_TIMEOUT", "5s"),
+		IngestSecrets:                      getenv("INGEST_SECRETS", ""),
+		IngestMaxSkew:                      getenv("INGEST_MAX_SKEW", "5m"),
+		IngestMaxBodyBytes:                 getenv("INGEST_MAX_BODY_BYTES", "5242880"),
+		IngestMaxItems:                     getenv("INGEST_MAX_ITEMS", "1000"),
 		ScoreRecalcEnabled:                 getenv("SCORE_RECALCULATION_ENABLED", "true"),
 		ScoreRecalcInterval:                getenv("SCORE_RECALCULATION_INTERVAL", "24h"),
 		ScoreBatchSize:                     getenv("SCORE_BATCH_SIZE", "100"),
@@ -74,14 +183,18 @@ func Load() Config {
 		Freshness1Week:                     getenv("FRESHNESS_1_WEEK", "5"),
 		Freshness1Month:                    getenv("FRESHNESS_1_MONTH", "3"),
 		Freshness3Months:                   getenv("FRESHNESS_3_MONTHS", "1"),
+		WatchTimeWeight:                    getenv("WATCH_TIME_WEIGHT", "0"),
+		CommentWeight:                      getenv("COMMENT_WEIGHT", "0"),
 		ContentSyncEnabled:                 getenv("CONTENT_SYNC_ENABLED", "true"),
 		ContentSyncInterval:                getenv("CONTENT_SYNC_INTERVAL", "6h"),
-		ContentSyncRetryCount:              getenv("CONTENT_SYNC_RETRY_COUNT", "3"),
-		ContentSyncRetryDelay:              getenv("CONTENT_SYNC_RETRY_DELAY", "30s"),
+		ContentSyncBatchSize:               getenv("CONTENT_SYNC_BATCH_SIZE", "500"),
+		ContentRemovalMisses:               getenv("CONTENT_REMOVAL_MISSES", "2"),
+		ContentSyncProviderTimeout:         getenv("CONTENT_SYNC_PROVIDER_TIMEOUT", "10m"),
 		MetricsChangeThresholdPercent:      getenv("METRICS_CHANGE_THRESHOLD_PERCENT", "5"),
 		MetricsChangeThresholdAbsViews:     getenv("METRICS_CHANGE_THRESHOLD_ABS_VIEWS", "100"),
 		MetricsChangeThresholdAbsLikes:     getenv("METRICS_CHANGE_THRESHOLD_ABS_LIKES", "10"),
 		MetricsChangeThresholdAbsReactions: getenv("METRICS_CHANGE_THRESHOLD_ABS_REACTIONS", "5"),
+		MetricsChangeThresholdAbsComments:  getenv("METRICS_CHANGE_THRESHOLD_ABS_COMMENTS", "5"),
 		AdminAPIKey:                        getenv("ADMIN_API_KEY", ""),
 		DefaultPageSize:                    getenv("DEFAULT_PAGE_SIZE", "20"),
 		MaxPageSize:                        getenv("MAX_PAGE_SIZE", "100"),
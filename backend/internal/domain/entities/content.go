@@ -21,6 +21,9 @@ type Content struct {
 	PublishedAt       *time.Time  `json:"publishedAt,omitempty"`
 	CreatedAt         time.Time   `json:"createdAt"`
 	UpdatedAt         time.Time   `json:"updatedAt"`
+	Tags              []string    `json:"tags,omitempty"`
+	// Language is "en", "tr" or "und"; nil for rows stored before detection existed
+	Language *string `json:"language,omitempty"`
 
 	// Relation
 	Metrics *ContentMetrics `json:"metrics,omitempty"`
@@ -2,15 +2,19 @@ package entities
 
 import "time"
 
+// ContentMetrics holds the provider metrics of a content. DurationSeconds is the
+// video length; Comments counts comments on any content type.
 type ContentMetrics struct {
-	ID             int64      `json:"id"`
-	ContentID      int64      `json:"contentId"`
-	Views          int64      `json:"views"`
-	Likes          int64      `json:"likes"`
-	ReadingTime    int        `json:"readingTime"`
-	Reactions      int        `json:"reactions"`
-	FinalScore     float64    `json:"finalScore"`
-	RecalculatedAt *time.Time `json:"recalculatedAt,omitempty"`
-	CreatedAt      time.Time  `json:"createdAt"`
-	UpdatedAt      time.Time  `json:"updatedAt"`
+	ID              int64      `json:"id"`
+	ContentID       int64      `json:"contentId"`
+	Views           int64      `json:"views"`
+	Likes           int64      `json:"likes"`
+	ReadingTime     int        `json:"readingTime"`
+	Reactions       int        `json:"reactions"`
+	DurationSeconds int        `json:"durationSeconds"`
+	Comments        int        `json:"comments"`
+	FinalScore      float64    `json:"finalScore"`
+	RecalculatedAt  *time.Time `json:"recalculatedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
 }
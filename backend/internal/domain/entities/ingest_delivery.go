@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type IngestDeliveryStatus string
+
+const (
+	IngestDeliveryProcessing IngestDeliveryStatus = "processing"
+	IngestDeliveryCompleted  IngestDeliveryStatus = "completed"
+)
+
+// IngestDelivery records one pushed batch so a redelivery with the same ID is not
+// applied twice. Result holds the JSON encoded outcome once the batch completed.
+type IngestDelivery struct {
+	ProviderID  string
+	DeliveryID  string
+	Status      IngestDeliveryStatus
+	Result      json.RawMessage
+	ReceivedAt  time.Time
+	CompletedAt *time.Time
+}
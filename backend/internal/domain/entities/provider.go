@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProviderKind selects the implementation a registry row is built with.
+type ProviderKind string
+
+const (
+	ProviderKindJSON    ProviderKind = "json"
+	ProviderKindXML     ProviderKind = "xml"
+	ProviderKindGeneric ProviderKind = "generic"
+	ProviderKindFeed    ProviderKind = "feed"
+	ProviderKindFile    ProviderKind = "file"
+)
+
+// ProviderSource records who manages a registry row. Env rows are reconciled with
+// the environment on every start; admin rows belong to the admin API.
+type ProviderSource string
+
+const (
+	ProviderSourceEnv   ProviderSource = "env"
+	ProviderSourceAdmin ProviderSource = "admin"
+)
+
+// Provider is a row of the provider registry. BaseURL is the feed URL for kind
+// feed and the import path for kind file; Definition holds the generic provider
+// definition. Transport holds the auth and HTTP settings (see providers.TransportConfig);
+// it names where secrets are read from, never the secrets themselves. Zero
+// RateLimitPerMinute, Timeout and SyncInterval use the defaults.
+type Provider struct {
+	ID                 string
+	Kind               ProviderKind
+	BaseURL            string
+	Enabled            bool
+	RateLimitPerMinute int
+	Timeout            time.Duration
+	SyncInterval       time.Duration
+	Definition         json.RawMessage
+	Transport          json.RawMessage
+	Source             ProviderSource
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
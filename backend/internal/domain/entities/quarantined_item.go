@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type QuarantineStatus string
+
+const (
+	QuarantinePending   QuarantineStatus = "pending"
+	QuarantineDiscarded QuarantineStatus = "discarded"
+)
+
+// QuarantinedItem is a provider item that failed validation during sync or ingest.
+// ItemKey identifies the item across runs: its provider content ID, or a hash of
+// the raw payload when it has none. Item is the mapped item used on retry.
+type QuarantinedItem struct {
+	ID                int64
+	ProviderID        string
+	ItemKey           string
+	ProviderContentID string
+	Reasons           []string
+	RawPayload        string
+	Item              json.RawMessage
+	Status            QuarantineStatus
+	Occurrences       int
+	FirstSeenAt       time.Time
+	LastSeenAt        time.Time
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// SyncCursor is the incremental sync state kept per provider: the validators of the
+// last full response and the newest published_at seen so far.
+type SyncCursor struct {
+	ProviderID     string
+	ETag           string
+	LastModified   string
+	MaxPublishedAt *time.Time
+	UpdatedAt      time.Time
+}
@@ -5,11 +5,12 @@ import "time"
 type SyncStatus string
 
 const (
-	SyncStatusSuccess    SyncStatus = "success"
-	SyncStatusPartial    SyncStatus = "partial"
-	SyncStatusFailed     SyncStatus = "failed"
-	SyncStatusInProgress SyncStatus = "in_progress"
-	SyncStatusSkipped    SyncStatus = "skipped"
+	SyncStatusSuccess     SyncStatus = "success"
+	SyncStatusPartial     SyncStatus = "partial"
+	SyncStatusFailed      SyncStatus = "failed"
+	SyncStatusInProgress  SyncStatus = "in_progress"
+	SyncStatusSkipped     SyncStatus = "skipped"
+	SyncStatusRateLimited SyncStatus = "rate_limited"
 )
 
 type SyncHistory struct {
@@ -21,8 +22,28 @@ type SyncHistory struct {
 	UpdatedContents int
 	SkippedContents int
 	FailedContents  int
+	// QuarantinedContents counts items that failed validation
+	QuarantinedContents int
+	// RemovedContents counts stored items soft-deleted because the provider stopped returning them
+	RemovedContents int
+	// ChangedContents counts updated items whose attributes changed at the provider
+	ChangedContents int
 	ErrorMessage    *string
 	StartedAt       time.Time
 	CompletedAt     *time.Time
 	DurationMs      int
+	// Attempts lists the provider requests of the run, retries included
+	Attempts []SyncAttempt
+}
+
+// SyncAttempt is one provider HTTP request made during a sync run; retries of a
+// request share URL and carry increasing Attempt numbers.
+type SyncAttempt struct {
+	URL        string    `json:"url"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int       `json:"duration_ms"`
+	BackoffMs  int       `json:"backoff_ms,omitempty"`
 }
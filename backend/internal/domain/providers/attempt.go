@@ -0,0 +1,33 @@
+package providers
+
+import (
+	"context"
+	"time"
+)
+
+// RequestAttempt is one HTTP request made while fetching a provider. Retries of the
+// same request share URL and carry increasing Attempt numbers.
+type RequestAttempt struct {
+	URL        string
+	Attempt    int
+	StatusCode int
+	Error      string
+	StartedAt  time.Time
+	Duration   time.Duration
+	// Backoff is the wait before the next attempt; zero when no retry followed
+	Backoff time.Duration
+}
+
+type attemptRecorderKey struct{}
+
+// WithAttemptRecorder returns a context whose provider requests are reported to fn.
+func WithAttemptRecorder(ctx context.Context, fn func(RequestAttempt)) context.Context {
+	return context.WithValue(ctx, attemptRecorderKey{}, fn)
+}
+
+// RecordAttempt reports a to the recorder attached to ctx, if any.
+func RecordAttempt(ctx context.Context, a RequestAttempt) {
+	if fn, ok := ctx.Value(attemptRecorderKey{}).(func(RequestAttempt)); ok && fn != nil {
+		fn(a)
+	}
+}
@@ -0,0 +1,20 @@
+package providers
+
+import "context"
+
+type requestGateKey struct{}
+
+// WithRequestGate returns a context whose providers call fn before every HTTP
+// request, retries included, e.g. to take a rate limit token. A non-nil error from
+// fn fails the request without sending it.
+func WithRequestGate(ctx context.Context, fn func(context.Context) error) context.Context {
+	return context.WithValue(ctx, requestGateKey{}, fn)
+}
+
+// AwaitRequest passes through the gate attached to ctx, if any.
+func AwaitRequest(ctx context.Context) error {
+	if fn, ok := ctx.Value(requestGateKey{}).(func(context.Context) error); ok && fn != nil {
+		return fn(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package providers
+
+import "context"
+
+type partialRecorderKey struct{}
+
+// WithPartialRecorder returns a context whose providers report through fn when a
+// fetch returns less than the full feed, e.g. it stopped at max pages or asked only
+// for items changed since the cursor. Items missing from such a fetch may still exist.
+func WithPartialRecorder(ctx context.Context, fn func(reason string)) context.Context {
+	return context.WithValue(ctx, partialRecorderKey{}, fn)
+}
+
+// ReportPartial reports reason to the recorder attached to ctx, if any.
+func ReportPartial(ctx context.Context, reason string) {
+	if fn, ok := ctx.Value(partialRecorderKey{}).(func(string)); ok && fn != nil {
+		fn(reason)
+	}
+}
@@ -1,28 +1,98 @@
 package providers
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 type RateLimit struct {
 	RequestsPerMinute int
 }
 
 type ProviderContent struct {
-	ProviderID        string
-	ProviderContentID string
-	Title             string
-	ContentType       string // "video" | "text"
-	Description       string
-	URL               string
-	ThumbnailURL      string
-	Views             *int64
-	Likes             *int64
-	ReadingTime       *int
-	Reactions         *int
-	PublishedAt       time.Time
+	ProviderID        string    `json:"provider_id"`
+	ProviderContentID string    `json:"provider_content_id"`
+	Title             string    `json:"title"`
+	ContentType       string    `json:"content_type"` // "video" | "text"
+	Description       string    `json:"description,omitempty"`
+	URL               string    `json:"url"`
+	ThumbnailURL      string    `json:"thumbnail_url,omitempty"`
+	Views             *int64    `json:"views,omitempty"`
+	Likes             *int64    `json:"likes,omitempty"`
+	ReadingTime       *int      `json:"reading_time,omitempty"`
+	Reactions         *int      `json:"reactions,omitempty"`
+	DurationSeconds   *int      `json:"duration_seconds,omitempty"`
+	Comments          *int      `json:"comments,omitempty"`
+	PublishedAt       time.Time `json:"published_at"`
+	Tags              []string  `json:"tags,omitempty"`
+	// Language is the provider's language code, e.g. "tr"; empty means detect it
+	Language string `json:"language,omitempty"`
+	// Raw is the item as the provider sent it, kept when the item is quarantined
+	Raw []byte `json:"-"`
+	// Problems lists values the provider could not map, e.g. a date that does not parse
+	Problems []string `json:"-"`
 }
 
 type IContentProvider interface {
-	FetchContents() ([]ProviderContent, error)
+	FetchContents(ctx context.Context) ([]ProviderContent, error)
 	GetProviderID() string
 	GetRateLimit() RateLimit
 }
+
+// Cursor is where an incremental fetch resumes: conditional request validators
+// and the newest published_at already stored.
+type Cursor struct {
+	ETag         string
+	LastModified string
+	Since        *time.Time
+}
+
+// FetchResult is the outcome of an incremental fetch. NotModified means the source
+// reported no changes and Items is empty; Cursor carries the new validators.
+// RowErrors lists the malformed records skipped by this fetch.
+type FetchResult struct {
+	Items       []ProviderContent
+	NotModified bool
+	Cursor      Cursor
+	RowErrors   []RowError
+}
+
+// IncrementalProvider is implemented by providers that can skip unchanged feeds
+// or pull only items changed since the cursor.
+type IncrementalProvider interface {
+	FetchSince(ctx context.Context, cur Cursor) (FetchResult, error)
+}
+
+// StreamingProvider is implemented by providers that decode their feed item by
+// item instead of holding it in memory. StreamContents calls yield for every item
+// in feed order and stops with the first error yield returns.
+type StreamingProvider interface {
+	StreamContents(ctx context.Context, yield func(ProviderContent) error) error
+}
+
+// RowError describes one malformed record that a provider skipped.
+type RowError struct {
+	Source  string
+	Line    int
+	Message string
+}
+
+func (e RowError) String() string {
+	return fmt.Sprintf("%s:%d: %s", e.Source, e.Line, e.Message)
+}
+
+// RowErrorProvider is implemented by providers that skip malformed records instead
+// of failing the whole fetch; FetchWithRowErrors returns the items together with the
+// records skipped by that call.
+type RowErrorProvider interface {
+	FetchWithRowErrors(ctx context.Context) (FetchResult, error)
+}
+
+// HealthProber is implemented by providers that can check their source with a
+// single lightweight request. Probe returns the HTTP status code (0 when no HTTP
+// request was made) and an error when the source is unreachable or its payload
+// does not parse.
+type HealthProber interface {
+	Probe(ctx context.Context) (statusCode int, err error)
+}
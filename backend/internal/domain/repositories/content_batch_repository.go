@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"search_engine/internal/domain/entities"
+)
+
+// ContentBatchRepository loads and stores synced contents a chunk at a time, so a
+// sync costs a few round trips per chunk instead of several per item.
+type ContentBatchRepository interface {
+	// GetByProviderKeys returns the stored contents of providerID among providerContentIDs.
+	// Metrics.ContentID is zero for contents without a metrics row.
+	GetByProviderKeys(ctx context.Context, providerID string, providerContentIDs []string) ([]ContentWithMetrics, error)
+	// UpsertBatch writes contents in one transaction: rows are inserted or, when any
+	// field differs, updated by provider key, and new rows get their ID set. Metrics
+	// are upserted for contents whose Metrics is set, and Tags replace the stored tags
+	// of every content in the batch. Provider keys must be unique within a batch.
+	UpsertBatch(ctx context.Context, contents []entities.Content) error
+}
@@ -9,6 +9,11 @@ import (
 type ContentFilters struct {
 	ContentType *entities.ContentType
 	ProviderID  *string
+	// Language restricts results to one content language code, e.g. "tr"
+	Language *string
+	// Tags restricts results to contents carrying any (or, with MatchAllTags, all) of the names
+	Tags         []string
+	MatchAllTags bool
 }
 
 type Pagination struct {
@@ -35,12 +40,18 @@ type ContentRepository interface {
 	ListIDs(ctx context.Context, offset, limit int) ([]int64, error)
 	CountAll(ctx context.Context) (int64, error)
 	// Search & Stats
-	SearchWithFilters(ctx context.Context, keyword string, contentType *entities.ContentType, pagination Pagination, sort SearchSort) ([]ContentWithMetrics, int64, error)
+	SearchWithFilters(ctx context.Context, keyword string, filters ContentFilters, pagination Pagination, sort SearchSort) ([]ContentWithMetrics, int64, error)
 	GetDetailByID(ctx context.Context, id int64) (*ContentWithMetrics, error)
 	CountByType(ctx context.Context) (map[entities.ContentType]int64, error)
 	GetAverageScore(ctx context.Context) (float64, error)
 	CountByProvider(ctx context.Context) (map[string]int64, error)
 	SoftDelete(ctx context.Context, id int64) error
+	// MarkMissing counts a missed sync on the live contents of providerID not in seen and
+	// returns the IDs that have now been missing for at least misses syncs in a row
+	MarkMissing(ctx context.Context, providerID string, seen []string, misses int) ([]int64, error)
+	// RestoreSeen clears the missed syncs of the contents in seen and restores the ones
+	// soft-deleted as missing upstream, returning how many were restored
+	RestoreSeen(ctx context.Context, providerID string, seen []string) (int, error)
 	ListIDsByType(ctx context.Context, t entities.ContentType, offset, limit int) ([]int64, error)
 	GetAverageScoreByProvider(ctx context.Context, providerID string) (float64, error)
 }
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"search_engine/internal/domain/entities"
+)
+
+type IngestDeliveryRepository interface {
+	// Claim marks the delivery as processing. It reports claimed=false together with
+	// the stored row when the delivery is completed or still being processed; a
+	// processing claim older than staleAfter is taken over.
+	Claim(ctx context.Context, providerID, deliveryID string, staleAfter time.Duration) (claimed bool, existing *entities.IngestDelivery, err error)
+	// Complete stores the result of a claimed delivery
+	Complete(ctx context.Context, providerID, deliveryID string, result []byte) error
+	// Release drops a claim so the delivery can be retried
+	Release(ctx context.Context, providerID, deliveryID string) error
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"search_engine/internal/domain/entities"
+)
+
+var (
+	ErrProviderExists   = errors.New("provider already exists")
+	ErrProviderNotFound = errors.New("provider not found")
+)
+
+type ProviderRepository interface {
+	List(ctx context.Context) ([]entities.Provider, error)
+	// Get returns nil without error when the provider does not exist
+	Get(ctx context.Context, id string) (*entities.Provider, error)
+	// Create returns ErrProviderExists when the ID is taken
+	Create(ctx context.Context, p *entities.Provider) error
+	// Update and Delete return ErrProviderNotFound when no row matches
+	Update(ctx context.Context, p *entities.Provider) error
+	Delete(ctx context.Context, id string) error
+	// Version changes whenever a row is added, changed or removed
+	Version(ctx context.Context) (string, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"search_engine/internal/domain/entities"
+)
+
+type QuarantineRepository interface {
+	// Upsert stores the item or, when its key is already quarantined, refreshes the
+	// payload and reasons and counts the occurrence. Discarded items stay discarded.
+	Upsert(ctx context.Context, it *entities.QuarantinedItem) error
+	// Get returns nil, nil when the item does not exist
+	Get(ctx context.Context, id int64) (*entities.QuarantinedItem, error)
+	List(ctx context.Context, providerID *string, status *entities.QuarantineStatus, limit, offset int) ([]entities.QuarantinedItem, error)
+	Count(ctx context.Context, providerID *string, status *entities.QuarantineStatus) (int64, error)
+	SetStatus(ctx context.Context, id int64, status entities.QuarantineStatus) error
+	UpdateReasons(ctx context.Context, id int64, reasons []string) error
+	Delete(ctx context.Context, id int64) error
+	// Resolve deletes pending items of the provider whose keys now passed validation
+	Resolve(ctx context.Context, providerID string, itemKeys []string) error
+}
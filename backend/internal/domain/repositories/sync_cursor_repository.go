@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"search_engine/internal/domain/entities"
+)
+
+type SyncCursorRepository interface {
+	// Get returns nil without error when the provider has no cursor yet
+	Get(ctx context.Context, providerID string) (*entities.SyncCursor, error)
+	Upsert(ctx context.Context, c *entities.SyncCursor) error
+	Delete(ctx context.Context, providerID string) error
+}
@@ -10,6 +10,8 @@ type SyncHistoryRepository interface {
 	Update(ctx context.Context, h *entities.SyncHistory) error
 	GetByProviderID(ctx context.Context, providerID string, limit int) ([]entities.SyncHistory, error)
 	GetLastSync(ctx context.Context, providerID string) (*entities.SyncHistory, error)
+	// GetLastSuccessfulSync returns the latest completed run with status success or partial
+	GetLastSuccessfulSync(ctx context.Context, providerID string) (*entities.SyncHistory, error)
 	GetAll(ctx context.Context, limit int) ([]entities.SyncHistory, error)
 	// Admin listing with filters
 	List(ctx context.Context, providerID *string, status *entities.SyncStatus, limit, offset int) ([]entities.SyncHistory, error)
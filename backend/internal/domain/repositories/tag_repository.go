@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"search_engine/internal/domain/entities"
+)
+
+type TagRepository interface {
+	// SetContentTags replaces the tags of a content with names, creating missing tags
+	SetContentTags(ctx context.Context, contentID int64, names []string) error
+	ListWithCounts(ctx context.Context, limit int) ([]entities.TagCount, error)
+}
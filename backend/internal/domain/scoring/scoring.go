@@ -17,6 +17,12 @@ type ScoringEngine struct {
 	VideoTypeMultiplier float64
 	TextTypeMultiplier  float64
 	Freshness           FreshnessConfig
+	// WatchTimeWeight adds points per 1000 estimated watch hours (views × duration)
+	// to a video's base score; 0 ignores duration
+	WatchTimeWeight float64
+	// CommentWeight counts each comment as that many likes (video) or reactions
+	// (text) in the engagement score; 0 ignores comments
+	CommentWeight float64
 }
 
 type IScoringService interface {
@@ -37,7 +43,8 @@ func (s *ScoringEngine) calculateBaseScore(ct entities.ContentType, m *entities.
 	case entities.ContentTypeVideo:
 		views := float64(max64(m.Views, 0))
 		likes := float64(max64(m.Likes, 0))
-		return (views / 1000.0) + (likes / 100.0)
+		watchHours := views * float64(maxInt(m.DurationSeconds, 0)) / 3600.0
+		return (views / 1000.0) + (likes / 100.0) + s.WatchTimeWeight*(watchHours/1000.0)
 	default:
 		rt := float64(maxInt(m.ReadingTime, 0))
 		reac := float64(maxInt(m.Reactions, 0))
@@ -63,15 +70,16 @@ func (s *ScoringEngine) calculateFreshnessScore(p *time.Time) float64 {
 }
 
 func (s *ScoringEngine) calculateEngagementScore(ct entities.ContentType, m *entities.ContentMetrics) float64 {
+	comments := s.CommentWeight * float64(maxInt(m.Comments, 0))
 	switch ct {
 	case entities.ContentTypeVideo:
 		if m.Views > 0 {
-			return (float64(max64(m.Likes, 0)) / float64(m.Views)) * 10.0
+			return ((float64(max64(m.Likes, 0)) + comments) / float64(m.Views)) * 10.0
 		}
 		return 0
 	default:
 		if m.ReadingTime > 0 {
-			return (float64(maxInt(m.Reactions, 0)) / float64(m.ReadingTime)) * 5.0
+			return ((float64(maxInt(m.Reactions, 0)) + comments) / float64(m.ReadingTime)) * 5.0
 		}
 		return 0
 	}
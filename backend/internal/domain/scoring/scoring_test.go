@@ -63,3 +63,32 @@ func TestVeryOldContentFreshnessZero(t *testing.T) {
 		t.Fatalf("expected 0 score for old content got %v", score)
 	}
 }
+
+func TestVideoWatchTimeRaisesScore(t *testing.T) {
+	engine := newEngine()
+	engine.WatchTimeWeight = 1
+	p := time.Now().UTC().AddDate(-1, 0, 0)
+	c := entities.Content{ContentType: entities.ContentTypeVideo, PublishedAt: &p}
+	short := entities.ContentMetrics{Views: 100000, Likes: 5000, DurationSeconds: 60}
+	long := entities.ContentMetrics{Views: 100000, Likes: 5000, DurationSeconds: 3600}
+	s1, _ := engine.CalculateScore(&c, &short)
+	s2, _ := engine.CalculateScore(&c, &long)
+	// 100000 views x 1h = 100 thousand watch hours, times the 1.5 video multiplier
+	if diff := s2 - s1; diff < 145 || diff > 150 {
+		t.Fatalf("expected watch time to add about 148.5, got %v -> %v", s1, s2)
+	}
+}
+
+func TestTextCommentsDriveEngagement(t *testing.T) {
+	engine := newEngine()
+	p := time.Now().UTC().AddDate(-1, 0, 0)
+	c := entities.Content{ContentType: entities.ContentTypeText, PublishedAt: &p}
+	m := entities.ContentMetrics{ReadingTime: 5, Reactions: 10, Comments: 20}
+	without, _ := engine.CalculateScore(&c, &m)
+	engine.CommentWeight = 2
+	with, _ := engine.CalculateScore(&c, &m)
+	// 20 comments count as 40 reactions: 40 / 5 min * 5
+	if with-without != 40 {
+		t.Fatalf("expected comments to add 40, got %v -> %v", without, with)
+	}
+}
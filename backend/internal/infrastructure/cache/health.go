@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	ModeRedis    = "redis"
+	ModeFallback = "fallback"
+)
+
+// RedisStatus is the probe's view of Redis, as reported by /health.
+type RedisStatus struct {
+	// Mode is "redis", or "fallback" while process-local stores are used
+	Mode string `json:"mode"`
+	// Since is when the current mode began
+	Since     time.Time  `json:"since"`
+	LastProbe *time.Time `json:"last_probe,omitempty"`
+	// ProbeMs is the duration of the last probe
+	ProbeMs   float64 `json:"probe_ms"`
+	LastError string  `json:"last_error,omitempty"`
+}
+
+// RedisHealth pings Redis periodically and decides whether Redis-backed stores use
+// Redis or their process-local fallback. Consecutive FailureThreshold failed probes
+// switch to fallback; the first successful probe switches back and runs the
+// OnRecover callbacks. A nil *RedisHealth always reports Redis as up.
+type RedisHealth struct {
+	Client *redis.Client
+	Logger *zap.Logger
+	// Timeout bounds a single probe
+	Timeout time.Duration
+	// FailureThreshold is the number of consecutive failed probes before falling back
+	FailureThreshold int
+
+	mu        sync.RWMutex
+	status    RedisStatus
+	failures  int
+	onRecover []func(ctx context.Context)
+}
+
+func NewRedisHealth(client *redis.Client, logger *zap.Logger) *RedisHealth {
+	return &RedisHealth{
+		Client:           client,
+		Logger:           logger,
+		Timeout:          time.Second,
+		FailureThreshold: 2,
+		status:           RedisStatus{Mode: ModeRedis, Since: time.Now().UTC()},
+	}
+}
+
+// Up reports whether Redis should be used.
+func (h *RedisHealth) Up() bool {
+	if h == nil {
+		return true
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.status.Mode == ModeRedis
+}
+
+func (h *RedisHealth) Status() RedisStatus {
+	if h == nil {
+		return RedisStatus{Mode: ModeRedis}
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.status
+}
+
+// OnRecover registers fn to run, in the probe's goroutine, when Redis comes back.
+func (h *RedisHealth) OnRecover(fn func(ctx context.Context)) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onRecover = append(h.onRecover, fn)
+}
+
+// Probe pings Redis once and updates the mode.
+func (h *RedisHealth) Probe(ctx context.Context) {
+	pctx := ctx
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		pctx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := h.Client.Ping(pctx).Err()
+	now := time.Now().UTC()
+
+	h.mu.Lock()
+	h.status.LastProbe = &now
+	h.status.ProbeMs = float64(time.Since(start).Nanoseconds()) / 1e6
+	var recovered []func(ctx context.Context)
+	if err != nil {
+		h.status.LastError = err.Error()
+		h.failures++
+		threshold := h.FailureThreshold
+		if threshold <= 0 {
+			threshold = 1
+		}
+		if h.status.Mode == ModeRedis && h.failures >= threshold {
+			h.status.Mode, h.status.Since = ModeFallback, now
+			h.log().Warn("redis unreachable, using process-local fallback", zap.Int("failed_probes", h.failures), zap.Error(err))
+		}
+	} else {
+		h.status.LastError = ""
+		h.failures = 0
+		if h.status.Mode == ModeFallback {
+			h.status.Mode, h.status.Since = ModeRedis, now
+			recovered = append(recovered, h.onRecover...)
+			h.log().Info("redis reachable again, leaving fallback")
+		}
+	}
+	h.mu.Unlock()
+
+	for _, fn := range recovered {
+		fn(ctx)
+	}
+}
+
+// Watch probes Redis immediately and then every interval until ctx is done.
+func (h *RedisHealth) Watch(ctx context.Context, interval time.Duration) {
+	h.Probe(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Probe(ctx)
+		}
+	}
+}
+
+func (h *RedisHealth) log() *zap.Logger {
+	if h.Logger == nil {
+		return zap.NewNop()
+	}
+	return h.Logger
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMemoryEntries bounds a Memory created with a non-positive size.
+const DefaultMemoryEntries = 10000
+
+type memoryEntry struct {
+	value   []byte
+	count   int64
+	expires time.Time
+}
+
+// Memory is a process-local key/value store with per-key TTLs, used while Redis is
+// unreachable. It holds at most MaxEntries keys; expired keys are dropped first and
+// then arbitrary ones, which is acceptable for a short-lived fallback cache.
+type Memory struct {
+	mu         sync.Mutex
+	entries    map[string]*memoryEntry
+	maxEntries int
+	now        func() time.Time
+}
+
+func NewMemory(maxEntries int) *Memory {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryEntries
+	}
+	return &Memory{entries: make(map[string]*memoryEntry), maxEntries: maxEntries, now: time.Now}
+}
+
+// Get returns the value of key, or false when it is missing or expired.
+func (m *Memory) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.live(key)
+	if e == nil || e.value == nil {
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (m *Memory) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.makeRoom(key)
+	m.entries[key] = &memoryEntry{value: value, expires: m.now().Add(ttl)}
+}
+
+// Incr increments the counter at key and returns its new value. A new counter
+// expires after ttl; incrementing does not extend it, like INCR followed by a
+// one-off EXPIRE.
+func (m *Memory) Incr(key string, ttl time.Duration) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.live(key)
+	if e == nil {
+		m.makeRoom(key)
+		e = &memoryEntry{expires: m.now().Add(ttl)}
+		m.entries[key] = e
+	}
+	e.count++
+	return e.count
+}
+
+func (m *Memory) Delete(keys ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, k := range keys {
+		delete(m.entries, k)
+	}
+}
+
+// DeletePrefix removes every key starting with prefix.
+func (m *Memory) DeletePrefix(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k := range m.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(m.entries, k)
+		}
+	}
+}
+
+// Clear removes every key.
+func (m *Memory) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*memoryEntry)
+}
+
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// live returns the entry of key, dropping it when expired. m.mu must be held.
+func (m *Memory) live(key string) *memoryEntry {
+	e, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	if !m.now().Before(e.expires) {
+		delete(m.entries, key)
+		return nil
+	}
+	return e
+}
+
+// makeRoom evicts entries so that key fits. m.mu must be held.
+func (m *Memory) makeRoom(key string) {
+	if _, ok := m.entries[key]; ok || len(m.entries) < m.maxEntries {
+		return
+	}
+	now := m.now()
+	for k, e := range m.entries {
+		if !now.Before(e.expires) {
+			delete(m.entries, k)
+		}
+	}
+	for k := range m.entries {
+		if len(m.entries) < m.maxEntries {
+			break
+		}
+		delete(m.entries, k)
+	}
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store is a JSON cache in Redis that switches to a process-local Memory while
+// Health reports Redis down. Deletes made during the outage are replayed against
+// Redis when it recovers, so entries invalidated meanwhile are not served stale.
+// A nil *Store caches nothing.
+type Store struct {
+	Redis  *redis.Client
+	Health *RedisHealth
+	Memory *Memory
+
+	mu sync.Mutex
+	// pending holds deletes made during an outage; the value is true for prefixes
+	pending map[string]bool
+}
+
+// NewStore returns a Store; health and memory may be nil, which disables the fallback.
+func NewStore(rdb *redis.Client, health *RedisHealth, memory *Memory) *Store {
+	s := &Store{Redis: rdb, Health: health, Memory: memory}
+	health.OnRecover(s.recover)
+	return s
+}
+
+// Fallback reports whether the process-local memory is in use.
+func (s *Store) Fallback() bool {
+	return s != nil && s.Memory != nil && !s.Health.Up()
+}
+
+// GetJSON reads key into out. Returns (true, nil) on hit, (false, nil) on miss.
+func (s *Store) GetJSON(ctx context.Context, key string, out any) (bool, error) {
+	if s == nil || key == "" {
+		return false, nil
+	}
+	if !s.Fallback() {
+		return GetJSON(ctx, s.Redis, key, out)
+	}
+	b, ok := s.Memory.Get(key)
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetJSON stores v as JSON under key for ttl.
+func (s *Store) SetJSON(ctx context.Context, key string, v any, ttl time.Duration) error {
+	if s == nil || key == "" || ttl <= 0 {
+		return nil
+	}
+	if !s.Fallback() {
+		return SetJSON(ctx, s.Redis, key, v, ttl)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.Memory.Set(key, b, ttl)
+	return nil
+}
+
+// Delete removes keys.
+func (s *Store) Delete(ctx context.Context, keys ...string) error {
+	if s == nil || len(keys) == 0 {
+		return nil
+	}
+	if s.Fallback() {
+		s.Memory.Delete(keys...)
+		s.remember(false, keys...)
+		return nil
+	}
+	if s.Redis == nil {
+		return nil
+	}
+	return s.Redis.Del(ctx, keys...).Err()
+}
+
+// DeletePrefix removes every key starting with prefix. In Redis this uses KEYS,
+// which is fine for the handful of cache keys this service writes.
+func (s *Store) DeletePrefix(ctx context.Context, prefix string) error {
+	if s == nil {
+		return nil
+	}
+	if s.Fallback() {
+		s.Memory.DeletePrefix(prefix)
+		s.remember(true, prefix)
+		return nil
+	}
+	if s.Redis == nil {
+		return nil
+	}
+	keys, err := s.Redis.Keys(ctx, prefix+"*").Result()
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	return s.Redis.Del(ctx, keys...).Err()
+}
+
+// Incr increments the counter at key, (re)setting its ttl. A failed Redis
+// call falls back to the process-local counter when a Memory is configured.
+func (s *Store) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	if s == nil {
+		return 0, nil
+	}
+	if !s.Fallback() && s.Redis != nil {
+		pipe := s.Redis.TxPipeline()
+		inc := pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, ttl)
+		_, err := pipe.Exec(ctx)
+		if err == nil || s.Memory == nil || ctx.Err() != nil {
+			return inc.Val(), err
+		}
+	}
+	if s.Memory == nil {
+		return 0, nil
+	}
+	return s.Memory.Incr(key, ttl), nil
+}
+
+func (s *Store) remember(prefix bool, keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil {
+		s.pending = make(map[string]bool)
+	}
+	for _, k := range keys {
+		s.pending[k] = s.pending[k] || prefix
+	}
+}
+
+// recover drops the fallback entries and replays the deletes made while Redis was
+// down. Deletes that fail are kept for the next recovery.
+func (s *Store) recover(ctx context.Context) {
+	if s.Memory != nil {
+		s.Memory.Clear()
+	}
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	for k, prefix := range pending {
+		var err error
+		if prefix {
+			err = s.DeletePrefix(ctx, k)
+		} else {
+			err = s.Redis.Del(ctx, k).Err()
+		}
+		if err != nil {
+			s.remember(prefix, k)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMemory_TTLAndEviction(t *testing.T) {
+	m := NewMemory(2)
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+	m.Set("a", []byte("1"), time.Second)
+	if n := m.Incr("c", time.Minute); n != 1 {
+		t.Fatalf("incr = %d", n)
+	}
+	if n := m.Incr("c", time.Minute); n != 2 {
+		t.Fatalf("incr = %d", n)
+	}
+	now = now.Add(2 * time.Second)
+	if _, ok := m.Get("a"); ok {
+		t.Fatal("expired key returned")
+	}
+	m.Set("b", []byte("2"), time.Minute)
+	m.Set("d", []byte("3"), time.Minute)
+	if m.Len() > 2 {
+		t.Fatalf("holds %d keys, max 2", m.Len())
+	}
+	if v, ok := m.Get("d"); !ok || string(v) != "3" {
+		t.Fatal("newest key evicted")
+	}
+}
+
+func TestStore_FallsBackWhileRedisIsDown(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	health := NewRedisHealth(rc, nil)
+	recovered := 0
+	health.OnRecover(func(context.Context) { recovered++ })
+	s := NewStore(rc, health, NewMemory(0))
+	ctx := context.Background()
+
+	if err := s.SetJSON(ctx, "content:detail:1", "before", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	mr.SetError("LOADING")
+	health.Probe(ctx)
+	if !health.Up() {
+		t.Fatal("one failed probe is below the threshold")
+	}
+	health.Probe(ctx)
+	if health.Up() || !s.Fallback() || health.Status().Mode != ModeFallback || health.Status().LastError == "" {
+		t.Fatalf("expected fallback after two failed probes, status %+v", health.Status())
+	}
+
+	var got string
+	if err := s.SetJSON(ctx, "search:go", "local", time.Minute); err != nil {
+		t.Fatalf("fallback write: %v", err)
+	}
+	if ok, err := s.GetJSON(ctx, "search:go", &got); !ok || err != nil || got != "local" {
+		t.Fatalf("fallback read: ok=%v err=%v got=%q", ok, err, got)
+	}
+	if n, err := s.Incr(ctx, "prl:1", time.Minute); err != nil || n != 1 {
+		t.Fatalf("fallback incr: %d %v", n, err)
+	}
+	// Invalidated while Redis is down; must not be served from Redis afterwards
+	if err := s.Delete(ctx, "content:detail:1"); err != nil {
+		t.Fatal(err)
+	}
+
+	mr.SetError("")
+	health.Probe(ctx)
+	if !health.Up() || recovered != 1 {
+		t.Fatalf("expected recovery, up=%v callbacks=%d", health.Up(), recovered)
+	}
+	if mr.Exists("content:detail:1") {
+		t.Fatal("delete made during the outage was not replayed")
+	}
+	if ok, _ := s.GetJSON(ctx, "search:go", &got); ok {
+		t.Fatal("fallback entry served after recovery")
+	}
+	if s.Memory.Len() != 0 {
+		t.Fatalf("fallback memory not cleared, %d keys", s.Memory.Len())
+	}
+}
+
+func TestStore_IncrFallsBackOnRedisError(t *testing.T) {
+	rc := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0", MaxRetries: -1}) // unreachable
+	s := NewStore(rc, NewRedisHealth(rc, nil), NewMemory(0))
+	for want := int64(1); want <= 2; want++ {
+		n, err := s.Incr(context.Background(), "prl:1", time.Minute)
+		if err != nil || n != want {
+			t.Fatalf("incr = %d, %v; want %d", n, err, want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"search_engine/internal/infrastructure/cache"
+)
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// ErrOpen is matched by errors.Is for every OpenError.
+var ErrOpen = errors.New("circuit open")
+
+// OpenError is returned when a provider's circuit rejects a call.
+type OpenError struct {
+	ProviderID string
+	RetryAt    time.Time
+	LastError  string
+}
+
+func (e *OpenError) Error() string {
+	msg := fmt.Sprintf("circuit open for %s until %s", e.ProviderID, e.RetryAt.UTC().Format(time.RFC3339))
+	if e.LastError != "" {
+		msg += " (last error: " + e.LastError + ")"
+	}
+	return msg
+}
+
+func (e *OpenError) Is(target error) bool { return target == ErrOpen }
+
+// Settings control when a circuit opens and how long it stays open. The circuit
+// opens once at least MinRequests calls were made in Window and FailureRate of them
+// failed, or after ConsecutiveFailures failed calls in a row; after CoolDown one
+// half-open probe is let through.
+type Settings struct {
+	Window      time.Duration
+	MinRequests int
+	FailureRate float64
+	// ConsecutiveFailures counts failures however far apart they are, so providers
+	// called less often than once per Window still trip the circuit; 0 disables it
+	ConsecutiveFailures int
+	CoolDown            time.Duration
+	// ProbeTimeout bounds how long a half-open probe blocks other callers
+	ProbeTimeout time.Duration
+}
+
+// Status is the breaker state of one provider as stored in Redis.
+type Status struct {
+	ProviderID string `json:"provider_id"`
+	State      State  `json:"state"`
+	Requests   int64  `json:"requests"`
+	Failures   int64  `json:"failures"`
+	// ConsecutiveFailures is the number of calls in a row that failed
+	ConsecutiveFailures int64      `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// RedisBreaker keeps per-provider breaker state in Redis hashes so all replicas
+// agree; transitions run as Lua scripts to stay atomic.
+type RedisBreaker struct {
+	Client   *redis.Client
+	Enabled  bool
+	Settings Settings
+	// Now overrides the clock of transitions in tests; when nil the Redis server's
+	// clock is used, so replicas with drifting clocks agree on cool-downs and windows
+	Now func() time.Time
+	// Health is optional; while it reports Redis down every circuit reads closed,
+	// as with any other Redis error, without waiting on Redis timeouts
+	Health *cache.RedisHealth
+}
+
+func NewRedisBreaker(client *redis.Client, enabled bool, settings Settings) *RedisBreaker {
+	if settings.ProbeTimeout <= 0 {
+		settings.ProbeTimeout = time.Minute
+	}
+	return &RedisBreaker{Client: client, Enabled: enabled, Settings: settings}
+}
+
+func (b *RedisBreaker) active() bool {
+	return b != nil && b.Enabled && b.Health.Up()
+}
+
+func (b *RedisBreaker) key(providerID string) string {
+	return "cb:" + providerID
+}
+
+// nowMs is the Now override in milliseconds, or 0 for the Redis server's clock.
+func (b *RedisBreaker) nowMs() int64 {
+	if b.Now == nil {
+		return 0
+	}
+	return b.Now().UnixMilli()
+}
+
+// luaNow sets now to ARGV[1], or to the Redis server time in milliseconds when it is 0.
+const luaNow = `
+local now = tonumber(ARGV[1])
+if now <= 0 then
+  local t = redis.call('TIME')
+  now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+end
+`
+
+// allowScript moves an open circuit to half-open after the cool-down and admits a
+// single probe at a time while half-open.
+var allowScript = redis.NewScript(luaNow + `
+local k = KEYS[1]
+local cooldown = tonumber(ARGV[2])
+local probe = tonumber(ARGV[3])
+local st = redis.call('HGET', k, 'state') or 'closed'
+if st == 'open' then
+  local opened = tonumber(redis.call('HGET', k, 'opened_at') or '0')
+  if now - opened < cooldown then return {0, st} end
+  redis.call('HSET', k, 'state', 'half_open', 'probe_until', now + probe)
+  return {1, 'half_open'}
+end
+if st == 'half_open' then
+  local pu = tonumber(redis.call('HGET', k, 'probe_until') or '0')
+  if now < pu then return {0, st} end
+  redis.call('HSET', k, 'probe_until', now + probe)
+  return {1, st}
+end
+return {1, 'closed'}
+`)
+
+// recordScript counts an outcome. A half-open probe closes or reopens the circuit;
+// a closed circuit opens when the failure rate of the current window or the
+// consecutive failure limit is reached.
+var recordScript = redis.NewScript(luaNow + `
+local k = KEYS[1]
+local window = tonumber(ARGV[2])
+local minreq = tonumber(ARGV[3])
+local rate = tonumber(ARGV[4])
+local ok = ARGV[5] == '1'
+local lasterr = ARGV[6]
+local maxconsec = tonumber(ARGV[7])
+local st = redis.call('HGET', k, 'state') or 'closed'
+if st == 'half_open' then
+  if ok then
+    redis.call('HSET', k, 'state', 'closed', 'requests', 0, 'failures', 0, 'consecutive', 0, 'window_start', now)
+    redis.call('HDEL', k, 'opened_at', 'probe_until', 'last_error')
+    return 'closed'
+  end
+  redis.call('HSET', k, 'state', 'open', 'opened_at', now, 'last_error', lasterr)
+  redis.call('HDEL', k, 'probe_until')
+  return 'open'
+end
+if st == 'open' then
+  if not ok then redis.call('HSET', k, 'last_error', lasterr) end
+  return 'open'
+end
+local ws = tonumber(redis.call('HGET', k, 'window_start') or '0')
+if now - ws >= window then
+  redis.call('HSET', k, 'window_start', now, 'requests', 0, 'failures', 0)
+end
+local req = redis.call('HINCRBY', k, 'requests', 1)
+local fail = tonumber(redis.call('HGET', k, 'failures') or '0')
+if ok then
+  redis.call('HSET', k, 'consecutive', 0)
+else
+  fail = redis.call('HINCRBY', k, 'failures', 1)
+  local consec = redis.call('HINCRBY', k, 'consecutive', 1)
+  redis.call('HSET', k, 'last_error', lasterr)
+  if (req >= minreq and fail / req >= rate) or (maxconsec > 0 and consec >= maxconsec) then
+    redis.call('HSET', k, 'state', 'open', 'opened_at', now)
+    return 'open'
+  end
+end
+return 'closed'
+`)
+
+// Allow reports whether a call to the provider may proceed. When it may not, the
+// returned error is an *OpenError.
+func (b *RedisBreaker) Allow(ctx context.Context, providerID string) error {
+	if !b.active() {
+		return nil
+	}
+	res, err := allowScript.Run(ctx, b.Client, []string{b.key(providerID)},
+		b.nowMs(), b.Settings.CoolDown.Milliseconds(), b.Settings.ProbeTimeout.Milliseconds()).Slice()
+	if err != nil {
+		return err
+	}
+	if len(res) == 2 && res[0] == int64(1) {
+		return nil
+	}
+	st, err := b.Status(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	oe := &OpenError{ProviderID: providerID, LastError: st.LastError, RetryAt: time.Now()}
+	if st.RetryAt != nil {
+		oe.RetryAt = *st.RetryAt
+	}
+	return oe
+}
+
+// Record counts the outcome of an allowed call; callErr nil means success.
+func (b *RedisBreaker) Record(ctx context.Context, providerID string, callErr error) (State, error) {
+	if !b.active() {
+		return StateClosed, nil
+	}
+	ok, lastErr := "1", ""
+	if callErr != nil {
+		ok, lastErr = "0", callErr.Error()
+	}
+	st, err := recordScript.Run(ctx, b.Client, []string{b.key(providerID)},
+		b.nowMs(), b.Settings.Window.Milliseconds(), b.Settings.MinRequests,
+		strconv.FormatFloat(b.Settings.FailureRate, 'f', -1, 64), ok, lastErr, b.Settings.ConsecutiveFailures).Text()
+	return State(st), err
+}
+
+// Status reads the stored state of one provider's circuit.
+func (b *RedisBreaker) Status(ctx context.Context, providerID string) (Status, error) {
+	st := Status{ProviderID: providerID, State: StateClosed}
+	if !b.active() {
+		return st, nil
+	}
+	m, err := b.Client.HGetAll(ctx, b.key(providerID)).Result()
+	if err != nil {
+		return st, err
+	}
+	if s := m["state"]; s != "" {
+		st.State = State(s)
+	}
+	st.Requests, _ = strconv.ParseInt(m["requests"], 10, 64)
+	st.Failures, _ = strconv.ParseInt(m["failures"], 10, 64)
+	st.ConsecutiveFailures, _ = strconv.ParseInt(m["consecutive"], 10, 64)
+	st.LastError = m["last_error"]
+	if ms, err := strconv.ParseInt(m["opened_at"], 10, 64); err == nil && st.State != StateClosed {
+		opened := time.UnixMilli(ms).UTC()
+		retry := opened.Add(b.Settings.CoolDown)
+		st.OpenedAt, st.RetryAt = &opened, &retry
+	}
+	return st, nil
+}
+
+// Reset closes a provider's circuit and clears its counters.
+func (b *RedisBreaker) Reset(ctx context.Context, providerID string) error {
+	if b == nil || !b.Enabled {
+		return nil
+	}
+	return b.Client.Del(ctx, b.key(providerID)).Err()
+}
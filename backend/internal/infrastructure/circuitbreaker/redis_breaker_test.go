@@ -0,0 +1,162 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestBreaker(t *testing.T) (*RedisBreaker, *time.Time) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mr.Close)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	b := NewRedisBreaker(rc, true, Settings{Window: time.Minute, MinRequests: 3, FailureRate: 0.5, CoolDown: 30 * time.Second, ProbeTimeout: 10 * time.Second})
+	b.Now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestRedisBreaker_OpensOnFailureRate(t *testing.T) {
+	b, _ := newTestBreaker(t)
+	ctx := context.Background()
+	fail := errors.New("status 503")
+	if st, _ := b.Record(ctx, "p2", fail); st != StateClosed {
+		t.Fatalf("one failure below min requests should not open, got %s", st)
+	}
+	_, _ = b.Record(ctx, "p2", nil)
+	if st, _ := b.Record(ctx, "p2", fail); st != StateOpen {
+		t.Fatalf("2 of 3 failed, expected open, got %s", st)
+	}
+	err := b.Allow(ctx, "p2")
+	var oe *OpenError
+	if !errors.As(err, &oe) || !errors.Is(err, ErrOpen) || oe.LastError != "status 503" {
+		t.Fatalf("expected OpenError with last error, got %v", err)
+	}
+	if err := b.Allow(ctx, "p1"); err != nil {
+		t.Fatalf("other providers are unaffected, got %v", err)
+	}
+}
+
+func TestRedisBreaker_HalfOpenProbe(t *testing.T) {
+	b, now := newTestBreaker(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, _ = b.Record(ctx, "p2", errors.New("timeout"))
+	}
+	*now = now.Add(31 * time.Second)
+	if err := b.Allow(ctx, "p2"); err != nil {
+		t.Fatalf("cool-down elapsed, probe should be allowed: %v", err)
+	}
+	if err := b.Allow(ctx, "p2"); !errors.Is(err, ErrOpen) {
+		t.Fatalf("only one probe at a time, got %v", err)
+	}
+	if st, _ := b.Record(ctx, "p2", errors.New("still down")); st != StateOpen {
+		t.Fatalf("failed probe should reopen, got %s", st)
+	}
+	*now = now.Add(31 * time.Second)
+	if err := b.Allow(ctx, "p2"); err != nil {
+		t.Fatalf("second probe should be allowed: %v", err)
+	}
+	if st, _ := b.Record(ctx, "p2", nil); st != StateClosed {
+		t.Fatalf("successful probe should close, got %s", st)
+	}
+	st, err := b.Status(ctx, "p2")
+	if err != nil || st.State != StateClosed || st.Failures != 0 || st.OpenedAt != nil {
+		t.Fatalf("unexpected status after close: %+v err=%v", st, err)
+	}
+}
+
+func TestRedisBreaker_WindowResetsCounters(t *testing.T) {
+	b, now := newTestBreaker(t)
+	ctx := context.Background()
+	_, _ = b.Record(ctx, "p2", errors.New("x"))
+	_, _ = b.Record(ctx, "p2", errors.New("x"))
+	*now = now.Add(2 * time.Minute)
+	if st, _ := b.Record(ctx, "p2", errors.New("x")); st != StateClosed {
+		t.Fatalf("failures from an old window should not count, got %s", st)
+	}
+}
+
+func TestRedisBreaker_Disabled(t *testing.T) {
+	b, _ := newTestBreaker(t)
+	b.Enabled = false
+	for i := 0; i < 5; i++ {
+		_, _ = b.Record(context.Background(), "p2", errors.New("x"))
+	}
+	if err := b.Allow(context.Background(), "p2"); err != nil {
+		t.Fatalf("disabled breaker must allow, got %v", err)
+	}
+}
+
+func TestRedisBreaker_OpensOnFailuresAcrossSyncIntervals(t *testing.T) {
+	b, now := newTestBreaker(t)
+	b.Settings = Settings{Window: 10 * time.Minute, MinRequests: 3, FailureRate: 0.5, ConsecutiveFailures: 3, CoolDown: 5 * time.Minute, ProbeTimeout: time.Minute}
+	ctx := context.Background()
+	fail := errors.New("status 503")
+	// Scheduled syncs run every 6h, so no window ever holds more than one call
+	for i := 1; i <= 3; i++ {
+		*now = now.Add(6 * time.Hour)
+		if err := b.Allow(ctx, "p2"); err != nil {
+			t.Fatalf("sync %d should be allowed, got %v", i, err)
+		}
+		st, _ := b.Record(ctx, "p2", fail)
+		if i < 3 && st != StateClosed {
+			t.Fatalf("after failed sync %d expected closed, got %s", i, st)
+		}
+		if i == 3 && st != StateOpen {
+			t.Fatalf("three failed syncs in a row should open the circuit, got %s", st)
+		}
+	}
+	if err := b.Allow(ctx, "p2"); !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected the next call refused, got %v", err)
+	}
+
+	// A success in between restarts the streak
+	_, _ = b.Record(ctx, "p3", fail)
+	*now = now.Add(6 * time.Hour)
+	_, _ = b.Record(ctx, "p3", fail)
+	*now = now.Add(6 * time.Hour)
+	_, _ = b.Record(ctx, "p3", nil)
+	*now = now.Add(6 * time.Hour)
+	if st, _ := b.Record(ctx, "p3", fail); st != StateClosed {
+		t.Fatalf("a success must reset the streak, got %s", st)
+	}
+	if st, _ := b.Status(ctx, "p3"); st.ConsecutiveFailures != 1 {
+		t.Fatalf("expected 1 consecutive failure, got %+v", st)
+	}
+}
+
+func TestRedisBreaker_UsesServerClock(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	mr.SetTime(now)
+	b := NewRedisBreaker(redis.NewClient(&redis.Options{Addr: mr.Addr()}), true, Settings{Window: time.Minute, MinRequests: 3, FailureRate: 0.5, CoolDown: 30 * time.Second, ProbeTimeout: 10 * time.Second})
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, _ = b.Record(ctx, "p2", errors.New("timeout"))
+	}
+	st, err := b.Status(ctx, "p2")
+	if err != nil || st.OpenedAt == nil || !st.OpenedAt.Equal(now) {
+		t.Fatalf("expected the circuit opened at the server time, got %+v err=%v", st, err)
+	}
+	mr.SetTime(now.Add(20 * time.Second))
+	if err := b.Allow(ctx, "p2"); !errors.Is(err, ErrOpen) {
+		t.Fatalf("cool-down not elapsed on the server clock, got %v", err)
+	}
+	mr.SetTime(now.Add(31 * time.Second))
+	if err := b.Allow(ctx, "p2"); err != nil {
+		t.Fatalf("cool-down elapsed on the server clock, probe should be allowed: %v", err)
+	}
+}
@@ -10,28 +10,44 @@ import (
 	"search_engine/internal/infrastructure/services"
 )
 
+// scheduleTick is how often due providers are looked for when a Schedule is set.
+const scheduleTick = time.Minute
+
 type ContentSyncJob struct {
-	Logger     *zap.Logger
-	Service    *services.ContentSyncService
-	Interval   time.Duration
-	Enabled    bool
-	MaxRetries int
-	RetryDelay time.Duration
+	Logger   *zap.Logger
+	Service  *services.ContentSyncService
+	Interval time.Duration
+	Enabled  bool
+	Timeout  time.Duration
+	// Schedule is optional; when set, each provider is synced once its own interval
+	// (Interval when it has none) has elapsed since its last run
+	Schedule interface {
+		SyncInterval(providerID string) time.Duration
+	}
+	// Leader is optional; when set, the job only runs on the replica holding it
+	Leader Leadership
+	// Lock is optional; when set, each run holds it and is skipped while it is taken
+	Lock RunLock
 
 	mu      sync.Mutex
 	running bool
 	stopCh  chan struct{}
+	// ctx is cancelled on Stop so in-flight provider calls are aborted
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
-func NewContentSyncJob(logger *zap.Logger, svc *services.ContentSyncService, interval time.Duration, enabled bool, retries int, retryDelay time.Duration) *ContentSyncJob {
+func NewContentSyncJob(logger *zap.Logger, svc *services.ContentSyncService, interval time.Duration, enabled bool, timeout time.Duration) *ContentSyncJob {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &ContentSyncJob{
-		Logger:     logger,
-		Service:    svc,
-		Interval:   interval,
-		Enabled:    enabled,
-		MaxRetries: retries,
-		RetryDelay: retryDelay,
-		stopCh:     make(chan struct{}),
+		Logger:   logger,
+		Service:  svc,
+		Interval: interval,
+		Enabled:  enabled,
+		Timeout:  timeout,
+		stopCh:   make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
@@ -39,7 +55,11 @@ func (j *ContentSyncJob) Start() {
 	if !j.Enabled {
 		return
 	}
-	ticker := time.NewTicker(j.Interval)
+	tick := j.Interval
+	if j.Schedule != nil && tick > scheduleTick {
+		tick = scheduleTick
+	}
+	ticker := time.NewTicker(tick)
 	go func() {
 		j.Logger.Info("content sync job started", zap.Duration("interval", j.Interval))
 		// Run immediately once on start to ensure data is ingested without waiting first tick
@@ -58,6 +78,7 @@ func (j *ContentSyncJob) Start() {
 }
 
 func (j *ContentSyncJob) Stop() {
+	j.cancel()
 	close(j.stopCh)
 }
 
@@ -76,17 +97,40 @@ func (j *ContentSyncJob) runOnce() {
 		j.mu.Unlock()
 	}()
 
-	var lastErr error
-	for attempt := 0; attempt <= j.MaxRetries; attempt++ {
-		_, err := j.Service.SyncAllProviders(context.Background())
-		if err == nil {
-			return
-		}
-		lastErr = err
-		j.Logger.Warn("content sync attempt failed", zap.Int("attempt", attempt+1), zap.Error(err))
-		time.Sleep(j.RetryDelay)
+	leadCtx, stopLead, ok, err := lead(j.Leader, j.Lock, j.ctx)
+	if err != nil {
+		j.Logger.Warn("content sync skipped, run lock unavailable", zap.Error(err))
+		return
+	}
+	if !ok {
+		j.Logger.Debug("content sync skipped, another replica leads or is still running it")
+		return
 	}
-	if lastErr != nil {
-		j.Logger.Error("content sync failed after retries", zap.Error(lastErr))
+	defer stopLead()
+	// Transient provider failures are retried per request inside the providers
+	ctx, cancel := j.runContext(leadCtx)
+	defer cancel()
+	if j.Schedule != nil {
+		_, err = j.Service.SyncDueProviders(ctx, j.intervalOf)
+	} else {
+		_, err = j.Service.SyncAllProviders(ctx)
+	}
+	if err != nil {
+		j.Logger.Error("content sync failed", zap.Error(err))
+	}
+}
+
+func (j *ContentSyncJob) intervalOf(providerID string) time.Duration {
+	if d := j.Schedule.SyncInterval(providerID); d > 0 {
+		return d
+	}
+	return j.Interval
+}
+
+// runContext bounds a single sync run by the job timeout, if configured.
+func (j *ContentSyncJob) runContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if j.Timeout > 0 {
+		return context.WithTimeout(parent, j.Timeout)
 	}
+	return context.WithCancel(parent)
 }
@@ -22,6 +22,9 @@ type JobInfo struct {
 	StartedAt time.Time
 	EndedAt   *time.Time
 	Error     *string
+	// Results holds the outcome of each finished part of the job, e.g. one sync
+	// result per provider, in the order they finished
+	Results []any
 }
 
 type JobManager struct {
@@ -55,9 +58,25 @@ func (m *JobManager) Update(id string, status JobStatus, progress int, errStr *s
 	}
 }
 
+// AddResult appends the outcome of a finished part of a running job and sets its progress.
+func (m *JobManager) AddResult(id string, result any, progress int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if j, ok := m.jobs[id]; ok {
+		j.Results = append(j.Results, result)
+		j.Progress = progress
+	}
+}
+
+// Get returns a copy of the job, safe to read while the job keeps running.
 func (m *JobManager) Get(id string) (*JobInfo, bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	j, ok := m.jobs[id]
-	return j, ok
+	if !ok {
+		return nil, false
+	}
+	cp := *j
+	cp.Results = append([]any(nil), j.Results...)
+	return &cp, true
 }
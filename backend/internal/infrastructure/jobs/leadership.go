@@ -0,0 +1,40 @@
+package jobs
+
+import "context"
+
+// Leadership is implemented by *leader.Lease. A job given one runs only while this
+// replica holds it, under a context cancelled as soon as the lease is lost.
+type Leadership interface {
+	Lead(parent context.Context) (context.Context, context.CancelFunc, bool)
+}
+
+// RunLock is implemented by *postgres.AdvisoryLock. A leader that stalls past its
+// lease TTL only learns it was replaced at its next renewal and may write alongside
+// the new leader until then. A job given a RunLock also holds it in the database for
+// the whole run, so another replica's run is skipped until the stalled one ends.
+type RunLock interface {
+	TryLock(parent context.Context) (context.Context, context.CancelFunc, bool, error)
+}
+
+// lead returns the context of a run, or false when another replica leads or still
+// runs the job. Jobs without a Leadership or RunLock skip the respective check. err
+// is set when the run lock could not be checked.
+func lead(l Leadership, lock RunLock, parent context.Context) (context.Context, context.CancelFunc, bool, error) {
+	ctx, stop := parent, context.CancelFunc(func() {})
+	if l != nil {
+		var ok bool
+		if ctx, stop, ok = l.Lead(parent); !ok {
+			return parent, stop, false, nil
+		}
+	}
+	if lock == nil {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, func() { cancel(); stop() }, true, nil
+	}
+	ctx, release, ok, err := lock.TryLock(ctx)
+	if !ok {
+		stop()
+		return parent, func() {}, false, err
+	}
+	return ctx, func() { release(); stop() }, true, nil
+}
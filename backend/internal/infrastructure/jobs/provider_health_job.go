@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/infrastructure/services"
+)
+
+// ProviderHealthJob probes every provider on an interval, keeping the cached
+// health-check results warm and logging providers that fail.
+type ProviderHealthJob struct {
+	Logger   *zap.Logger
+	Service  *services.ProviderHealthService
+	Interval time.Duration
+	// Leader is optional; when set, providers are only probed by the replica holding it
+	Leader Leadership
+
+	stopCh chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewProviderHealthJob(logger *zap.Logger, svc *services.ProviderHealthService, interval time.Duration) *ProviderHealthJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ProviderHealthJob{
+		Logger:   logger,
+		Service:  svc,
+		Interval: interval,
+		stopCh:   make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func (j *ProviderHealthJob) Start() {
+	if j.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(j.Interval)
+	go func() {
+		j.Logger.Info("provider health job started", zap.Duration("interval", j.Interval))
+		defer j.Logger.Info("provider health job stopped")
+		j.runOnce()
+		for {
+			select {
+			case <-ticker.C:
+				j.runOnce()
+			case <-j.stopCh:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (j *ProviderHealthJob) Stop() {
+	j.cancel()
+	close(j.stopCh)
+}
+
+func (j *ProviderHealthJob) runOnce() {
+	ctx, cancel, ok, _ := lead(j.Leader, nil, j.ctx)
+	if !ok {
+		return
+	}
+	defer cancel()
+	results, err := j.Service.Refresh(ctx)
+	if err != nil {
+		j.Logger.Error("provider health check failed", zap.Error(err))
+		return
+	}
+	for _, r := range results {
+		if r.Status == services.HealthStatusUnhealthy {
+			fields := []zap.Field{zap.String("provider", r.ProviderID), zap.Int("status_code", r.StatusCode), zap.Int64("response_time_ms", r.ResponseTimeMs)}
+			if r.Error != nil {
+				fields = append(fields, zap.String("error", *r.Error))
+			}
+			j.Logger.Warn("provider unhealthy", fields...)
+		}
+	}
+}
@@ -16,7 +16,11 @@ type ScoreRecalculationJob struct {
 	Service   *services.ScoreCalculatorService
 	BatchSize int
 	Interval  time.Duration
-	stopCh    chan struct{}
+	// Leader is optional; when set, scores are only recalculated by the replica holding it
+	Leader Leadership
+	// Lock is optional; when set, each run holds it and is skipped while it is taken
+	Lock   RunLock
+	stopCh chan struct{}
 }
 
 func NewScoreRecalculationJob(logger *zap.Logger, repo repositories.ContentRepository, svc *services.ScoreCalculatorService, batchSize int, interval time.Duration) *ScoreRecalculationJob {
@@ -52,7 +56,16 @@ func (j *ScoreRecalculationJob) Stop() {
 }
 
 func (j *ScoreRecalculationJob) runOnce() {
-	ctx := context.Background()
+	ctx, cancel, ok, err := lead(j.Leader, j.Lock, context.Background())
+	if err != nil {
+		j.Logger.Warn("score recalculation skipped, run lock unavailable", zap.Error(err))
+		return
+	}
+	if !ok {
+		j.Logger.Debug("score recalculation skipped, another replica leads or is still running it")
+		return
+	}
+	defer cancel()
 	total, err := j.Repo.CountAll(ctx)
 	if err != nil {
 		j.Logger.Error("count contents failed", zap.Error(err))
@@ -61,6 +74,10 @@ func (j *ScoreRecalculationJob) runOnce() {
 	start := time.Now()
 	var processed int64
 	for offset := 0; offset < int(total); offset += j.BatchSize {
+		if ctx.Err() != nil {
+			j.Logger.Warn("recalculation stopped, leadership lost", zap.Int64("processed", processed))
+			return
+		}
 		ids, err := j.Repo.ListIDs(ctx, offset, j.BatchSize)
 		if err != nil {
 			j.Logger.Error("list ids failed", zap.Error(err))
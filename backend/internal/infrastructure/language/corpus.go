@@ -0,0 +1,49 @@
+package language
+
+// Training text for the trigram profiles. It mirrors the catalogue: programming
+// tutorials, articles and video descriptions. Extending a sample improves
+// detection; keep both samples of similar length so neither language is favoured.
+
+const englishSample = `
+The complete beginner guide to the Go programming language. In this tutorial we will learn how to
+write clean code, how to test it and how to deploy your application to the cloud. This video shows
+the best practices that every developer should know before starting a new project. You will build a
+small web service with a database, add authentication and measure its performance.
+Learn how to use Docker and Kubernetes for modern software development. We explain the difference
+between containers and virtual machines, and why teams are moving their workloads to managed
+platforms. The article also covers the history of the project and the most common mistakes.
+Understanding data structures and algorithms is one of the most important skills for an engineer.
+This course teaches arrays, linked lists, trees, graphs and dynamic programming with simple examples
+and clear explanations. Each lesson ends with exercises that help you practice what you have learned.
+Machine learning for everyone: an introduction to neural networks, training data and evaluation.
+The author walks through a real example and discusses what works, what does not and how to improve
+the results. Read this post if you want to get started with artificial intelligence today.
+How we scaled our search engine to millions of users. The engineering team shares the lessons they
+learned while building the system, from caching and indexing to monitoring and alerting.
+Tips and tricks for writing better documentation, reviewing pull requests and working with other
+people on open source projects. These are the tools I use every day and why I recommend them.
+Getting started with React and TypeScript: components, state, hooks and testing. We will create a
+simple application step by step and explain each part of the code as we go.
+`
+
+const turkishSample = `
+Go programlama diline yeni başlayanlar için kapsamlı bir rehber. Bu eğitimde temiz kod yazmayı,
+kodu test etmeyi ve uygulamanızı buluta nasıl taşıyacağınızı öğreneceğiz. Bu videoda yeni bir projeye
+başlamadan önce her geliştiricinin bilmesi gereken en iyi uygulamaları gösteriyoruz. Veritabanı olan
+küçük bir web servisi yazacak, kimlik doğrulama ekleyecek ve performansını ölçeceksiniz.
+Modern yazılım geliştirme için Docker ve Kubernetes kullanımını öğrenin. Konteynerler ile sanal
+makineler arasındaki farkı ve ekiplerin iş yüklerini neden yönetilen platformlara taşıdığını
+anlatıyoruz. Makale ayrıca projenin geçmişini ve en sık yapılan hataları da ele alıyor.
+Veri yapıları ve algoritmaları anlamak bir mühendis için en önemli becerilerden biridir. Bu kurs
+diziler, bağlı listeler, ağaçlar, graflar ve dinamik programlama konularını basit örneklerle ve açık
+anlatımlarla öğretiyor. Her ders öğrendiklerinizi pekiştirmenize yardımcı olan alıştırmalarla bitiyor.
+Herkes için makine öğrenmesi: yapay sinir ağlarına, eğitim verisine ve değerlendirmeye giriş. Yazar
+gerçek bir örnek üzerinden ilerliyor, neyin işe yarayıp neyin yaramadığını ve sonuçların nasıl
+iyileştirileceğini tartışıyor. Yapay zekaya bugün başlamak istiyorsanız bu yazıyı okuyun.
+Arama motorumuzu milyonlarca kullanıcıya nasıl ölçeklendirdik. Mühendislik ekibi sistemi kurarken
+önbellekten indekslemeye, izlemeden uyarılara kadar öğrendikleri dersleri paylaşıyor.
+Daha iyi dokümantasyon yazmak, değişiklikleri incelemek ve açık kaynak projelerde başkalarıyla
+çalışmak için ipuçları. Her gün kullandığım araçlar ve onları neden önerdiğim bu yazıda.
+React ve TypeScript ile başlangıç: bileşenler, durum, kancalar ve testler. Basit bir uygulamayı adım
+adım oluşturacak ve kodun her bölümünü ilerledikçe açıklayacağız.
+`
@@ -0,0 +1,145 @@
+// Package language detects the language of content text and maps languages to
+// PostgreSQL text search configurations.
+package language
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	English = "en"
+	Turkish = "tr"
+	// Undetermined marks text whose language could not be told apart; it is
+	// indexed with the "simple" configuration, without stemming
+	Undetermined = "und"
+)
+
+// Supported lists the detectable languages in a stable order.
+var Supported = []string{English, Turkish}
+
+const (
+	// minLetters is the shortest text, in letters, that detection is attempted on
+	minLetters = 12
+	// minMargin is the per-trigram log-likelihood lead the winner needs
+	minMargin = 0.1
+)
+
+type profile struct {
+	lang   string
+	logp   map[string]float64
+	unseen float64
+}
+
+var profiles = []profile{
+	newProfile(English, englishSample),
+	newProfile(Turkish, turkishSample),
+}
+
+// newProfile estimates add-one smoothed trigram probabilities from sample.
+func newProfile(lang, sample string) profile {
+	counts := make(map[string]int)
+	total := 0
+	for _, g := range trigrams(sample) {
+		counts[g]++
+		total++
+	}
+	// The vocabulary term leaves room for trigrams the sample never shows
+	denom := float64(total + len(counts) + 1000)
+	p := profile{lang: lang, logp: make(map[string]float64, len(counts)), unseen: math.Log(1 / denom)}
+	for g, n := range counts {
+		p.logp[g] = math.Log(float64(n+1) / denom)
+	}
+	return p
+}
+
+// Detect returns the language of text: English, Turkish, or Undetermined when
+// the text is too short or the trigram evidence is too close to call.
+func Detect(text string) string {
+	grams := trigrams(text)
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters < minLetters || len(grams) == 0 {
+		return Undetermined
+	}
+	best, second := math.Inf(-1), math.Inf(-1)
+	lang := Undetermined
+	for _, p := range profiles {
+		score := 0.0
+		for _, g := range grams {
+			if lp, ok := p.logp[g]; ok {
+				score += lp
+			} else {
+				score += p.unseen
+			}
+		}
+		switch {
+		case score > best:
+			best, second, lang = score, best, p.lang
+		case score > second:
+			second = score
+		}
+	}
+	if (best-second)/float64(len(grams)) < minMargin {
+		return Undetermined
+	}
+	return lang
+}
+
+// Normalize maps a provider or request language value such as "tr", "TR",
+// "tur", "Turkish" or "en-US" to a supported code, or "" when it is not supported.
+func Normalize(value string) string {
+	v := strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(v, "-_"); i > 0 {
+		v = v[:i]
+	}
+	switch v {
+	case "en", "eng", "english":
+		return English
+	case "tr", "tur", "turkish", "türkçe", "turkce":
+		return Turkish
+	case Undetermined:
+		return Undetermined
+	}
+	return ""
+}
+
+// TSConfig returns the PostgreSQL text search configuration of a language code.
+// It must agree with the content_ts_config SQL function.
+func TSConfig(lang string) string {
+	switch lang {
+	case English:
+		return "english"
+	case Turkish:
+		return "turkish"
+	}
+	return "simple"
+}
+
+// trigrams returns the letter trigrams of text, words padded with spaces.
+func trigrams(text string) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(lower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		rs := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(rs); i++ {
+			out = append(out, string(rs[i:i+3]))
+		}
+	}
+	return out
+}
+
+// lower lowercases text; the dotted capital İ becomes i rather than i plus a
+// combining dot, so Turkish words share trigrams regardless of case.
+func lower(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == 'İ' {
+			return 'i'
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
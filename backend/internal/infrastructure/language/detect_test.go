@@ -0,0 +1,39 @@
+package language
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"Advanced Go Concurrency Patterns", English},
+		{"Understanding the JavaScript event loop", English},
+		{"How to write a great README", English},
+		{"Yeni başlayanlar için Go programlama", Turkish},
+		{"Veritabanı performansını artırmanın yolları", Turkish},
+		{"Docker nedir, nasıl kullanılır?", Turkish},
+		// Turkish typed without Turkish letters
+		{"Yeni baslayanlar icin Python dersleri", Turkish},
+		{"İLERİ SEVİYE GO EĞİTİMİ VE ÖRNEKLER", Turkish},
+		{"Python", Undetermined},
+		{"Kubernetes Docker Go", Undetermined},
+		{"", Undetermined},
+	}
+	for _, c := range cases {
+		if got := Detect(c.text); got != c.want {
+			t.Errorf("Detect(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestNormalizeAndTSConfig(t *testing.T) {
+	for in, want := range map[string]string{"TR": Turkish, "tur": Turkish, "Turkish": Turkish, "en-US": English, "english": English, "de": "", "": ""} {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if TSConfig(Turkish) != "turkish" || TSConfig(English) != "english" || TSConfig(Undetermined) != "simple" {
+		t.Fatal("unexpected text search configurations")
+	}
+}
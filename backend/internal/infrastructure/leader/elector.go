@@ -0,0 +1,334 @@
+package leader
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Status describes one lease as stored in Redis, as reported by the admin API.
+type Status struct {
+	Name string `json:"name"`
+	// Owner is the replica holding the lease; empty while it is free
+	Owner string `json:"owner,omitempty"`
+	// Token numbers the holders of the lease; it grows with every new holder and only
+	// tells takeovers apart, nothing checks it
+	Token      int64      `json:"token,omitempty"`
+	AcquiredAt *time.Time `json:"acquired_at,omitempty"`
+	// ExpiresInMs is how long the lease lasts without another renewal
+	ExpiresInMs int64 `json:"expires_in_ms,omitempty"`
+	// Held is set when the replica answering holds the lease
+	Held bool `json:"held"`
+}
+
+// Elector campaigns for named leases in Redis so each scheduled job runs on one
+// replica at a time. A lease is a key owned by one replica that expires TTL after its
+// last renewal; every RenewEvery the elector renews the leases it holds and claims
+// the free ones. Each new holder gets a higher token. While Redis cannot be reached,
+// held leases are given up once they expire, so no replica runs the jobs rather than
+// all of them. A lease only decides which replica starts a run: a holder that stalls
+// past the TTL notices the loss at its next renewal, so jobs writing to the database
+// also hold a database lock while they run (see jobs.RunLock).
+type Elector struct {
+	Client *redis.Client
+	Logger *zap.Logger
+	// ID names this replica as lease owner
+	ID string
+	// TTL is how long a lease outlives its last renewal; a crashed leader is replaced
+	// within TTL plus RenewEvery
+	TTL time.Duration
+	// RenewEvery is how often held leases are renewed and free ones claimed
+	RenewEvery time.Duration
+
+	mu     sync.Mutex
+	leases []*Lease
+	// ctx is set while the campaign runs
+	ctx    context.Context
+	stopCh chan struct{}
+	done   chan struct{}
+}
+
+// defaultTTL is the lease TTL when none is configured
+const defaultTTL = 15 * time.Second
+
+// NewElector returns an elector owning leases as id; ttl <= 0 uses 15s. Leases are
+// renewed three times per TTL.
+func NewElector(client *redis.Client, id string, ttl time.Duration, logger *zap.Logger) *Elector {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &Elector{
+		Client:     client,
+		Logger:     logger,
+		ID:         id,
+		TTL:        ttl,
+		RenewEvery: ttl / 3,
+	}
+}
+
+// DefaultID names a replica by its host name plus a random suffix, so two processes
+// on one host never share leases.
+func DefaultID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "replica"
+	}
+	return host + "-" + uuid.NewString()[:8]
+}
+
+func leaseKey(name string) string { return "leader:" + name }
+func tokenKey(name string) string { return "leader:" + name + ":token" }
+
+// acquireScript renews the lease when ARGV[1] holds it and claims it, with the next
+// token, when it is free. It returns {1, token} or {0, 0}.
+var acquireScript = redis.NewScript(`
+local owner = redis.call('HGET', KEYS[1], 'owner')
+if owner == ARGV[1] then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+  return {1, tonumber(redis.call('HGET', KEYS[1], 'token'))}
+end
+if owner then
+  return {0, 0}
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('HSET', KEYS[1], 'owner', ARGV[1], 'token', token, 'acquired_at', ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return {1, token}
+`)
+
+// releaseScript frees the lease if ARGV[1] still holds it.
+var releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lease returns the lease for name, adding it to the campaign. Once the campaign
+// runs, a new lease is claimed right away, so a job started next sees it held.
+func (e *Elector) Lease(name string) *Lease {
+	e.mu.Lock()
+	for _, l := range e.leases {
+		if l.name == name {
+			e.mu.Unlock()
+			return l
+		}
+	}
+	l := &Lease{e: e, name: name}
+	e.leases = append(e.leases, l)
+	ctx := e.ctx
+	e.mu.Unlock()
+	if ctx != nil {
+		rctx, cancel := context.WithTimeout(ctx, e.RenewEvery)
+		l.refresh(rctx)
+		cancel()
+	}
+	return l
+}
+
+// Start claims the free leases once, then keeps renewing and claiming in the
+// background until Stop.
+func (e *Elector) Start(ctx context.Context) {
+	e.mu.Lock()
+	e.ctx = ctx
+	e.mu.Unlock()
+	e.stopCh = make(chan struct{})
+	e.done = make(chan struct{})
+	e.campaign(ctx)
+	ticker := time.NewTicker(e.RenewEvery)
+	go func() {
+		defer close(e.done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				e.campaign(ctx)
+			case <-e.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the campaign and releases the held leases, so another replica takes
+// over at its next renewal instead of after the TTL.
+func (e *Elector) Stop() {
+	if e.stopCh == nil {
+		return
+	}
+	close(e.stopCh)
+	<-e.done
+	e.mu.Lock()
+	e.ctx = nil
+	e.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), e.RenewEvery)
+	defer cancel()
+	for _, l := range e.snapshot() {
+		l.release(ctx)
+	}
+}
+
+func (e *Elector) snapshot() []*Lease {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*Lease(nil), e.leases...)
+}
+
+func (e *Elector) campaign(ctx context.Context) {
+	for _, l := range e.snapshot() {
+		rctx, cancel := context.WithTimeout(ctx, e.RenewEvery)
+		l.refresh(rctx)
+		cancel()
+	}
+}
+
+// Status reads every lease of the campaign from Redis.
+func (e *Elector) Status(ctx context.Context) ([]Status, error) {
+	leases := e.snapshot()
+	out := make([]Status, 0, len(leases))
+	for _, l := range leases {
+		key := leaseKey(l.name)
+		vals, err := e.Client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		st := Status{Name: l.name, Owner: vals["owner"]}
+		st.Token, _ = strconv.ParseInt(vals["token"], 10, 64)
+		if t, err := time.Parse(time.RFC3339, vals["acquired_at"]); err == nil {
+			st.AcquiredAt = &t
+		}
+		if st.Owner != "" {
+			ttl, err := e.Client.PTTL(ctx, key).Result()
+			if err != nil {
+				return nil, err
+			}
+			st.ExpiresInMs = ttl.Milliseconds()
+		}
+		st.Held = st.Owner == e.ID && l.Held()
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+func (e *Elector) log() *zap.Logger {
+	if e.Logger == nil {
+		return zap.NewNop()
+	}
+	return e.Logger
+}
+
+// Lease is one named lock the elector campaigns for.
+type Lease struct {
+	e    *Elector
+	name string
+
+	mu         sync.Mutex
+	token      int64
+	validUntil time.Time
+	// lost is closed when a held lease is lost; nil while the lease is not held
+	lost chan struct{}
+}
+
+// Held reports whether this replica holds the lease.
+func (l *Lease) Held() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.heldLocked()
+}
+
+func (l *Lease) heldLocked() bool {
+	return l.lost != nil && time.Now().Before(l.validUntil)
+}
+
+// Token returns the token of the held lease, or 0.
+func (l *Lease) Token() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.heldLocked() {
+		return 0
+	}
+	return l.token
+}
+
+// Lead reports whether this replica holds the lease. When it does, the returned
+// context is derived from parent and cancelled as soon as the lease is lost, so a
+// run cannot outlast its leadership; cancel must be called when the run ends.
+func (l *Lease) Lead(parent context.Context) (context.Context, context.CancelFunc, bool) {
+	l.mu.Lock()
+	held, lost := l.heldLocked(), l.lost
+	l.mu.Unlock()
+	if !held {
+		return parent, func() {}, false
+	}
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-lost:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel, true
+}
+
+// refresh renews or claims the lease. A renewal that fails keeps the lease until it
+// would have expired, since Redis may still hold it for this replica.
+func (l *Lease) refresh(ctx context.Context) {
+	e := l.e
+	start := time.Now()
+	res, err := acquireScript.Run(ctx, e.Client, []string{leaseKey(l.name), tokenKey(l.name)},
+		e.ID, e.TTL.Milliseconds(), start.UTC().Format(time.RFC3339)).Int64Slice()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err != nil || len(res) != 2 {
+		if l.lost != nil && !start.Before(l.validUntil) {
+			l.dropLocked()
+			e.log().Warn("leader lease expired, renewal failed", zap.String("lease", l.name), zap.Error(err))
+		} else {
+			e.log().Debug("leader lease refresh failed", zap.String("lease", l.name), zap.Error(err))
+		}
+		return
+	}
+	if res[0] == 1 {
+		if l.lost == nil || res[1] != l.token {
+			if l.lost != nil {
+				l.dropLocked()
+			}
+			l.lost = make(chan struct{})
+			e.log().Info("leader lease acquired", zap.String("lease", l.name), zap.String("owner", e.ID), zap.Int64("token", res[1]))
+		}
+		l.token = res[1]
+		l.validUntil = start.Add(e.TTL)
+		return
+	}
+	if l.lost != nil {
+		l.dropLocked()
+		e.log().Warn("leader lease lost to another replica", zap.String("lease", l.name))
+	}
+}
+
+func (l *Lease) dropLocked() {
+	close(l.lost)
+	l.lost = nil
+	l.token = 0
+}
+
+func (l *Lease) release(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.lost == nil {
+		return
+	}
+	if err := releaseScript.Run(ctx, l.e.Client, []string{leaseKey(l.name)}, l.e.ID).Err(); err != nil {
+		l.e.log().Warn("leader lease release failed", zap.String("lease", l.name), zap.Error(err))
+	}
+	l.dropLocked()
+}
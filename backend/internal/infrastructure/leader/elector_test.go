@@ -0,0 +1,88 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestElectors(t *testing.T) (*miniredis.Miniredis, *Elector, *Elector) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mr.Close)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return mr, NewElector(client, "a", time.Minute, nil), NewElector(client, "b", time.Minute, nil)
+}
+
+func TestElector_OneLeaderAndFailover(t *testing.T) {
+	_, a, b := newTestElectors(t)
+	ctx := context.Background()
+	la, lb := a.Lease("content-sync"), b.Lease("content-sync")
+
+	a.campaign(ctx)
+	b.campaign(ctx)
+	if !la.Held() || lb.Held() {
+		t.Fatalf("expected only a to lead, a=%v b=%v", la.Held(), lb.Held())
+	}
+	if la.Token() != 1 {
+		t.Fatalf("expected token 1, got %d", la.Token())
+	}
+	if _, _, ok := lb.Lead(ctx); ok {
+		t.Fatalf("follower must not run the job")
+	}
+
+	// Renewal keeps the lease and its token
+	a.campaign(ctx)
+	if !la.Held() || la.Token() != 1 {
+		t.Fatalf("expected renewal to keep token 1, got held=%v token=%d", la.Held(), la.Token())
+	}
+
+	// A released lease is taken over at the next campaign with a higher token
+	la.release(ctx)
+	b.campaign(ctx)
+	if la.Held() || !lb.Held() || lb.Token() != 2 {
+		t.Fatalf("expected b to take over with token 2, a=%v b=%v token=%d", la.Held(), lb.Held(), lb.Token())
+	}
+}
+
+func TestElector_LostLeaseCancelsRun(t *testing.T) {
+	mr, a, b := newTestElectors(t)
+	ctx := context.Background()
+	la, lb := a.Lease("score-recalculation"), b.Lease("score-recalculation")
+	a.campaign(ctx)
+	runCtx, cancel, ok := la.Lead(ctx)
+	if !ok {
+		t.Fatalf("expected a to lead")
+	}
+	defer cancel()
+
+	// a stalls past the TTL and b claims the lease
+	mr.FastForward(2 * time.Minute)
+	b.campaign(ctx)
+	a.campaign(ctx)
+	if !lb.Held() || la.Held() {
+		t.Fatalf("expected b to lead after expiry, a=%v b=%v", la.Held(), lb.Held())
+	}
+	select {
+	case <-runCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected the run context cancelled when the lease was lost")
+	}
+
+	st, err := b.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(st) != 1 || st[0].Owner != "b" || !st[0].Held || st[0].Token != 2 || st[0].ExpiresInMs <= 0 {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	if st, _ := a.Status(ctx); st[0].Held {
+		t.Fatalf("expected a to report the lease as not held, got %+v", st)
+	}
+}
@@ -0,0 +1,172 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"search_engine/internal/domain/entities"
+	domainp "search_engine/internal/domain/providers"
+)
+
+// Builder turns provider registry rows into providers. Its fields are the
+// process-wide defaults; a row's own timeout and rate limit take precedence.
+type Builder struct {
+	Timeout   time.Duration
+	PageSize  int
+	MaxPages  int
+	PageDelay time.Duration
+	Retry     RetryPolicy
+	// MaxPayloadBytes caps a single provider response; 0 keeps DefaultMaxPayloadBytes
+	MaxPayloadBytes int64
+	// DefaultReadingTime is given to JSON provider articles without a reading time
+	DefaultReadingTime int
+}
+
+// Build validates row and returns the provider it describes.
+func (b Builder) Build(row entities.Provider) (domainp.IContentProvider, error) {
+	if strings.TrimSpace(row.ID) == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	if row.RateLimitPerMinute < 0 || row.Timeout < 0 || row.SyncInterval < 0 {
+		return nil, fmt.Errorf("provider %q: rate limit, timeout and sync interval must not be negative", row.ID)
+	}
+	timeout := b.Timeout
+	if row.Timeout > 0 {
+		timeout = row.Timeout
+	}
+	if row.Kind != entities.ProviderKindGeneric && row.Kind != entities.ProviderKindFile {
+		if err := checkHTTPURL(row.BaseURL); err != nil {
+			return nil, fmt.Errorf("provider %q: base_url %w", row.ID, err)
+		}
+	}
+	var transport *TransportConfig
+	if len(row.Transport) > 0 && string(row.Transport) != "null" {
+		if row.Kind == entities.ProviderKindFile {
+			return nil, fmt.Errorf("provider %q: transport does not apply to kind file", row.ID)
+		}
+		cfg, err := ParseTransportConfig(row.Transport)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", row.ID, err)
+		}
+		transport = &cfg
+	}
+	client := func() (*http.Client, error) {
+		c, err := NewHTTPClient(timeout, *transport)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", row.ID, err)
+		}
+		return c, nil
+	}
+	switch row.Kind {
+	case entities.ProviderKindJSON:
+		p := NewJSONProvider(row.BaseURL, timeout)
+		if b.MaxPayloadBytes > 0 {
+			p.MaxPayloadBytes = b.MaxPayloadBytes
+		}
+		if transport != nil {
+			c, err := client()
+			if err != nil {
+				return nil, err
+			}
+			p.Client = c
+		}
+		p.Provider, p.Limit, p.MaxPages, p.PageDelay, p.Retry = row.ID, b.PageSize, b.MaxPages, b.PageDelay, b.Retry
+		p.DefaultReadingTime = b.DefaultReadingTime
+		if row.RateLimitPerMinute > 0 {
+			p.RequestsPerMinute = row.RateLimitPerMinute
+		}
+		return p, nil
+	case entities.ProviderKindXML:
+		p := NewXMLProvider(row.BaseURL, timeout)
+		if b.MaxPayloadBytes > 0 {
+			p.MaxPayloadBytes = b.MaxPayloadBytes
+		}
+		if transport != nil {
+			c, err := client()
+			if err != nil {
+				return nil, err
+			}
+			p.Client = c
+		}
+		p.Provider, p.Size, p.MaxPages, p.PageDelay, p.Retry = row.ID, b.PageSize, b.MaxPages, b.PageDelay, b.Retry
+		if row.RateLimitPerMinute > 0 {
+			p.RequestsPerMinute = row.RateLimitPerMinute
+		}
+		return p, nil
+	case entities.ProviderKindFeed:
+		p := NewFeedProvider(row.ID, row.BaseURL, timeout)
+		if b.MaxPayloadBytes > 0 {
+			p.MaxPayloadBytes = b.MaxPayloadBytes
+		}
+		if transport != nil {
+			c, err := client()
+			if err != nil {
+				return nil, err
+			}
+			p.Client = c
+		}
+		p.Retry = b.Retry
+		if row.RateLimitPerMinute > 0 {
+			p.RequestsPerMinute = row.RateLimitPerMinute
+		}
+		return p, nil
+	case entities.ProviderKindFile:
+		if strings.TrimSpace(row.BaseURL) == "" {
+			return nil, fmt.Errorf("provider %q: base_url must be the import path", row.ID)
+		}
+		p := NewFileProvider(row.ID, row.BaseURL)
+		if row.RateLimitPerMinute > 0 {
+			p.RequestsPerMinute = row.RateLimitPerMinute
+		}
+		return p, nil
+	case entities.ProviderKindGeneric:
+		if len(row.Definition) == 0 {
+			return nil, fmt.Errorf("provider %q: definition is required for kind generic", row.ID)
+		}
+		var def ProviderDefinition
+		if err := yaml.Unmarshal(row.Definition, &def); err != nil {
+			return nil, fmt.Errorf("provider %q: definition: %w", row.ID, err)
+		}
+		// The row owns the ID and, when set, the base URL and rate limit
+		def.ID = row.ID
+		if row.BaseURL != "" {
+			def.BaseURL = row.BaseURL
+		}
+		if row.RateLimitPerMinute > 0 {
+			def.RateLimitPerMinute = row.RateLimitPerMinute
+		}
+		if err := def.Validate(); err != nil {
+			return nil, err
+		}
+		if transport == nil {
+			transport = def.Transport
+		}
+		p := NewGenericProvider(def, timeout)
+		if b.MaxPayloadBytes > 0 {
+			p.MaxPayloadBytes = b.MaxPayloadBytes
+		}
+		p.Retry = b.Retry
+		if transport != nil {
+			c, err := client()
+			if err != nil {
+				return nil, err
+			}
+			p.Client = c
+		}
+		return p, nil
+	}
+	return nil, fmt.Errorf("provider %q: unknown kind %q", row.ID, row.Kind)
+}
+
+func checkHTTPURL(s string) error {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) URL")
+	}
+	return nil
+}
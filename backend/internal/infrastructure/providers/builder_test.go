@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"search_engine/internal/domain/entities"
+)
+
+func TestBuilder_BuildsEveryKind(t *testing.T) {
+	b := Builder{Timeout: 10 * time.Second, PageSize: 25, Retry: RetryPolicy{MaxAttempts: 2}}
+
+	p, err := b.Build(entities.Provider{ID: "news", Kind: entities.ProviderKindJSON, BaseURL: "http://example.com", RateLimitPerMinute: 30, Timeout: 3 * time.Second})
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	jp := p.(*JSONProvider)
+	if jp.GetProviderID() != "news" || jp.GetRateLimit().RequestsPerMinute != 30 || jp.Client.Timeout != 3*time.Second || jp.Limit != 25 || jp.Retry.MaxAttempts != 2 {
+		t.Fatalf("json provider not configured from row: %+v", jp)
+	}
+
+	p, err = b.Build(entities.Provider{ID: "p2", Kind: entities.ProviderKindXML, BaseURL: "http://example.com"})
+	if err != nil {
+		t.Fatalf("xml: %v", err)
+	}
+	if p.GetRateLimit().RequestsPerMinute != 80 || p.(*XMLProvider).Client.Timeout != 10*time.Second {
+		t.Fatal("xml provider should keep the kind's rate limit and the default timeout")
+	}
+
+	if _, err := b.Build(entities.Provider{ID: "blog", Kind: entities.ProviderKindFeed, BaseURL: "https://example.com/rss"}); err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	if _, err := b.Build(entities.Provider{ID: "import", Kind: entities.ProviderKindFile, BaseURL: "/data/import"}); err != nil {
+		t.Fatalf("file: %v", err)
+	}
+
+	def := []byte(`{"id":"ignored","base_url":"http://example.com","format":"json","items_path":"items","fields":{"id":"id","title":"title"}}`)
+	p, err = b.Build(entities.Provider{ID: "p3", Kind: entities.ProviderKindGeneric, RateLimitPerMinute: 12, Definition: def})
+	if err != nil {
+		t.Fatalf("generic: %v", err)
+	}
+	if p.GetProviderID() != "p3" || p.GetRateLimit().RequestsPerMinute != 12 {
+		t.Fatalf("row should override the definition's id and rate limit, got %s %d", p.GetProviderID(), p.GetRateLimit().RequestsPerMinute)
+	}
+}
+
+func TestBuilder_RejectsInvalidRows(t *testing.T) {
+	b := Builder{Timeout: time.Second}
+	cases := map[string]entities.Provider{
+		"missing id":        {Kind: entities.ProviderKindJSON, BaseURL: "http://example.com"},
+		"unknown kind":      {ID: "x", Kind: "soap", BaseURL: "http://example.com"},
+		"bad url":           {ID: "x", Kind: entities.ProviderKindXML, BaseURL: "example.com/feed"},
+		"negative limit":    {ID: "x", Kind: entities.ProviderKindJSON, BaseURL: "http://example.com", RateLimitPerMinute: -1},
+		"file without path": {ID: "x", Kind: entities.ProviderKindFile},
+		"generic no def":    {ID: "x", Kind: entities.ProviderKindGeneric, BaseURL: "http://example.com"},
+		"generic bad def":   {ID: "x", Kind: entities.ProviderKindGeneric, Definition: []byte(`{"format":"csv"}`)},
+	}
+	for name, row := range cases {
+		if _, err := b.Build(row); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
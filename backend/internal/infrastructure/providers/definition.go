@@ -0,0 +1,219 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderDefinition declares an HTTP feed and how its items map to ProviderContent.
+// Field paths are dot-separated keys into the decoded document, e.g. "metrics.views";
+// for XML feeds the keys are element names relative to the document element.
+type ProviderDefinition struct {
+	ID      string `json:"id" yaml:"id"`
+	BaseURL string `json:"base_url" yaml:"base_url"`
+	// Path is appended to BaseURL, e.g. "/contents"
+	Path string `json:"path" yaml:"path"`
+	// Format is "json" or "xml"
+	Format string `json:"format" yaml:"format"`
+	// Query holds static query parameters sent with every request
+	Query map[string]string `json:"query,omitempty" yaml:"query,omitempty"`
+	// ItemsPath locates the item list, e.g. "contents" or "items.item"
+	ItemsPath string       `json:"items_path" yaml:"items_path"`
+	Fields    FieldMapping `json:"fields" yaml:"fields"`
+	// TypeValues maps provider type values to "video" or "text". Items without a type
+	// are text; other unmapped values fail validation and are quarantined
+	TypeValues map[string]string `json:"type_values,omitempty" yaml:"type_values,omitempty"`
+	// DateFormats are tried after RFC3339 when parsing published_at
+	DateFormats        []string       `json:"date_formats,omitempty" yaml:"date_formats,omitempty"`
+	Pagination         PaginationSpec `json:"pagination" yaml:"pagination"`
+	RateLimitPerMinute int            `json:"rate_limit_per_minute" yaml:"rate_limit_per_minute"`
+	// SinceParam, when set, is sent with the newest published_at already stored so the
+	// feed returns only changed items; SinceFormat is its time layout (default RFC3339)
+	SinceParam  string `json:"since_param,omitempty" yaml:"since_param,omitempty"`
+	SinceFormat string `json:"since_format,omitempty" yaml:"since_format,omitempty"`
+	// Transport holds auth and HTTP settings; a registry row's transport takes precedence
+	Transport *TransportConfig `json:"transport,omitempty" yaml:"transport,omitempty"`
+}
+
+// FieldMapping holds the item-relative path of every mapped field. URLTemplate and
+// ThumbnailTemplate are used when the item has no URL field; {id} and {type} are substituted.
+// Duration values may be "mm:ss", "hh:mm:ss" or a number of seconds.
+type FieldMapping struct {
+	ID                string `json:"id" yaml:"id"`
+	Title             string `json:"title" yaml:"title"`
+	Type              string `json:"type,omitempty" yaml:"type,omitempty"`
+	Description       string `json:"description,omitempty" yaml:"description,omitempty"`
+	URL               string `json:"url,omitempty" yaml:"url,omitempty"`
+	URLTemplate       string `json:"url_template,omitempty" yaml:"url_template,omitempty"`
+	ThumbnailURL      string `json:"thumbnail_url,omitempty" yaml:"thumbnail_url,omitempty"`
+	ThumbnailTemplate string `json:"thumbnail_template,omitempty" yaml:"thumbnail_template,omitempty"`
+	PublishedAt       string `json:"published_at,omitempty" yaml:"published_at,omitempty"`
+	Views             string `json:"views,omitempty" yaml:"views,omitempty"`
+	Likes             string `json:"likes,omitempty" yaml:"likes,omitempty"`
+	ReadingTime       string `json:"reading_time,omitempty" yaml:"reading_time,omitempty"`
+	Reactions         string `json:"reactions,omitempty" yaml:"reactions,omitempty"`
+	Duration          string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Comments          string `json:"comments,omitempty" yaml:"comments,omitempty"`
+	Tags              string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Language holds a language code or name such as "tr"; unmapped items are detected
+	Language string `json:"language,omitempty" yaml:"language,omitempty"`
+}
+
+const (
+	PaginationNone   = "none"
+	PaginationOffset = "offset"
+	PaginationPage   = "page"
+)
+
+// PaginationSpec describes how pages are requested. Style "offset" sends
+// limit/offset parameters, "page" sends page/size parameters, "none" fetches once.
+type PaginationSpec struct {
+	Style       string `json:"style" yaml:"style"`
+	LimitParam  string `json:"limit_param,omitempty" yaml:"limit_param,omitempty"`
+	OffsetParam string `json:"offset_param,omitempty" yaml:"offset_param,omitempty"`
+	PageParam   string `json:"page_param,omitempty" yaml:"page_param,omitempty"`
+	SizeParam   string `json:"size_param,omitempty" yaml:"size_param,omitempty"`
+	StartPage   int    `json:"start_page,omitempty" yaml:"start_page,omitempty"`
+	PageSize    int    `json:"page_size,omitempty" yaml:"page_size,omitempty"`
+	MaxPages    int    `json:"max_pages,omitempty" yaml:"max_pages,omitempty"`
+	// TotalPath optionally locates the total item count in the response
+	TotalPath string `json:"total_path,omitempty" yaml:"total_path,omitempty"`
+	// Delay is the minimum pause between pages, e.g. "500ms"; the rate limit may raise it
+	Delay string `json:"delay,omitempty" yaml:"delay,omitempty"`
+}
+
+// Validate checks required fields and fills defaults.
+func (d *ProviderDefinition) Validate() error {
+	var errs []string
+	if strings.TrimSpace(d.ID) == "" {
+		errs = append(errs, "id is required")
+	}
+	if strings.TrimSpace(d.BaseURL) == "" {
+		errs = append(errs, "base_url is required")
+	}
+	d.Format = strings.ToLower(strings.TrimSpace(d.Format))
+	if d.Format != "json" && d.Format != "xml" {
+		errs = append(errs, "format must be json or xml")
+	}
+	if d.ItemsPath == "" {
+		errs = append(errs, "items_path is required")
+	}
+	if d.Fields.ID == "" || d.Fields.Title == "" {
+		errs = append(errs, "fields.id and fields.title are required")
+	}
+	p := &d.Pagination
+	if p.Style == "" {
+		p.Style = PaginationNone
+	}
+	switch p.Style {
+	case PaginationNone:
+	case PaginationOffset:
+		if p.LimitParam == "" {
+			p.LimitParam = "limit"
+		}
+		if p.OffsetParam == "" {
+			p.OffsetParam = "offset"
+		}
+	case PaginationPage:
+		if p.PageParam == "" {
+			p.PageParam = "page"
+		}
+		if p.SizeParam == "" {
+			p.SizeParam = "size"
+		}
+		if p.StartPage <= 0 {
+			p.StartPage = 1
+		}
+	default:
+		errs = append(errs, "pagination.style must be none, offset or page")
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = defaultPageSize
+	}
+	if p.MaxPages == 0 {
+		p.MaxPages = defaultMaxPages
+	}
+	if p.Delay != "" {
+		if _, err := time.ParseDuration(p.Delay); err != nil {
+			errs = append(errs, "pagination.delay must be a duration")
+		}
+	}
+	if d.Transport != nil {
+		if err := d.Transport.Validate(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("provider definition %q: %s", d.ID, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ParseProviderDefinition decodes a single definition; YAML is a superset of JSON.
+func ParseProviderDefinition(data []byte) (ProviderDefinition, error) {
+	var d ProviderDefinition
+	if err := yaml.Unmarshal(data, &d); err != nil {
+		return d, err
+	}
+	return d, d.Validate()
+}
+
+// LoadProviderDefinitions reads one definition file, or every .yaml/.yml/.json file
+// in a directory, sorted by name.
+func LoadProviderDefinitions(path string) ([]ProviderDefinition, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, e := range entries {
+			switch strings.ToLower(filepath.Ext(e.Name())) {
+			case ".yaml", ".yml", ".json":
+				if !e.IsDir() {
+					files = append(files, filepath.Join(path, e.Name()))
+				}
+			}
+		}
+		sort.Strings(files)
+	}
+	out := make([]ProviderDefinition, 0, len(files))
+	seen := make(map[string]string, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var d ProviderDefinition
+		if strings.EqualFold(filepath.Ext(f), ".json") {
+			err = json.Unmarshal(data, &d)
+		} else {
+			err = yaml.Unmarshal(data, &d)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		if err := d.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		if prev, dup := seen[d.ID]; dup {
+			return nil, errors.New("duplicate provider id " + d.ID + " in " + prev + " and " + f)
+		}
+		seen[d.ID] = f
+		out = append(out, d)
+	}
+	return out, nil
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProviderDefinitions_Directory(t *testing.T) {
+	dir := t.TempDir()
+	yamlDef := `id: feed_a
+base_url: http://example.com
+format: XML
+items_path: items.item
+fields: {id: id, title: headline}
+pagination: {style: page}
+`
+	jsonDef := `{"id":"feed_b","base_url":"http://example.com","format":"json","items_path":"contents","fields":{"id":"id","title":"title"}}`
+	_ = os.WriteFile(filepath.Join(dir, "a.yaml"), []byte(yamlDef), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "b.json"), []byte(jsonDef), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600)
+
+	defs, err := LoadProviderDefinitions(dir)
+	if err != nil {
+		t.Fatalf("LoadProviderDefinitions: %v", err)
+	}
+	if len(defs) != 2 || defs[0].ID != "feed_a" || defs[1].ID != "feed_b" {
+		t.Fatalf("unexpected definitions: %+v", defs)
+	}
+	a := defs[0]
+	if a.Format != "xml" || a.Pagination.PageParam != "page" || a.Pagination.StartPage != 1 || a.Pagination.PageSize != defaultPageSize {
+		t.Fatalf("defaults not applied: %+v", a)
+	}
+	if defs[1].Pagination.Style != PaginationNone {
+		t.Fatalf("expected default style none, got %q", defs[1].Pagination.Style)
+	}
+}
+
+func TestLoadProviderDefinitions_DuplicateID(t *testing.T) {
+	dir := t.TempDir()
+	def := `{"id":"dup","base_url":"http://example.com","format":"json","items_path":"x","fields":{"id":"id","title":"title"}}`
+	_ = os.WriteFile(filepath.Join(dir, "a.json"), []byte(def), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "b.json"), []byte(def), 0o600)
+	if _, err := LoadProviderDefinitions(dir); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("expected duplicate id error, got %v", err)
+	}
+}
+
+func TestParseProviderDefinition_Invalid(t *testing.T) {
+	_, err := ParseProviderDefinition([]byte(`{"id":"x","format":"csv","pagination":{"style":"cursor"}}`))
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"base_url", "format", "items_path", "fields.id", "pagination.style"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestParseProviderDefinition_ExampleFile(t *testing.T) {
+	data, err := os.ReadFile("../../../docs/provider-definition.example.yaml")
+	if err != nil {
+		t.Fatalf("read example: %v", err)
+	}
+	if _, err := ParseProviderDefinition(data); err != nil {
+		t.Fatalf("example definition is invalid: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package providers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseDurationSeconds converts a provider duration to seconds. It accepts "mm:ss",
+// "hh:mm:ss" and a plain number of seconds; seconds, and minutes after hours, must
+// be below 60.
+func parseDurationSeconds(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	total := 0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		// Every part after the first is bounded by the unit above it
+		if i > 0 && n >= 60 {
+			return 0, false
+		}
+		total = total*60 + n
+	}
+	return total, true
+}
+
+// durationPtr parses s for a ProviderContent field; unparseable values stay nil.
+func durationPtr(s string) *int {
+	if d, ok := parseDurationSeconds(s); ok {
+		return &d
+	}
+	return nil
+}
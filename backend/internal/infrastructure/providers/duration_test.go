@@ -0,0 +1,29 @@
+package providers
+
+import "testing"
+
+func TestParseDurationSeconds(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"15:30", 930, true},
+		{"0:45", 45, true},
+		{"75:00", 4500, true},
+		{"1:02:03", 3723, true},
+		{" 930 ", 930, true},
+		{"", 0, false},
+		{"15:60", 0, false},
+		{"1:60:00", 0, false},
+		{"1:2:3:4", 0, false},
+		{"-1:00", 0, false},
+		{"ab:cd", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := parseDurationSeconds(c.in)
+		if got != c.want || ok != c.ok {
+			t.Errorf("parseDurationSeconds(%q) = %d, %v; want %d, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+}
@@ -2,10 +2,16 @@ package providers
 
 import (
 	"fmt"
+	"sort"
+	"sync"
+
 	domainp "search_engine/internal/domain/providers"
 )
 
+// ProviderFactory is the set of active providers. It is safe for concurrent use so
+// the registry can swap providers while syncs run.
 type ProviderFactory struct {
+	mu       sync.RWMutex
 	registry map[string]domainp.IContentProvider
 }
 
@@ -14,18 +20,37 @@ func NewProviderFactory() *ProviderFactory {
 }
 
 func (f *ProviderFactory) RegisterProvider(p domainp.IContentProvider) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.registry[p.GetProviderID()] = p
 }
 
+// Replace swaps the whole provider set at once.
+func (f *ProviderFactory) Replace(list []domainp.IContentProvider) {
+	registry := make(map[string]domainp.IContentProvider, len(list))
+	for _, p := range list {
+		registry[p.GetProviderID()] = p
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registry = registry
+}
+
+// GetAllProviders returns the providers sorted by ID.
 func (f *ProviderFactory) GetAllProviders() []domainp.IContentProvider {
+	f.mu.RLock()
 	list := make([]domainp.IContentProvider, 0, len(f.registry))
 	for _, p := range f.registry {
 		list = append(list, p)
 	}
+	f.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].GetProviderID() < list[j].GetProviderID() })
 	return list
 }
 
 func (f *ProviderFactory) GetProviderByID(id string) (domainp.IContentProvider, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 	p, ok := f.registry[id]
 	if !ok {
 		return nil, fmt.Errorf("provider not found: %s", id)
@@ -0,0 +1,426 @@
+package providers
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// FeedProvider reads an RSS 2.0 or Atom feed. FetchSince sends the cursor's
+// validators and reports NotModified when the feed answers 304; FetchContents
+// always downloads the whole feed.
+type FeedProvider struct {
+	Client   *http.Client
+	Provider string
+	URL      string
+	// RequestsPerMinute is the limit applied by ProviderService
+	RequestsPerMinute int
+	Retry             RetryPolicy
+	// MaxPayloadBytes caps the feed document (0 = unlimited)
+	MaxPayloadBytes int64
+}
+
+func NewFeedProvider(providerID, feedURL string, timeout time.Duration) *FeedProvider {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   5 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+		},
+	}
+	return &FeedProvider{Client: client, Provider: providerID, URL: feedURL, RequestsPerMinute: 60, Retry: DefaultRetryPolicy(), MaxPayloadBytes: DefaultMaxPayloadBytes}
+}
+
+func (p *FeedProvider) GetProviderID() string { return p.Provider }
+func (p *FeedProvider) GetRateLimit() domainp.RateLimit {
+	return domainp.RateLimit{RequestsPerMinute: p.RequestsPerMinute}
+}
+
+// FeedSource is one configured feed.
+type FeedSource struct {
+	ID  string
+	URL string
+}
+
+// ParseFeedSources parses a comma-separated list of id=url pairs,
+// e.g. "goblog=https://go.dev/blog/feed.atom,hn=https://hnrss.org/frontpage".
+func ParseFeedSources(s string) ([]FeedSource, error) {
+	var out []FeedSource
+	seen := make(map[string]struct{})
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, u, ok := strings.Cut(part, "=")
+		id, u = strings.TrimSpace(id), strings.TrimSpace(u)
+		if !ok || id == "" || u == "" {
+			return nil, fmt.Errorf("feed %q: expected id=url", part)
+		}
+		if _, dup := seen[id]; dup {
+			return nil, fmt.Errorf("duplicate feed id %s", id)
+		}
+		seen[id] = struct{}{}
+		out = append(out, FeedSource{ID: id, URL: u})
+	}
+	return out, nil
+}
+
+type feedDoc struct {
+	XMLName xml.Name
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	// raw is the element as received
+	raw         []byte
+	GUID        string         `xml:"guid"`
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Description string         `xml:"description"`
+	PubDate     string         `xml:"pubDate"`
+	Categories  []string       `xml:"category"`
+	Enclosures  []feedMedia    `xml:"enclosure"`
+	Thumbnails  []feedMedia    `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Media       []feedMedia    `xml:"http://search.yahoo.com/mrss/ content"`
+	Groups      []feedMediaGrp `xml:"http://search.yahoo.com/mrss/ group"`
+	Duration    string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	Comments    string         `xml:"http://purl.org/rss/1.0/modules/slash/ comments"`
+}
+
+type atomEntry struct {
+	// raw is the element as received
+	raw        []byte
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Summary    string         `xml:"summary"`
+	Content    string         `xml:"content"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Thumbnails []feedMedia    `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Media      []feedMedia    `xml:"http://search.yahoo.com/mrss/ content"`
+	Groups     []feedMediaGrp `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+// feedMedia covers <enclosure>, <media:content> and <media:thumbnail>.
+type feedMedia struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Medium string `xml:"medium,attr"`
+	// Duration is the media:content length in seconds
+	Duration string `xml:"duration,attr"`
+}
+
+type feedMediaGrp struct {
+	Thumbnails []feedMedia `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Media      []feedMedia `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+// FetchContents downloads and maps the whole feed.
+func (p *FeedProvider) FetchContents(ctx context.Context) ([]domainp.ProviderContent, error) {
+	res, err := p.fetch(ctx, "", "")
+	return res.Items, err
+}
+
+// FetchSince performs a conditional GET with the validators stored in the cursor.
+// Feeds have no since parameter, so a changed feed returns every item.
+func (p *FeedProvider) FetchSince(ctx context.Context, cur domainp.Cursor) (domainp.FetchResult, error) {
+	return p.fetch(ctx, cur.ETag, cur.LastModified)
+}
+
+// fetch downloads and maps the feed unless the validators show it is unchanged.
+func (p *FeedProvider) fetch(ctx context.Context, etag, lastModified string) (domainp.FetchResult, error) {
+	// Feeds list recent entries only; an entry missing from the feed may still exist
+	domainp.ReportPartial(ctx, "feeds list recent entries only")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, http.NoBody)
+	if err != nil {
+		return domainp.FetchResult{}, err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+	resp, err := doWithRetry(ctx, p.Client, req, p.Retry)
+	if err != nil {
+		return domainp.FetchResult{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotModified {
+		return domainp.FetchResult{NotModified: true, Cursor: domainp.Cursor{ETag: etag, LastModified: lastModified}}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return domainp.FetchResult{}, fmt.Errorf("status %d from %s", resp.StatusCode, p.Provider)
+	}
+	body, err := limitBody(resp, p.MaxPayloadBytes)
+	if err != nil {
+		return domainp.FetchResult{}, err
+	}
+	doc, err := p.decode(body)
+	if err != nil {
+		return domainp.FetchResult{}, err
+	}
+	res := domainp.FetchResult{Cursor: domainp.Cursor{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}}
+	switch doc.XMLName.Local {
+	case "rss":
+		res.Items = p.mapRSS(doc.Channel.Items)
+	case "feed":
+		res.Items = p.mapAtom(doc.Entries)
+	}
+	return res, nil
+}
+
+// decode parses an RSS or Atom document leniently and rejects any other root. Each
+// rss>channel>item and feed>entry keeps its source bytes for quarantine.
+func (p *FeedProvider) decode(r io.Reader) (feedDoc, error) {
+	var doc feedDoc
+	rec := &offsetRecorder{r: r}
+	dec := xml.NewDecoder(rec)
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	// path holds the open elements above the decoder position
+	var path []string
+	for {
+		start := dec.InputOffset()
+		rec.discard(start)
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return feedDoc{}, fmt.Errorf("%s: %w", p.Provider, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			switch {
+			case len(path) == 0 && name != "rss" && name != "feed":
+				return feedDoc{}, fmt.Errorf("%s: unsupported feed root <%s>", p.Provider, name)
+			case len(path) == 0:
+				doc.XMLName = t.Name
+			case path[0] == "rss" && len(path) == 2 && path[1] == "channel" && name == "item":
+				var it rssItem
+				if err := dec.DecodeElement(&it, &t); err != nil {
+					return feedDoc{}, fmt.Errorf("%s: %w", p.Provider, err)
+				}
+				it.raw = rec.slice(start, dec.InputOffset())
+				doc.Channel.Items = append(doc.Channel.Items, it)
+				continue
+			case path[0] == "feed" && len(path) == 1 && name == "entry":
+				var e atomEntry
+				if err := dec.DecodeElement(&e, &t); err != nil {
+					return feedDoc{}, fmt.Errorf("%s: %w", p.Provider, err)
+				}
+				e.raw = rec.slice(start, dec.InputOffset())
+				doc.Entries = append(doc.Entries, e)
+				continue
+			}
+			path = append(path, name)
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+	}
+	if doc.XMLName.Local == "" || len(path) > 0 {
+		return feedDoc{}, fmt.Errorf("%s: %w", p.Provider, io.ErrUnexpectedEOF)
+	}
+	return doc, nil
+}
+
+func (p *FeedProvider) mapRSS(items []rssItem) []domainp.ProviderContent {
+	out := make([]domainp.ProviderContent, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for _, it := range items {
+		id := firstNonEmpty(it.GUID, it.Link)
+		if _, dup := seen[id]; dup || id == "" {
+			continue
+		}
+		seen[id] = struct{}{}
+		media := append(append([]feedMedia{}, it.Enclosures...), it.Media...)
+		thumbs := it.Thumbnails
+		for _, g := range it.Groups {
+			media = append(media, g.Media...)
+			thumbs = append(thumbs, g.Thumbnails...)
+		}
+		pc := domainp.ProviderContent{
+			ProviderID:        p.Provider,
+			ProviderContentID: id,
+			Title:             strings.TrimSpace(html.UnescapeString(it.Title)),
+			Description:       plainText(it.Description),
+			URL:               strings.TrimSpace(it.Link),
+			Tags:              trimAll(it.Categories),
+			DurationSeconds:   mediaDuration(it.Duration, media),
+			Raw:               it.raw,
+		}
+		mapPublishedAt(&pc, "pubDate", it.PubDate, feedDateLayouts...)
+		if n, err := strconv.Atoi(strings.TrimSpace(it.Comments)); err == nil && n >= 0 {
+			pc.Comments = &n
+		}
+		pc.ContentType, pc.ThumbnailURL = classifyMedia(media, thumbs)
+		out = append(out, pc)
+	}
+	return out
+}
+
+func (p *FeedProvider) mapAtom(entries []atomEntry) []domainp.ProviderContent {
+	out := make([]domainp.ProviderContent, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		link := ""
+		var media []feedMedia
+		for _, l := range e.Links {
+			switch l.Rel {
+			case "", "alternate":
+				if link == "" {
+					link = l.Href
+				}
+			case "enclosure":
+				media = append(media, feedMedia{URL: l.Href, Type: l.Type})
+			}
+		}
+		id := firstNonEmpty(e.ID, link)
+		if _, dup := seen[id]; dup || id == "" {
+			continue
+		}
+		seen[id] = struct{}{}
+		media = append(media, e.Media...)
+		thumbs := e.Thumbnails
+		for _, g := range e.Groups {
+			media = append(media, g.Media...)
+			thumbs = append(thumbs, g.Thumbnails...)
+		}
+		tags := make([]string, 0, len(e.Categories))
+		for _, c := range e.Categories {
+			if t := firstNonEmpty(c.Term, c.Label); t != "" {
+				tags = append(tags, t)
+			}
+		}
+		pc := domainp.ProviderContent{
+			ProviderID:        p.Provider,
+			ProviderContentID: id,
+			Title:             strings.TrimSpace(html.UnescapeString(e.Title)),
+			Description:       plainText(firstNonEmpty(e.Summary, e.Content)),
+			URL:               strings.TrimSpace(link),
+			Tags:              tags,
+			DurationSeconds:   mediaDuration("", media),
+			Raw:               e.raw,
+		}
+		if strings.TrimSpace(e.Published) != "" {
+			mapPublishedAt(&pc, "published", e.Published)
+		} else {
+			mapPublishedAt(&pc, "updated", e.Updated)
+		}
+		pc.ContentType, pc.ThumbnailURL = classifyMedia(media, thumbs)
+		out = append(out, pc)
+	}
+	return out
+}
+
+// classifyMedia marks an item as video when it carries a video enclosure and picks
+// the first explicit thumbnail, falling back to the first image enclosure.
+func classifyMedia(media, thumbs []feedMedia) (contentType, thumbnail string) {
+	contentType = "text"
+	for _, m := range media {
+		if m.Medium == "video" || strings.HasPrefix(m.Type, "video/") {
+			contentType = "video"
+		}
+	}
+	for _, t := range thumbs {
+		if t.URL != "" {
+			return contentType, t.URL
+		}
+	}
+	for _, m := range media {
+		if m.URL != "" && (m.Medium == "image" || strings.HasPrefix(m.Type, "image/")) {
+			return contentType, m.URL
+		}
+	}
+	return contentType, ""
+}
+
+// mediaDuration prefers the item's itunes:duration, then the first media duration.
+func mediaDuration(itunes string, media []feedMedia) *int {
+	if d := durationPtr(itunes); d != nil {
+		return d
+	}
+	for _, m := range media {
+		if d := durationPtr(m.Duration); d != nil {
+			return d
+		}
+	}
+	return nil
+}
+
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02",
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// plainText strips markup from HTML descriptions and collapses whitespace.
+func plainText(s string) string {
+	s = html.UnescapeString(htmlTag.ReplaceAllString(s, " "))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func trimAll(vals []string) []string {
+	out := make([]string, 0, len(vals))
+	for _, v := range vals {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
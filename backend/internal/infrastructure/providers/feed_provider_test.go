@@ -0,0 +1,165 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+const rssBody = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Example</title>
+    <item>
+      <title>Go 1.22 &amp; you</title>
+      <link>https://example.com/posts/go</link>
+      <guid>post-1</guid>
+      <description><![CDATA[<p>Range over <b>integers</b>.</p>]]></description>
+      <pubDate>Tue, 06 Feb 2024 10:00:00 +0000</pubDate>
+      <category>go</category>
+      <category> release </category>
+      <enclosure url="https://example.com/cover.jpg" type="image/jpeg" length="1"/>
+    </item>
+    <item>
+      <title>Talk recording</title>
+      <link>https://example.com/talks/1</link>
+      <pubDate>Mon, 5 Feb 2024 09:00:00 GMT</pubDate>
+      <enclosure url="https://example.com/talk.mp4" type="video/mp4" length="1"/>
+      <media:thumbnail url="https://example.com/talk.jpg"/>
+    </item>
+  </channel>
+</rss>`
+
+const atomBody = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example Atom</title>
+  <entry>
+    <id>tag:example.com,2024:1</id>
+    <title>Atom entry</title>
+    <link rel="self" href="https://example.com/self/1"/>
+    <link rel="alternate" href="https://example.com/entries/1"/>
+    <updated>2024-03-01T12:00:00Z</updated>
+    <summary>Short &lt;i&gt;summary&lt;/i&gt;</summary>
+    <category term="atom"/>
+    <category term="feeds"/>
+  </entry>
+</feed>`
+
+func TestFeedProvider_RSS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(rssBody))
+	}))
+	defer srv.Close()
+
+	items, err := NewFeedProvider("news", srv.URL, 5*time.Second).FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	a := items[0]
+	if a.ProviderID != "news" || a.ProviderContentID != "post-1" || a.Title != "Go 1.22 & you" || a.ContentType != "text" {
+		t.Fatalf("unexpected item: %+v", a)
+	}
+	if a.Description != "Range over integers ." || a.URL != "https://example.com/posts/go" || a.ThumbnailURL != "https://example.com/cover.jpg" {
+		t.Fatalf("unexpected description/urls: %+v", a)
+	}
+	if !a.PublishedAt.Equal(time.Date(2024, 2, 6, 10, 0, 0, 0, time.UTC)) || len(a.Tags) != 2 || a.Tags[1] != "release" {
+		t.Fatalf("unexpected date/tags: %+v", a)
+	}
+	b := items[1]
+	if b.ProviderContentID != "https://example.com/talks/1" || b.ContentType != "video" || b.ThumbnailURL != "https://example.com/talk.jpg" || b.PublishedAt.IsZero() {
+		t.Fatalf("unexpected video item: %+v", b)
+	}
+}
+
+func TestFeedProvider_Atom(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(atomBody))
+	}))
+	defer srv.Close()
+
+	items, err := NewFeedProvider("atom", srv.URL, 5*time.Second).FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	e := items[0]
+	if e.ProviderContentID != "tag:example.com,2024:1" || e.URL != "https://example.com/entries/1" || e.Description != "Short summary" {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+	if e.PublishedAt.IsZero() || len(e.Tags) != 2 || e.Tags[0] != "atom" {
+		t.Fatalf("unexpected date/tags: %+v", e)
+	}
+}
+
+func TestFeedProvider_ReportsBadDatesAndKeepsRawItems(t *testing.T) {
+	item := `<item><guid>p1</guid><title>Undated</title><pubDate>sometime last week</pubDate></item>`
+	entry := `<entry><id>e1</id><title>Bad</title><updated>2024-13-45</updated></entry>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/atom" {
+			_, _ = w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><title>A</title>` + entry + `</feed>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<rss version="2.0"><channel><title>R</title>` + item + `</channel></rss>`))
+	}))
+	defer srv.Close()
+
+	for path, want := range map[string]string{"/rss": item, "/atom": entry} {
+		items, err := NewFeedProvider("news", srv.URL+path, 5*time.Second).FetchContents(context.Background())
+		if err != nil {
+			t.Fatalf("%s: FetchContents error: %v", path, err)
+		}
+		if len(items) != 1 || !items[0].PublishedAt.IsZero() || len(items[0].Problems) != 1 {
+			t.Fatalf("%s: expected the bad date reported, got %+v", path, items)
+		}
+		if string(items[0].Raw) != want {
+			t.Fatalf("%s: expected the element as sent, got %s", path, items[0].Raw)
+		}
+	}
+}
+
+func TestParseFeedSources(t *testing.T) {
+	got, err := ParseFeedSources(" blog=https://example.com/feed.xml , news=https://example.com/atom ,")
+	if err != nil {
+		t.Fatalf("ParseFeedSources: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "blog" || got[1].URL != "https://example.com/atom" {
+		t.Fatalf("unexpected sources: %+v", got)
+	}
+	if _, err := ParseFeedSources("missing-url"); err == nil {
+		t.Fatal("expected error for entry without url")
+	}
+	if _, err := ParseFeedSources("a=http://x,a=http://y"); err == nil {
+		t.Fatal("expected error for duplicate id")
+	}
+}
+
+func TestFeedProvider_FetchSinceUsesCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v2"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v2"`)
+		_, _ = w.Write([]byte(atomBody))
+	}))
+	defer srv.Close()
+
+	p := NewFeedProvider("atom", srv.URL, 5*time.Second)
+	res, err := p.FetchSince(context.Background(), domainp.Cursor{ETag: `"v1"`})
+	if err != nil || res.NotModified || len(res.Items) != 1 || res.Cursor.ETag != `"v2"` {
+		t.Fatalf("changed feed: res=%+v err=%v", res, err)
+	}
+	res, err = p.FetchSince(context.Background(), res.Cursor)
+	if err != nil || !res.NotModified || len(res.Items) != 0 || res.Cursor.ETag != `"v2"` {
+		t.Fatalf("unchanged feed: res=%+v err=%v", res, err)
+	}
+}
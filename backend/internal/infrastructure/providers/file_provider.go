@@ -0,0 +1,393 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// FileProvider imports contents from local NDJSON (.ndjson, .jsonl) or CSV (.csv)
+// files. Path is a single file or a directory whose matching files are read in name
+// order; when an ID appears twice the later record wins.
+//
+// Every record mirrors ProviderContent:
+//
+//	provider_content_id  required
+//	title                required
+//	content_type         "video" or "text" (default "text")
+//	description, url, thumbnail_url
+//	views, likes         integers
+//	reading_time, reactions integers
+//	duration             "mm:ss", "hh:mm:ss" or seconds
+//	comments             integer
+//	published_at         RFC3339 or YYYY-MM-DD
+//	tags                 JSON array; in CSV a "|" separated list
+//
+// CSV files start with a header row naming these columns; unknown columns are ignored.
+// Malformed records are skipped and reported by FetchWithRowErrors.
+type FileProvider struct {
+	Provider string
+	Path     string
+	// RequestsPerMinute is the limit applied by ProviderService
+	RequestsPerMinute int
+}
+
+func NewFileProvider(providerID, path string) *FileProvider {
+	return &FileProvider{Provider: providerID, Path: path, RequestsPerMinute: 60}
+}
+
+func (p *FileProvider) GetProviderID() string { return p.Provider }
+func (p *FileProvider) GetRateLimit() domainp.RateLimit {
+	return domainp.RateLimit{RequestsPerMinute: p.RequestsPerMinute}
+}
+
+// fileRow is the on-disk record; numbers are pointers so absent metrics stay nil.
+type fileRow struct {
+	ID           string   `json:"provider_content_id"`
+	Title        string   `json:"title"`
+	ContentType  string   `json:"content_type"`
+	Description  string   `json:"description"`
+	URL          string   `json:"url"`
+	ThumbnailURL string   `json:"thumbnail_url"`
+	Views        *int64   `json:"views"`
+	Likes        *int64   `json:"likes"`
+	ReadingTime  *int     `json:"reading_time"`
+	Reactions    *int     `json:"reactions"`
+	Duration     flexText `json:"duration"`
+	Comments     *int     `json:"comments"`
+	PublishedAt  string   `json:"published_at"`
+	Tags         []string `json:"tags"`
+}
+
+// flexText decodes a JSON string or number, so durations may be "15:30" or 930.
+type flexText string
+
+func (t *flexText) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*t = flexText(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*t = flexText(n.String())
+	return nil
+}
+
+func (p *FileProvider) FetchContents(ctx context.Context) ([]domainp.ProviderContent, error) {
+	res, err := p.FetchWithRowErrors(ctx)
+	return res.Items, err
+}
+
+// FetchWithRowErrors reads every import file; records that do not parse or validate
+// are skipped and returned as row errors.
+func (p *FileProvider) FetchWithRowErrors(ctx context.Context) (domainp.FetchResult, error) {
+	files, err := p.files()
+	if err != nil {
+		return domainp.FetchResult{}, err
+	}
+	var rowErrs []domainp.RowError
+	index := make(map[string]int)
+	var out []domainp.ProviderContent
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return domainp.FetchResult{}, err
+		}
+		err := p.readFile(f, func(line int, row fileRow, rowErr error) {
+			if rowErr == nil {
+				var pc domainp.ProviderContent
+				if pc, rowErr = p.toContent(row); rowErr == nil {
+					if i, dup := index[pc.ProviderContentID]; dup {
+						out[i] = pc
+					} else {
+						index[pc.ProviderContentID] = len(out)
+						out = append(out, pc)
+					}
+					return
+				}
+			}
+			rowErrs = append(rowErrs, domainp.RowError{Source: filepath.Base(f), Line: line, Message: rowErr.Error()})
+		})
+		if err != nil {
+			return domainp.FetchResult{}, fmt.Errorf("%s: %w", f, err)
+		}
+	}
+	return domainp.FetchResult{Items: out, RowErrors: rowErrs}, nil
+}
+
+// files lists the importable files under Path.
+func (p *FileProvider) files() ([]string, error) {
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{p.Path}, nil
+	}
+	entries, err := os.ReadDir(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && fileFormat(e.Name()) != "" {
+			files = append(files, filepath.Join(p.Path, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func fileFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	case ".csv":
+		return "csv"
+	}
+	return ""
+}
+
+func (p *FileProvider) readFile(path string, emit func(line int, row fileRow, err error)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	switch fileFormat(path) {
+	case "ndjson":
+		return readNDJSON(f, emit)
+	case "csv":
+		return readCSV(f, emit)
+	}
+	return fmt.Errorf("unsupported file type %q", filepath.Ext(path))
+}
+
+// maxNDJSONLine is the longest NDJSON row accepted; longer rows are reported and skipped
+const maxNDJSONLine = 1 << 20
+
+func readNDJSON(r io.Reader, emit func(int, fileRow, error)) error {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var buf []byte
+	for line := 1; ; line++ {
+		buf = buf[:0]
+		read, tooLong := 0, false
+		var err error
+		for {
+			var chunk []byte
+			chunk, err = br.ReadSlice('\n')
+			read += len(chunk)
+			if read > maxNDJSONLine {
+				// The rest of the row is read and dropped
+				tooLong, buf = true, buf[:0]
+			} else {
+				buf = append(buf, chunk...)
+			}
+			if !errors.Is(err, bufio.ErrBufferFull) {
+				break
+			}
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if read == 0 {
+			return nil
+		}
+		switch b := bytes.TrimSpace(buf); {
+		case tooLong:
+			emit(line, fileRow{}, fmt.Errorf("row longer than %d bytes", maxNDJSONLine))
+		case len(b) == 0:
+		default:
+			var row fileRow
+			if err := json.Unmarshal(b, &row); err != nil {
+				emit(line, row, fmt.Errorf("invalid json: %w", err))
+			} else {
+				emit(line, row, nil)
+			}
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func readCSV(r io.Reader, emit func(int, fileRow, error)) error {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	if _, ok := cols["provider_content_id"]; !ok {
+		return errors.New("header: provider_content_id column is required")
+	}
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var pe *csv.ParseError
+			if errors.As(err, &pe) {
+				emit(pe.Line, fileRow{}, pe.Err)
+				continue
+			}
+			return err
+		}
+		if len(rec) != len(header) {
+			emit(line, fileRow{}, fmt.Errorf("expected %d fields, got %d", len(header), len(rec)))
+			continue
+		}
+		row, err := csvRow(cols, rec)
+		emit(line, row, err)
+	}
+}
+
+func csvRow(cols map[string]int, rec []string) (fileRow, error) {
+	get := func(name string) string {
+		if i, ok := cols[name]; ok {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	row := fileRow{
+		ID:           get("provider_content_id"),
+		Title:        get("title"),
+		ContentType:  get("content_type"),
+		Description:  get("description"),
+		URL:          get("url"),
+		ThumbnailURL: get("thumbnail_url"),
+		Duration:     flexText(get("duration")),
+		PublishedAt:  get("published_at"),
+	}
+	if t := get("tags"); t != "" {
+		row.Tags = strings.Split(t, "|")
+	}
+	for _, f := range []struct {
+		name string
+		dst  func(int64)
+	}{
+		{"views", func(v int64) { row.Views = &v }},
+		{"likes", func(v int64) { row.Likes = &v }},
+		{"reading_time", func(v int64) { n := int(v); row.ReadingTime = &n }},
+		{"reactions", func(v int64) { n := int(v); row.Reactions = &n }},
+		{"comments", func(v int64) { n := int(v); row.Comments = &n }},
+	} {
+		s := get(f.name)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return row, fmt.Errorf("%s: not an integer: %q", f.name, s)
+		}
+		f.dst(v)
+	}
+	return row, nil
+}
+
+func (p *FileProvider) toContent(row fileRow) (domainp.ProviderContent, error) {
+	pc := domainp.ProviderContent{
+		ProviderID:        p.Provider,
+		ProviderContentID: strings.TrimSpace(row.ID),
+		Title:             strings.TrimSpace(row.Title),
+		ContentType:       strings.ToLower(strings.TrimSpace(row.ContentType)),
+		Description:       row.Description,
+		URL:               row.URL,
+		ThumbnailURL:      row.ThumbnailURL,
+		Views:             row.Views,
+		Likes:             row.Likes,
+		ReadingTime:       row.ReadingTime,
+		Reactions:         row.Reactions,
+		Comments:          row.Comments,
+		Tags:              trimAll(row.Tags),
+	}
+	if pc.ProviderContentID == "" {
+		return pc, errors.New("provider_content_id is required")
+	}
+	if pc.Title == "" {
+		return pc, errors.New("title is required")
+	}
+	switch pc.ContentType {
+	case "":
+		pc.ContentType = "text"
+	case "video", "text":
+	default:
+		return pc, fmt.Errorf("content_type must be video or text, got %q", row.ContentType)
+	}
+	if s := strings.TrimSpace(string(row.Duration)); s != "" {
+		d, ok := parseDurationSeconds(s)
+		if !ok {
+			return pc, fmt.Errorf("duration: invalid value %q", s)
+		}
+		pc.DurationSeconds = &d
+	}
+	if s := strings.TrimSpace(row.PublishedAt); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", s); err != nil {
+				return pc, fmt.Errorf("published_at: invalid date %q", s)
+			}
+		}
+		pc.PublishedAt = t
+	}
+	return pc, nil
+}
+
+// Watch polls Path every interval and calls onChange when files are added,
+// removed or modified. It returns when ctx is done.
+func (p *FileProvider) Watch(ctx context.Context, interval time.Duration, onChange func()) {
+	last := p.snapshot()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if cur := p.snapshot(); cur != last {
+				last = cur
+				onChange()
+			}
+		}
+	}
+}
+
+// snapshot summarises names, sizes and modification times of the import files.
+func (p *FileProvider) snapshot() string {
+	files, err := p.files()
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	var b strings.Builder
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			fmt.Fprintf(&b, "%s|%d|%d\n", f, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileProvider_NDJSONAndCSV(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.ndjson", `{"provider_content_id":"v1","title":"Docker","content_type":"video","views":100,"likes":5,"published_at":"2024-03-15T10:00:00Z","tags":["devops"]}
+
+{"provider_content_id":"a1","title":"Old title","published_at":"2024-03-14"}
+{not json}
+{"provider_content_id":"x1","title":"Bad type","content_type":"podcast"}
+`)
+	writeFile(t, dir, "b.csv", `provider_content_id,title,content_type,reading_time,reactions,published_at,tags,extra
+a1,New title,text,8,40,2024-03-14,go|testing,ignored
+a2,,text,,,,,
+a3,Numbers,text,eight,,,,
+a4,Short row
+`)
+	writeFile(t, dir, "notes.txt", "ignored")
+
+	p := NewFileProvider("import", dir)
+	res, err := p.FetchWithRowErrors(context.Background())
+	if err != nil {
+		t.Fatalf("FetchWithRowErrors error: %v", err)
+	}
+	items := res.Items
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d: %+v", len(items), items)
+	}
+	v := items[0]
+	if v.ProviderID != "import" || v.ContentType != "video" || v.Views == nil || *v.Views != 100 || len(v.Tags) != 1 {
+		t.Fatalf("unexpected ndjson item: %+v", v)
+	}
+	a := items[1]
+	if a.Title != "New title" || a.ReadingTime == nil || *a.ReadingTime != 8 || len(a.Tags) != 2 || !a.PublishedAt.Equal(time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("later csv record should override ndjson one: %+v", a)
+	}
+
+	errs := res.RowErrors
+	want := []string{"a.ndjson:4: invalid json", "a.ndjson:5: content_type", "b.csv:3: title is required", "b.csv:4: reading_time", "b.csv:5: expected 8 fields"}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d row errors, got %v", len(want), errs)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(errs[i].String(), w) {
+			t.Errorf("row error %d = %q, want prefix %q", i, errs[i].String(), w)
+		}
+	}
+}
+
+func TestFileProvider_ReportsOversizedNDJSONRows(t *testing.T) {
+	dir := t.TempDir()
+	huge := `{"provider_content_id":"big","title":"` + strings.Repeat("x", maxNDJSONLine) + `"}`
+	writeFile(t, dir, "a.ndjson", `{"provider_content_id":"a1","title":"Before"}
+`+huge+`
+{"provider_content_id":"a2","title":"After"}`)
+
+	res, err := NewFileProvider("import", dir).FetchWithRowErrors(context.Background())
+	if err != nil {
+		t.Fatalf("an oversized row must not fail the file: %v", err)
+	}
+	if len(res.Items) != 2 || res.Items[1].ProviderContentID != "a2" {
+		t.Fatalf("expected the rows around the oversized one, got %+v", res.Items)
+	}
+	if len(res.RowErrors) != 1 || !strings.HasPrefix(res.RowErrors[0].String(), "a.ndjson:2: row longer than") {
+		t.Fatalf("expected the oversized row reported, got %v", res.RowErrors)
+	}
+}
+
+func TestFileProvider_MissingPath(t *testing.T) {
+	if _, err := NewFileProvider("import", filepath.Join(t.TempDir(), "nope.csv")).FetchContents(context.Background()); err == nil {
+		t.Fatal("expected error for missing path")
+	}
+}
+
+func TestFileProvider_WatchDetectsChanges(t *testing.T) {
+	dir := t.TempDir()
+	p := NewFileProvider("import", dir)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	go p.Watch(ctx, 10*time.Millisecond, func() { changed <- struct{}{} })
+	time.Sleep(30 * time.Millisecond)
+	writeFile(t, dir, "new.ndjson", `{"provider_content_id":"n1","title":"New"}`)
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch did not report the new file")
+	}
+}
+
+func TestFileProvider_ExampleFiles(t *testing.T) {
+	for _, name := range []string{"import.example.ndjson", "import.example.csv"} {
+		p := NewFileProvider("import", filepath.Join("../../../docs", name))
+		res, err := p.FetchWithRowErrors(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(res.Items) != 2 || len(res.RowErrors) != 0 {
+			t.Fatalf("%s: expected 2 clean items, got %d items and %v", name, len(res.Items), res.RowErrors)
+		}
+	}
+}
+
+func TestFileProvider_DurationAndComments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.ndjson", `{"provider_content_id":"v1","title":"Clock","content_type":"video","duration":"1:02:03","comments":7}
+{"provider_content_id":"v2","title":"Seconds","content_type":"video","duration":930}
+{"provider_content_id":"v3","title":"Broken","content_type":"video","duration":"15:75"}
+`)
+	writeFile(t, dir, "b.csv", `provider_content_id,title,content_type,duration,comments
+v4,Csv,video,15:30,3
+`)
+	p := NewFileProvider("import", dir)
+	res, err := p.FetchWithRowErrors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := res.Items
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %+v", items)
+	}
+	for i, want := range []int{3723, 930, 930} {
+		if d := items[i].DurationSeconds; d == nil || *d != want {
+			t.Errorf("item %d duration = %v, want %d", i, d, want)
+		}
+	}
+	if items[0].Comments == nil || *items[0].Comments != 7 || items[2].Comments == nil || *items[2].Comments != 3 {
+		t.Fatalf("comments not mapped: %+v", items)
+	}
+	if errs := res.RowErrors; len(errs) != 1 || !strings.HasPrefix(errs[0].String(), "a.ndjson:3: duration") {
+		t.Fatalf("expected one duration row error, got %v", errs)
+	}
+}
@@ -0,0 +1,535 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// GenericProvider fetches any JSON or XML feed described by a ProviderDefinition.
+type GenericProvider struct {
+	Client *http.Client
+	Def    ProviderDefinition
+	// Retry applies to every page request
+	Retry RetryPolicy
+	// MaxPayloadBytes caps each page response (0 = unlimited); the document is
+	// decoded as a whole, so this also bounds its memory
+	MaxPayloadBytes int64
+}
+
+// NewGenericProvider builds a provider from a validated definition.
+func NewGenericProvider(def ProviderDefinition, timeout time.Duration) *GenericProvider {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   5 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+		},
+	}
+	return &GenericProvider{Client: client, Def: def, Retry: DefaultRetryPolicy(), MaxPayloadBytes: DefaultMaxPayloadBytes}
+}
+
+func (p *GenericProvider) GetProviderID() string { return p.Def.ID }
+func (p *GenericProvider) GetRateLimit() domainp.RateLimit {
+	return domainp.RateLimit{RequestsPerMinute: p.Def.RateLimitPerMinute}
+}
+
+// FetchContents walks every page as described by the definition.
+func (p *GenericProvider) FetchContents(ctx context.Context) ([]domainp.ProviderContent, error) {
+	res, err := p.FetchSince(ctx, domainp.Cursor{})
+	return res.Items, err
+}
+
+// FetchSince walks pages until an empty page, a page with no unseen items, the
+// reported total, or max_pages is reached. The first request carries since_param when
+// the definition declares one. Conditional request validators are only used for
+// unpaginated sources: an unchanged first page says nothing about the later ones.
+func (p *GenericProvider) FetchSince(ctx context.Context, cur domainp.Cursor) (domainp.FetchResult, error) {
+	pg := p.Def.Pagination
+	conditional := pg.Style == PaginationNone
+	if !conditional {
+		cur.ETag, cur.LastModified = "", ""
+	}
+	var configured time.Duration
+	if pg.Delay != "" {
+		configured, _ = time.ParseDuration(pg.Delay)
+	}
+	delay := pageDelay(configured, p.GetRateLimit())
+	seen := make(map[string]struct{})
+	var res domainp.FetchResult
+	fetched := 0
+	for n := 0; pg.MaxPages <= 0 || n < pg.MaxPages; n++ {
+		if n > 0 {
+			if err := waitPage(ctx, delay); err != nil {
+				return domainp.FetchResult{}, err
+			}
+		}
+		page, err := p.fetchPage(ctx, n, fetched, cur)
+		if err != nil {
+			return domainp.FetchResult{}, fmt.Errorf("%s page %d: %w", p.Def.ID, n+1, err)
+		}
+		if n == 0 {
+			if page.notModified && conditional {
+				return domainp.FetchResult{NotModified: true, Cursor: cur}, nil
+			}
+			if conditional {
+				res.Cursor = domainp.Cursor{ETag: page.etag, LastModified: page.lastModified}
+			}
+			if p.Def.SinceParam != "" && cur.Since != nil {
+				domainp.ReportPartial(ctx, "only items published since the cursor were requested")
+			}
+		}
+		fresh := 0
+		for i, it := range page.items {
+			pc := p.mapItem(it, page.raw(i))
+			if pc.ProviderContentID != "" {
+				if _, dup := seen[pc.ProviderContentID]; dup {
+					continue
+				}
+				seen[pc.ProviderContentID] = struct{}{}
+			}
+			fresh++
+			res.Items = append(res.Items, pc)
+		}
+		fetched += len(page.items)
+		if pg.Style == PaginationNone || len(page.items) == 0 || fresh == 0 {
+			return res, nil
+		}
+		if page.total > 0 && fetched >= page.total {
+			return res, nil
+		}
+	}
+	reportMaxPages(ctx, pg.MaxPages)
+	return res, nil
+}
+
+// Preview fetches only the first page and returns the mapped items with the
+// reported total (0 when the feed does not report one).
+func (p *GenericProvider) Preview(ctx context.Context) ([]domainp.ProviderContent, int, error) {
+	page, err := p.fetchPage(ctx, 0, 0, domainp.Cursor{})
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]domainp.ProviderContent, 0, len(page.items))
+	for i, it := range page.items {
+		out = append(out, p.mapItem(it, page.raw(i)))
+	}
+	return out, page.total, nil
+}
+
+func (p *GenericProvider) pageURL(n, fetched int, cur domainp.Cursor) (string, error) {
+	u, err := url.Parse(strings.TrimRight(p.Def.BaseURL, "/") + p.Def.Path)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for k, v := range p.Def.Query {
+		q.Set(k, v)
+	}
+	if p.Def.SinceParam != "" && cur.Since != nil {
+		layout := p.Def.SinceFormat
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		q.Set(p.Def.SinceParam, cur.Since.UTC().Format(layout))
+	}
+	pg := p.Def.Pagination
+	switch pg.Style {
+	case PaginationOffset:
+		q.Set(pg.LimitParam, strconv.Itoa(pg.PageSize))
+		q.Set(pg.OffsetParam, strconv.Itoa(fetched))
+	case PaginationPage:
+		q.Set(pg.PageParam, strconv.Itoa(pg.StartPage+n))
+		q.Set(pg.SizeParam, strconv.Itoa(pg.PageSize))
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+type genericPage struct {
+	items        []any
+	raws         [][]byte
+	total        int
+	notModified  bool
+	etag         string
+	lastModified string
+}
+
+// raw returns the source bytes of item i, or nil when they could not be matched up
+// with the decoded items.
+func (g genericPage) raw(i int) []byte {
+	if len(g.raws) != len(g.items) {
+		return nil
+	}
+	return g.raws[i]
+}
+
+func (p *GenericProvider) fetchPage(ctx context.Context, n, fetched int, cur domainp.Cursor) (genericPage, error) {
+	u, err := p.pageURL(n, fetched, cur)
+	if err != nil {
+		return genericPage{}, err
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if n == 0 {
+		if cur.ETag != "" {
+			req.Header.Set("If-None-Match", cur.ETag)
+		}
+		if cur.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cur.LastModified)
+		}
+	}
+	resp, err := doWithRetry(ctx, p.Client, req, p.Retry)
+	if err != nil {
+		return genericPage{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotModified {
+		return genericPage{notModified: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return genericPage{}, fmt.Errorf("status %d from %s", resp.StatusCode, p.Def.ID)
+	}
+	body, err := limitBody(resp, p.MaxPayloadBytes)
+	if err != nil {
+		return genericPage{}, err
+	}
+	doc, raws, err := p.decodeItems(body)
+	if err != nil {
+		return genericPage{}, err
+	}
+	page := genericPage{
+		items:        asList(lookupPath(doc, p.Def.ItemsPath)),
+		raws:         raws,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	if p.Def.Pagination.TotalPath != "" {
+		if t, ok := asInt64(lookupPath(doc, p.Def.Pagination.TotalPath)); ok {
+			page.total = int(t)
+		}
+	}
+	return page, nil
+}
+
+// decode parses a response body into a tree of maps, lists and scalars.
+func (p *GenericProvider) decode(r io.Reader) (any, error) {
+	if p.Def.Format == "xml" {
+		return decodeXMLTree(r)
+	}
+	var doc any
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	err := dec.Decode(&doc)
+	return doc, err
+}
+
+// decodeItems is decode that also returns the source bytes of every element under
+// items_path, in document order.
+func (p *GenericProvider) decodeItems(r io.Reader) (any, [][]byte, error) {
+	if p.Def.Format == "xml" {
+		return decodeXMLTreeRaw(r, splitPath(p.Def.ItemsPath))
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := p.decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, rawItemsJSON(body, p.Def.ItemsPath), nil
+}
+
+// mapItem maps a decoded item; raw is the item as received, kept for quarantine, and
+// falls back to the item re-encoded when missing.
+func (p *GenericProvider) mapItem(item any, raw []byte) domainp.ProviderContent {
+	if raw == nil {
+		raw = rawJSON(item)
+	}
+	f := p.Def.Fields
+	pc := domainp.ProviderContent{
+		ProviderID:        p.Def.ID,
+		ProviderContentID: asString(p.field(item, f.ID)),
+		Title:             asString(p.field(item, f.Title)),
+		Description:       asString(p.field(item, f.Description)),
+		URL:               asString(p.field(item, f.URL)),
+		ThumbnailURL:      asString(p.field(item, f.ThumbnailURL)),
+		Tags:              asStrings(p.field(item, f.Tags)),
+		Language:          asString(p.field(item, f.Language)),
+		Raw:               raw,
+	}
+	pc.ContentType = p.contentType(asString(p.field(item, f.Type)))
+	mapPublishedAt(&pc, f.PublishedAt, asString(p.field(item, f.PublishedAt)), p.Def.DateFormats...)
+
+	expand := func(tpl string) string {
+		return strings.NewReplacer("{id}", pc.ProviderContentID, "{type}", pc.ContentType).Replace(tpl)
+	}
+	if pc.URL == "" && f.URLTemplate != "" {
+		pc.URL = expand(f.URLTemplate)
+	}
+	if pc.ThumbnailURL == "" && f.ThumbnailTemplate != "" {
+		pc.ThumbnailURL = expand(f.ThumbnailTemplate)
+	}
+
+	if v, ok := asInt64(p.field(item, f.Views)); ok {
+		pc.Views = &v
+	}
+	if v, ok := asInt64(p.field(item, f.Likes)); ok {
+		pc.Likes = &v
+	}
+	if v, ok := asInt64(p.field(item, f.ReadingTime)); ok {
+		rt := int(v)
+		pc.ReadingTime = &rt
+	}
+	if v, ok := asInt64(p.field(item, f.Reactions)); ok {
+		r := int(v)
+		pc.Reactions = &r
+	}
+	mapDuration(&pc, f.Duration, asString(p.field(item, f.Duration)))
+	if v, ok := asInt64(p.field(item, f.Comments)); ok {
+		c := int(v)
+		pc.Comments = &c
+	}
+	return pc
+}
+
+func (p *GenericProvider) field(item any, path string) any {
+	if path == "" {
+		return nil
+	}
+	return lookupPath(item, path)
+}
+
+// contentType maps a type value through TypeValues. Items without a type are text;
+// unknown values are returned as they are so validation quarantines the item.
+func (p *GenericProvider) contentType(raw string) string {
+	if raw == "" {
+		return "text"
+	}
+	if mapped, ok := p.Def.TypeValues[raw]; ok {
+		raw = mapped
+	}
+	switch strings.ToLower(raw) {
+	case "video":
+		return "video"
+	case "text":
+		return "text"
+	}
+	return raw
+}
+
+// splitPath splits a dot-separated path into its keys; the empty path has none.
+func splitPath(path string) []string {
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, ".")
+}
+
+// rawItemsJSON returns the source bytes of the elements at path in a JSON document,
+// following lookupPath and asList. Nil means they could not be cut out.
+func rawItemsJSON(doc []byte, path string) [][]byte {
+	cur := json.RawMessage(doc)
+	for _, key := range splitPath(path) {
+		var m map[string]json.RawMessage
+		if json.Unmarshal(cur, &m) != nil {
+			return nil
+		}
+		cur = m[key]
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(cur, &list) != nil {
+		return [][]byte{cur}
+	}
+	out := make([][]byte, len(list))
+	for i, el := range list {
+		out[i] = el
+	}
+	return out
+}
+
+// lookupPath walks a dot-separated path through nested maps.
+func lookupPath(v any, path string) any {
+	if path == "" {
+		return v
+	}
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// asList normalises a value into a list; a single XML child decodes as a map, not a slice.
+func asList(v any) []any {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return t
+	case string:
+		if strings.TrimSpace(t) == "" {
+			return nil
+		}
+	}
+	return []any{v}
+}
+
+func asString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(t)
+	case json.Number:
+		return t.String()
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	case map[string]any:
+		if text, ok := t["#text"]; ok {
+			return asString(text)
+		}
+		return ""
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func asStrings(v any) []string {
+	list := asList(v)
+	if len(list) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, it := range list {
+		if s := asString(it); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func asInt64(v any) (int64, bool) {
+	s := asString(v)
+	if s == "" {
+		return 0, false
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return int64(f), true
+	}
+	return 0, false
+}
+
+// decodeXMLTree decodes an XML document into nested maps rooted at the document
+// element. Repeated child elements become lists, attributes are keyed "@name",
+// and text alongside children or attributes is kept under "#text".
+func decodeXMLTree(r io.Reader) (any, error) {
+	doc, _, err := decodeXMLTreeRaw(r, nil)
+	return doc, err
+}
+
+// decodeXMLTreeRaw is decodeXMLTree that also returns the source bytes of every
+// element at itemPath below the root, in document order; a nil itemPath keeps none.
+func decodeXMLTreeRaw(r io.Reader, itemPath []string) (any, [][]byte, error) {
+	type frame struct {
+		name  string
+		node  map[string]any
+		text  strings.Builder
+		start int64
+	}
+	rec := &offsetRecorder{r: r}
+	dec := xml.NewDecoder(rec)
+	var stack []*frame
+	var raws [][]byte
+	// inItem is set while an element whose source is kept is open
+	inItem := false
+	isItem := func() bool {
+		if itemPath == nil || len(stack) != len(itemPath)+1 {
+			return false
+		}
+		for i, key := range itemPath {
+			if stack[i+1].name != key {
+				return false
+			}
+		}
+		return true
+	}
+	for {
+		start := dec.InputOffset()
+		if !inItem {
+			rec.discard(start)
+		}
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("empty xml document")
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			f := &frame{name: t.Name.Local, node: map[string]any{}, start: start}
+			for _, a := range t.Attr {
+				f.node["@"+a.Name.Local] = a.Value
+			}
+			stack = append(stack, f)
+			if isItem() {
+				inItem = true
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			if isItem() {
+				raws = append(raws, rec.slice(stack[len(stack)-1].start, dec.InputOffset()))
+				inItem = false
+			}
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			text := strings.TrimSpace(f.text.String())
+			var val any = f.node
+			if len(f.node) == 0 {
+				val = text
+			} else if text != "" {
+				f.node["#text"] = text
+			}
+			if len(stack) == 0 {
+				return val, raws, nil
+			}
+			parent := stack[len(stack)-1].node
+			switch existing := parent[f.name].(type) {
+			case nil:
+				parent[f.name] = val
+			case []any:
+				parent[f.name] = append(existing, val)
+			default:
+				parent[f.name] = []any{existing, val}
+			}
+		}
+	}
+}
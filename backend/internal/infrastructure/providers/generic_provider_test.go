@@ -0,0 +1,297 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+func TestGenericProvider_JSONOffsetPagination(t *testing.T) {
+	const total = 5
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/contents" || r.URL.Query().Get("lang") != "en" {
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":{"list":[`)
+		for i := offset; i < offset+limit && i < total; i++ {
+			if i > offset {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"uid":%d,"name":"Item %d","kind":"clip","stats":{"plays":"%d","likes":3},"date":"2024-03-15T10:00:00Z","labels":["go"]}`, i, i, i*10)
+		}
+		fmt.Fprintf(w, `]},"meta":{"total":%d}}`, total)
+	}))
+	defer srv.Close()
+
+	def := ProviderDefinition{
+		ID: "p3", BaseURL: srv.URL, Path: "/contents", Format: "json",
+		Query:     map[string]string{"lang": "en"},
+		ItemsPath: "data.list",
+		Fields: FieldMapping{
+			ID: "uid", Title: "name", Type: "kind", Views: "stats.plays", Likes: "stats.likes",
+			PublishedAt: "date", Tags: "labels", URLTemplate: "https://example.com/{type}/{id}",
+		},
+		TypeValues: map[string]string{"clip": "video"},
+		Pagination: PaginationSpec{Style: PaginationOffset, PageSize: 2, TotalPath: "meta.total"},
+	}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	items, err := NewGenericProvider(def, 5*time.Second).FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if len(items) != total || calls != 3 {
+		t.Fatalf("expected %d items in 3 calls, got %d in %d", total, len(items), calls)
+	}
+	it := items[4]
+	if it.ProviderID != "p3" || it.ProviderContentID != "4" || it.Title != "Item 4" || it.ContentType != "video" {
+		t.Fatalf("unexpected mapping: %+v", it)
+	}
+	if it.URL != "https://example.com/video/4" || it.Views == nil || *it.Views != 40 || it.Likes == nil || *it.Likes != 3 {
+		t.Fatalf("unexpected url/metrics: %+v", it)
+	}
+	if it.PublishedAt.IsZero() || len(it.Tags) != 1 || it.Tags[0] != "go" {
+		t.Fatalf("unexpected date/tags: %+v", it)
+	}
+}
+
+func TestGenericProvider_XMLPagePagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Content-Type", "application/xml")
+		switch page {
+		case 1:
+			fmt.Fprint(w, `<feed><items>
+<item id="a1"><headline>First</headline><type>article</type><stats><reading_time>8</reading_time></stats>
+<publication_date>2024-03-14</publication_date><categories><category>go</category><category>testing</category></categories></item>
+<item id="a2"><headline>Second</headline><type>article</type><publication_date>bad</publication_date></item>
+</items></feed>`)
+		case 2:
+			fmt.Fprint(w, `<feed><items><item id="v1"><headline>Third</headline><type>video</type><categories><category>solo</category></categories></item></items></feed>`)
+		default:
+			fmt.Fprint(w, `<feed><items></items></feed>`)
+		}
+	}))
+	defer srv.Close()
+
+	def := ProviderDefinition{
+		ID: "p4", BaseURL: srv.URL, Format: "xml", ItemsPath: "items.item",
+		Fields: FieldMapping{
+			ID: "@id", Title: "headline", Type: "type", ReadingTime: "stats.reading_time",
+			PublishedAt: "publication_date", Tags: "categories.category",
+		},
+		TypeValues: map[string]string{"article": "text"},
+		Pagination: PaginationSpec{Style: PaginationPage, PageSize: 2},
+	}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	items, err := NewGenericProvider(def, 5*time.Second).FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	if items[0].ContentType != "text" || items[0].ReadingTime == nil || *items[0].ReadingTime != 8 || len(items[0].Tags) != 2 {
+		t.Fatalf("unexpected first item: %+v", items[0])
+	}
+	if !items[1].PublishedAt.IsZero() || len(items[1].Problems) != 1 {
+		t.Fatalf("unparseable date should stay zero and be reported, got %v %v", items[1].PublishedAt, items[1].Problems)
+	}
+	if items[2].ContentType != "video" || len(items[2].Tags) != 1 || items[2].Tags[0] != "solo" {
+		t.Fatalf("unexpected single-child mapping: %+v", items[2])
+	}
+}
+
+func TestGenericProvider_KeepsItemSourceBytes(t *testing.T) {
+	jsonItem := `{ "id": "j1", "title": "As sent", "n": 1.50 }`
+	xmlItem := `<item id="x1"><title>As &amp; sent</title><n>1.50</n></item>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/xml" {
+			fmt.Fprint(w, `<rss><channel><title>Feed</title>`+xmlItem+`</channel></rss>`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"items":[`+jsonItem+`]}}`)
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		def  ProviderDefinition
+		want string
+	}{
+		{ProviderDefinition{ID: "j", BaseURL: srv.URL, Path: "/json", ItemsPath: "data.items", Fields: FieldMapping{ID: "id", Title: "title"}}, jsonItem},
+		{ProviderDefinition{ID: "x", BaseURL: srv.URL, Path: "/xml", Format: "xml", ItemsPath: "channel.item", Fields: FieldMapping{ID: "@id", Title: "title"}}, xmlItem},
+	} {
+		items, err := NewGenericProvider(tc.def, 5*time.Second).FetchContents(context.Background())
+		if err != nil {
+			t.Fatalf("%s: FetchContents error: %v", tc.def.ID, err)
+		}
+		if len(items) != 1 || string(items[0].Raw) != tc.want {
+			t.Fatalf("%s: expected the item as sent, got %+v", tc.def.ID, items)
+		}
+	}
+}
+
+func TestGenericProvider_PreviewFetchesOnePage(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"items":[{"id":"1","title":"a"},{"id":"2","title":"b"}],"total":10}`)
+	}))
+	defer srv.Close()
+
+	def := ProviderDefinition{
+		ID: "p5", BaseURL: srv.URL, Format: "json", ItemsPath: "items",
+		Fields:     FieldMapping{ID: "id", Title: "title"},
+		Pagination: PaginationSpec{Style: PaginationOffset, TotalPath: "total"},
+	}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	items, total, err := NewGenericProvider(def, 5*time.Second).Preview(context.Background())
+	if err != nil {
+		t.Fatalf("Preview error: %v", err)
+	}
+	if calls != 1 || len(items) != 2 || total != 10 {
+		t.Fatalf("expected one call with 2 items and total 10, got calls=%d items=%d total=%d", calls, len(items), total)
+	}
+}
+
+func TestNewPreviewProvider_RefusesInternalTargets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[{"id":"1","title":"a"}]}`)
+	}))
+	defer srv.Close()
+	def := ProviderDefinition{
+		ID: "p6", BaseURL: srv.URL, Format: "json", ItemsPath: "items",
+		Fields: FieldMapping{ID: "id", Title: "title"},
+	}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	p, err := NewPreviewProvider(def, 5*time.Second, false)
+	if err != nil {
+		t.Fatalf("NewPreviewProvider: %v", err)
+	}
+	p.Retry = RetryPolicy{MaxAttempts: 1}
+	if _, _, err := p.Preview(context.Background()); !errors.Is(err, ErrNonPublicAddress) {
+		t.Fatalf("expected loopback target refused, got %v", err)
+	}
+	p, _ = NewPreviewProvider(def, 5*time.Second, true)
+	if items, _, err := p.Preview(context.Background()); err != nil || len(items) != 1 {
+		t.Fatalf("expected private targets allowed when enabled, got %d items (%v)", len(items), err)
+	}
+
+	for _, raw := range []string{"file:///etc/passwd", "gopher://example.com/"} {
+		bad := def
+		bad.BaseURL = raw
+		if _, err := NewPreviewProvider(bad, time.Second, false); err == nil {
+			t.Fatalf("expected %s refused", raw)
+		}
+	}
+	for _, tc := range []TransportConfig{
+		{Proxy: "http://proxy.internal:3128"},
+		{Auth: AuthConfig{Type: AuthAPIKey, QueryParam: "k", Secret: SecretRef{Env: "DATABASE_URL"}}},
+	} {
+		withTransport := def
+		withTransport.Transport = &tc
+		if _, err := NewPreviewProvider(withTransport, time.Second, true); err == nil {
+			t.Fatalf("expected a preview with transport %+v refused", tc)
+		}
+	}
+
+	for addr, public := range map[string]bool{
+		"8.8.8.8": true, "2606:4700::1111": true, "127.0.0.1": false, "10.1.2.3": false,
+		"169.254.169.254": false, "100.64.0.1": false, "::1": false, "fd00::1": false, "::ffff:192.168.1.1": false,
+	} {
+		if got := isPublicAddr(netip.MustParseAddr(addr)); got != public {
+			t.Fatalf("isPublicAddr(%s) = %v, want %v", addr, got, public)
+		}
+	}
+}
+
+func TestGenericProvider_FetchSince(t *testing.T) {
+	var gotSince string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		gotSince = r.URL.Query().Get("updated_after")
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"items":[{"id":"1","title":"changed"}]}`)
+	}))
+	defer srv.Close()
+
+	def := ProviderDefinition{
+		ID: "p6", BaseURL: srv.URL, Format: "json", ItemsPath: "items",
+		Fields:     FieldMapping{ID: "id", Title: "title"},
+		SinceParam: "updated_after", SinceFormat: "2006-01-02",
+	}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	p := NewGenericProvider(def, 5*time.Second)
+	since := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	res, err := p.FetchSince(context.Background(), domainp.Cursor{Since: &since})
+	if err != nil || res.NotModified || len(res.Items) != 1 || res.Cursor.ETag != `"v1"` {
+		t.Fatalf("first fetch: res=%+v err=%v", res, err)
+	}
+	if gotSince != "2024-03-15" {
+		t.Fatalf("expected since param 2024-03-15, got %q", gotSince)
+	}
+	res, err = p.FetchSince(context.Background(), res.Cursor)
+	if err != nil || !res.NotModified {
+		t.Fatalf("expected not modified, got res=%+v err=%v", res, err)
+	}
+}
+
+func TestGenericProvider_PaginatedFeedIgnoresValidators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Page 1 is unchanged but page 2 gained an item
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"page-etag"`)
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{"items":[{"id":"1","title":"one"},{"id":"2","title":"two"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"items":[{"id":"3","title":"new"}]}`)
+	}))
+	defer srv.Close()
+
+	def := ProviderDefinition{
+		ID: "p7", BaseURL: srv.URL, Format: "json", ItemsPath: "items",
+		Fields:     FieldMapping{ID: "id", Title: "title"},
+		Pagination: PaginationSpec{Style: PaginationPage, PageSize: 2, MaxPages: 2},
+	}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	p := NewGenericProvider(def, 5*time.Second)
+	res, err := p.FetchSince(context.Background(), domainp.Cursor{ETag: `"page-etag"`, LastModified: "Tue, 06 Feb 2024 10:00:00 GMT"})
+	if err != nil || res.NotModified || len(res.Items) != 3 {
+		t.Fatalf("expected every page fetched, got res=%+v err=%v", res, err)
+	}
+	if res.Cursor.ETag != "" || res.Cursor.LastModified != "" {
+		t.Fatalf("paginated feeds must not store page validators, got %+v", res.Cursor)
+	}
+}
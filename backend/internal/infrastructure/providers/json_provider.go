@@ -16,8 +16,22 @@ type JSONProvider struct {
 	Client   *http.Client
 	BaseURL  string
 	Provider string
-	Limit    int
-	Offset   int
+	// Limit is the page size and Offset the starting offset of the crawl
+	Limit  int
+	Offset int
+	// MaxPages caps the number of pages per fetch (0 = unlimited)
+	MaxPages int
+	// PageDelay is the minimum pause between page requests; the rate limit may raise it
+	PageDelay time.Duration
+	// Retry applies to every page request
+	Retry RetryPolicy
+	// RequestsPerMinute is the limit applied by ProviderService
+	RequestsPerMinute int
+	// MaxPayloadBytes caps each page response (0 = unlimited)
+	MaxPayloadBytes int64
+	// DefaultReadingTime is the reading time in minutes given to articles the feed
+	// reports none for, since the feed carries no text to estimate it from (0 = unset)
+	DefaultReadingTime int
 }
 
 func NewJSONProvider(baseURL string, timeout time.Duration) *JSONProvider {
@@ -32,89 +46,202 @@ func NewJSONProvider(baseURL string, timeout time.Duration) *JSONProvider {
 			MaxIdleConnsPerHost: 10,
 		},
 	}
-	return &JSONProvider{Client: client, BaseURL: baseURL, Provider: "provider1", Limit: 40, Offset: 0}
+	return &JSONProvider{Client: client, BaseURL: baseURL, Provider: "provider1", Limit: defaultPageSize, Offset: 0, MaxPages: defaultMaxPages, Retry: DefaultRetryPolicy(), RequestsPerMinute: 100, MaxPayloadBytes: DefaultMaxPayloadBytes}
 }
 
 func (p *JSONProvider) GetProviderID() string { return p.Provider }
 func (p *JSONProvider) GetRateLimit() domainp.RateLimit {
-	return domainp.RateLimit{RequestsPerMinute: 100}
+	return domainp.RateLimit{RequestsPerMinute: p.RequestsPerMinute}
+}
+
+type provider1Item struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Type    string `json:"type"`
+	Metrics struct {
+		Views       int64  `json:"views,omitempty"`
+		Likes       int64  `json:"likes,omitempty"`
+		Duration    string `json:"duration,omitempty"`
+		ReadingTime *int   `json:"reading_time,omitempty"`
+		Reactions   int    `json:"reactions,omitempty"`
+		Comments    *int   `json:"comments,omitempty"`
+	} `json:"metrics"`
+	PublishedAt string   `json:"published_at"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+type provider1Pagination struct {
+	Total   int `json:"total"`
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
 }
 
 type provider1Response struct {
-	Contents []struct {
-		ID      string `json:"id"`
-		Title   string `json:"title"`
-		Type    string `json:"type"`
-		Metrics struct {
-			Views     int64  `json:"views,omitempty"`
-			Likes     int64  `json:"likes,omitempty"`
-			Duration  string `json:"duration,omitempty"`
-			Reactions int    `json:"reactions,omitempty"`
-		} `json:"metrics"`
-		PublishedAt time.Time `json:"published_at"`
-		Tags        []string  `json:"tags,omitempty"`
-	} `json:"contents"`
-	Pagination struct {
-		Total   int `json:"total"`
-		Page    int `json:"page"`
-		PerPage int `json:"per_page"`
-	} `json:"pagination,omitempty"`
+	Contents   []provider1Item     `json:"contents"`
+	Pagination provider1Pagination `json:"pagination,omitempty"`
+}
+
+// FetchContents collects every item of StreamContents.
+func (p *JSONProvider) FetchContents(ctx context.Context) ([]domainp.ProviderContent, error) {
+	var out []domainp.ProviderContent
+	err := p.StreamContents(ctx, func(pc domainp.ProviderContent) error {
+		out = append(out, pc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (p *JSONProvider) FetchContents() ([]domainp.ProviderContent, error) {
+// StreamContents walks every page of the feed until the reported total is reached,
+// an empty page is returned, a page yields no unseen items, or MaxPages is hit.
+// Items are decoded from the response one at a time and passed to yield.
+func (p *JSONProvider) StreamContents(ctx context.Context, yield func(domainp.ProviderContent) error) error {
+	limit := p.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	delay := pageDelay(p.PageDelay, p.GetRateLimit())
+	seen := make(map[string]struct{})
+	offset := p.Offset
+	for page := 0; p.MaxPages <= 0 || page < p.MaxPages; page++ {
+		if page > 0 {
+			if err := waitPage(ctx, delay); err != nil {
+				return err
+			}
+		}
+		count, fresh := 0, 0
+		pg, err := p.streamPage(ctx, limit, offset, func(raw json.RawMessage) error {
+			count++
+			var it provider1Item
+			if err := json.Unmarshal(raw, &it); err != nil {
+				fresh++
+				return yield(undecodable(p.Provider, jsonItemID(raw), raw, err))
+			}
+			if _, dup := seen[it.ID]; dup {
+				return nil
+			}
+			seen[it.ID] = struct{}{}
+			fresh++
+			return yield(p.mapItem(it, raw))
+		})
+		if err != nil {
+			return fmt.Errorf("provider1 offset %d: %w", offset, err)
+		}
+		offset += count
+		if count == 0 || fresh == 0 {
+			return nil
+		}
+		if pg.Total > 0 && offset >= pg.Total {
+			return nil
+		}
+	}
+	reportMaxPages(ctx, p.MaxPages)
+	return nil
+}
+
+// streamPage requests one page and hands each element of "contents" to fn as it is
+// read, undecoded; other top-level fields except "pagination" are skipped.
+func (p *JSONProvider) streamPage(ctx context.Context, limit, offset int, fn func(json.RawMessage) error) (provider1Pagination, error) {
+	var pg provider1Pagination
 	u, _ := url.Parse(p.BaseURL + "/contents")
 	q := u.Query()
-	q.Set("limit", fmt.Sprintf("%d", p.Limit))
-	q.Set("offset", fmt.Sprintf("%d", p.Offset))
+	q.Set("limit", fmt.Sprintf("%d", limit))
+	q.Set("offset", fmt.Sprintf("%d", offset))
 	u.RawQuery = q.Encode()
 
-	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, u.String(), http.NoBody)
-	resp, err := p.Client.Do(req)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	resp, err := doWithRetry(ctx, p.Client, req, p.Retry)
 	if err != nil {
-		return nil, err
+		return pg, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status %d from provider1", resp.StatusCode)
+		return pg, fmt.Errorf("status %d from provider1", resp.StatusCode)
 	}
-	var pr provider1Response
-	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
-		return nil, err
+	body, err := limitBody(resp, p.MaxPayloadBytes)
+	if err != nil {
+		return pg, err
+	}
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return pg, err
 	}
-	out := make([]domainp.ProviderContent, 0, len(pr.Contents))
-	for _, it := range pr.Contents {
-		pc := domainp.ProviderContent{
-			ProviderID:        p.Provider,
-			ProviderContentID: it.ID,
-			Title:             it.Title,
-			Description:       "", // No description in this provider format
-			PublishedAt:       it.PublishedAt,
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return pg, err
 		}
-		switch it.Type {
-		case "video":
-			pc.ContentType = "video"
-			pc.URL = fmt.Sprintf("https://example.com/video/%s", it.ID)
-			pc.ThumbnailURL = fmt.Sprintf("https://example.com/thumb/%s.jpg", it.ID)
-			if it.Metrics.Views != 0 {
-				pc.Views = &it.Metrics.Views
+		switch tok {
+		case "contents":
+			if err := streamArray(dec, fn); err != nil {
+				return pg, err
 			}
-			if it.Metrics.Likes != 0 {
-				pc.Likes = &it.Metrics.Likes
+		case "pagination":
+			if err := dec.Decode(&pg); err != nil {
+				return pg, err
 			}
-		case "article":
-			pc.ContentType = "text"
-			pc.URL = fmt.Sprintf("https://example.com/article/%s", it.ID)
-			if it.Metrics.Reactions != 0 {
-				pc.Reactions = &it.Metrics.Reactions
-			}
-			// Estimate reading time from duration if available (not present in current format)
-			readTime := 5 // default
-			pc.ReadingTime = &readTime
 		default:
-			pc.ContentType = "text"
-			pc.URL = fmt.Sprintf("https://example.com/content/%s", it.ID)
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return pg, err
+			}
 		}
-		out = append(out, pc)
 	}
-	return out, nil
+	return pg, expectDelim(dec, '}')
+}
+
+// mapItem maps a decoded element; raw is the element as received, kept for quarantine.
+func (p *JSONProvider) mapItem(it provider1Item, raw []byte) domainp.ProviderContent {
+	pc := domainp.ProviderContent{
+		ProviderID:        p.Provider,
+		ProviderContentID: it.ID,
+		Title:             it.Title,
+		Description:       "", // No description in this provider format
+		Tags:              it.Tags,
+		Comments:          it.Metrics.Comments,
+		Raw:               raw,
+	}
+	mapPublishedAt(&pc, "published_at", it.PublishedAt)
+	switch it.Type {
+	case "video":
+		pc.ContentType = "video"
+		pc.URL = fmt.Sprintf("https://example.com/video/%s", it.ID)
+		pc.ThumbnailURL = fmt.Sprintf("https://example.com/thumb/%s.jpg", it.ID)
+		if it.Metrics.Views != 0 {
+			v := it.Metrics.Views
+			pc.Views = &v
+		}
+		if it.Metrics.Likes != 0 {
+			l := it.Metrics.Likes
+			pc.Likes = &l
+		}
+		mapDuration(&pc, "metrics.duration", it.Metrics.Duration)
+	case "article":
+		pc.ContentType = "text"
+		pc.URL = fmt.Sprintf("https://example.com/article/%s", it.ID)
+		if it.Metrics.Reactions != 0 {
+			r := it.Metrics.Reactions
+			pc.Reactions = &r
+		}
+		pc.ReadingTime = it.Metrics.ReadingTime
+		if pc.ReadingTime == nil && p.DefaultReadingTime > 0 {
+			rt := p.DefaultReadingTime
+			pc.ReadingTime = &rt
+		}
+	default:
+		// Left unmapped so validation quarantines the item
+		pc.ContentType = it.Type
+	}
+	return pc
+}
+
+// jsonItemID reads the "id" of an element that did not decode as a whole, if it can.
+func jsonItemID(raw []byte) string {
+	var v struct {
+		ID any `json:"id"`
+	}
+	_ = json.Unmarshal(raw, &v)
+	return asString(v.ID)
 }
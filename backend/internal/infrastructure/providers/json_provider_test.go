@@ -1,11 +1,17 @@
 package providers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	domainp "search_engine/internal/domain/providers"
 )
 
 func TestJSONProvider_FetchContents(t *testing.T) {
@@ -35,7 +41,7 @@ func TestJSONProvider_FetchContents(t *testing.T) {
 	defer srv.Close()
 
 	p := NewJSONProvider(srv.URL, 5*time.Second)
-	items, err := p.FetchContents()
+	items, err := p.FetchContents(context.Background())
 	if err != nil {
 		t.Fatalf("FetchContents error: %v", err)
 	}
@@ -51,6 +57,24 @@ func TestJSONProvider_FetchContents(t *testing.T) {
 	if items[0].Title != "Go Programming Tutorial" {
 		t.Fatalf("unexpected title: %s", items[0].Title)
 	}
+	if len(items[0].Tags) != 2 || items[0].Tags[0] != "programming" {
+		t.Fatalf("expected tags to be mapped, got %v", items[0].Tags)
+	}
+	if items[0].DurationSeconds == nil || *items[0].DurationSeconds != 930 {
+		t.Fatalf("expected duration 15:30 as 930s, got %v", items[0].DurationSeconds)
+	}
+	if items[1].ReadingTime != nil {
+		t.Fatalf("reading time must not be invented without a default, got %d", *items[1].ReadingTime)
+	}
+
+	p.DefaultReadingTime = 5
+	items, err = p.FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if items[1].ReadingTime == nil || *items[1].ReadingTime != 5 {
+		t.Fatalf("expected the default reading time of 5, got %v", items[1].ReadingTime)
+	}
 }
 
 func TestJSONProvider_Timeout(t *testing.T) {
@@ -61,7 +85,7 @@ func TestJSONProvider_Timeout(t *testing.T) {
 	}))
 	defer srv.Close()
 	p := NewJSONProvider(srv.URL, 50*time.Millisecond)
-	if _, err := p.FetchContents(); err == nil {
+	if _, err := p.FetchContents(context.Background()); err == nil {
 		t.Fatalf("expected timeout error")
 	}
 }
@@ -73,7 +97,182 @@ func TestJSONProvider_InvalidJSON(t *testing.T) {
 	}))
 	defer srv.Close()
 	p := NewJSONProvider(srv.URL, 2*time.Second)
-	if _, err := p.FetchContents(); err == nil {
+	if _, err := p.FetchContents(context.Background()); err == nil {
 		t.Fatalf("expected error for invalid JSON")
 	}
 }
+
+func TestJSONProvider_QuarantinesUndecodableItems(t *testing.T) {
+	bad := `{"id": 7, "title": "Numeric ID", "type": "video"}`
+	good := `{ "id":"v1","title":"Kept as sent","type":"video","metrics":{"views":5},"published_at":"2024-03-15T10:00:00Z" }`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("offset") != "0" {
+			_, _ = w.Write([]byte(`{"contents":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"contents":[` + bad + `,` + good + `]}`))
+	}))
+	defer srv.Close()
+
+	items, err := NewJSONProvider(srv.URL, 2*time.Second).FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("an undecodable element must not fail the page: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].ProviderContentID != "7" || len(items[0].Problems) != 1 || string(items[0].Raw) != bad {
+		t.Fatalf("undecodable element should carry its ID, a problem and its bytes: %+v", items[0])
+	}
+	if items[1].ProviderContentID != "v1" || len(items[1].Problems) != 0 || string(items[1].Raw) != good {
+		t.Fatalf("decoded element should keep its original bytes, got %s", items[1].Raw)
+	}
+}
+
+func TestJSONProvider_ContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	p := NewJSONProvider(srv.URL, 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := p.FetchContents(ctx); err == nil {
+		t.Fatalf("expected error for cancelled context")
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("fetch did not stop on context cancellation")
+	}
+}
+
+func TestJSONProvider_Pagination(t *testing.T) {
+	all := []string{"v1", "v2", "a1", "a2", "v3"}
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		contents := []map[string]any{}
+		for i := offset; i < offset+limit && i < len(all); i++ {
+			contents = append(contents, map[string]any{
+				"id": all[i], "title": "T " + all[i], "type": "video",
+				"metrics": map[string]any{"views": 10}, "published_at": "2024-03-15T10:00:00Z",
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"contents":   contents,
+			"pagination": map[string]any{"total": len(all), "page": offset/limit + 1, "per_page": limit},
+		})
+	}))
+	defer srv.Close()
+
+	var partial string
+	ctx := domainp.WithPartialRecorder(context.Background(), func(reason string) { partial = reason })
+	p := NewJSONProvider(srv.URL, 5*time.Second)
+	p.Limit = 2
+	items, err := p.FetchContents(ctx)
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if len(items) != len(all) {
+		t.Fatalf("expected %d items got %d", len(all), len(items))
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 page requests, got %d", calls)
+	}
+	if partial != "" {
+		t.Fatalf("expected a complete fetch, got partial %q", partial)
+	}
+
+	calls = 0
+	p.MaxPages = 1
+	items, err = p.FetchContents(ctx)
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if len(items) != 2 || calls != 1 {
+		t.Fatalf("expected 2 items in 1 request, got %d items in %d requests", len(items), calls)
+	}
+	if partial == "" {
+		t.Fatalf("expected stopping at max pages to be reported as partial")
+	}
+}
+
+func TestPageDelay_RespectsRateLimit(t *testing.T) {
+	p := NewJSONProvider("http://example", 0)
+	if d := pageDelay(0, p.GetRateLimit()); d != 600*time.Millisecond {
+		t.Fatalf("expected rate-limit spacing 600ms, got %v", d)
+	}
+	if d := pageDelay(2*time.Second, p.GetRateLimit()); d != 2*time.Second {
+		t.Fatalf("expected configured delay 2s, got %v", d)
+	}
+}
+
+func TestJSONProvider_StreamsItemsBeforeTheBodyEnds(t *testing.T) {
+	first := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") != "0" {
+			_, _ = w.Write([]byte(`{"contents":[]}`))
+			return
+		}
+		// The rest of the page is only sent once the first item has been yielded
+		_, _ = w.Write([]byte(`{"meta":{"source":"test"},"contents":[{"id":"a1","title":"A","type":"article","published_at":"2024-03-14T14:30:00Z"},`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-first:
+		case <-time.After(2 * time.Second):
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"a2","title":"B","type":"article"}],"pagination":{"total":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewJSONProvider(srv.URL, 5*time.Second)
+	var ids []string
+	err := p.StreamContents(context.Background(), func(pc domainp.ProviderContent) error {
+		if len(ids) == 0 {
+			close(first)
+		}
+		ids = append(ids, pc.ProviderContentID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "a1" || ids[1] != "a2" {
+		t.Fatalf("unexpected items %v", ids)
+	}
+}
+
+func TestJSONProvider_MaxPayloadBytes(t *testing.T) {
+	page := `{"contents":[` + strings.Repeat(`{"id":"x","title":"padding","type":"article"},`, 50) + `{"id":"y","title":"T","type":"article"}]}`
+	declare := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if declare {
+			w.Header().Set("Content-Length", strconv.Itoa(len(page)))
+		}
+		_, _ = w.Write([]byte(page))
+		// Without a declared length the flush makes the response chunked
+		w.(http.Flusher).Flush()
+	}))
+	defer srv.Close()
+
+	p := NewJSONProvider(srv.URL, 5*time.Second)
+	p.MaxPayloadBytes = 512
+	for _, declare = range []bool{true, false} {
+		if _, err := p.FetchContents(context.Background()); !errors.Is(err, ErrPayloadTooLarge) {
+			t.Fatalf("declared length %v: expected ErrPayloadTooLarge, got %v", declare, err)
+		}
+	}
+	p.MaxPayloadBytes = int64(len(page))
+	if _, err := p.FetchContents(context.Background()); err != nil {
+		t.Fatalf("a payload at the limit should be accepted: %v", err)
+	}
+}
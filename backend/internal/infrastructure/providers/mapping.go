@@ -0,0 +1,49 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// Helpers shared by the item mappers. Values that are present but cannot be mapped
+// are recorded in ProviderContent.Problems instead of being replaced by a default,
+// so the sync pipeline can quarantine the item.
+
+// mapPublishedAt parses s with RFC3339, the extra layouts and then YYYY-MM-DD.
+// An empty value leaves PublishedAt zero.
+func mapPublishedAt(pc *domainp.ProviderContent, field, s string, layouts ...string) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return
+	}
+	for _, layout := range append(append([]string{time.RFC3339}, layouts...), "2006-01-02") {
+		if t, err := time.Parse(layout, s); err == nil {
+			pc.PublishedAt = t
+			return
+		}
+	}
+	pc.Problems = append(pc.Problems, fmt.Sprintf("%s: invalid date %q", field, s))
+}
+
+// mapDuration sets DurationSeconds from "mm:ss", "hh:mm:ss" or seconds.
+func mapDuration(pc *domainp.ProviderContent, field, s string) {
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	if pc.DurationSeconds = durationPtr(s); pc.DurationSeconds == nil {
+		pc.Problems = append(pc.Problems, fmt.Sprintf("%s: invalid duration %q", field, s))
+	}
+}
+
+// rawJSON encodes a decoded item for ProviderContent.Raw.
+func rawJSON(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+const (
+	defaultPageSize = 40
+	defaultMaxPages = 100
+)
+
+// pageDelay returns the pause between two page requests: the configured delay,
+// raised to the spacing implied by the provider's requests-per-minute limit.
+func pageDelay(configured time.Duration, rl domainp.RateLimit) time.Duration {
+	d := configured
+	if rl.RequestsPerMinute > 0 {
+		if spacing := time.Minute / time.Duration(rl.RequestsPerMinute); spacing > d {
+			d = spacing
+		}
+	}
+	return d
+}
+
+// waitPage sleeps for d, returning early with the context error if ctx is done.
+func waitPage(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reportMaxPages tells the sync that a fetch stopped at maxPages, so items beyond
+// the last page were not seen.
+func reportMaxPages(ctx context.Context, maxPages int) {
+	domainp.ReportPartial(ctx, fmt.Sprintf("stopped at max pages (%d)", maxPages))
+}
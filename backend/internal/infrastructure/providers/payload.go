@@ -0,0 +1,132 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// DefaultMaxPayloadBytes caps a single provider response unless configured otherwise
+const DefaultMaxPayloadBytes = 64 << 20
+
+// ErrPayloadTooLarge is returned when a provider response exceeds MaxPayloadBytes.
+var ErrPayloadTooLarge = errors.New("provider payload exceeds the size limit")
+
+// limitBody returns the response body capped at max bytes (0 = unlimited). A
+// declared Content-Length above the cap fails before anything is read.
+func limitBody(resp *http.Response, max int64) (io.Reader, error) {
+	if max <= 0 {
+		return resp.Body, nil
+	}
+	if resp.ContentLength > max {
+		return nil, fmt.Errorf("%w: %d bytes declared, limit %d", ErrPayloadTooLarge, resp.ContentLength, max)
+	}
+	return &limitedReader{r: resp.Body, n: max}, nil
+}
+
+// limitedReader is io.LimitReader that fails instead of reporting a silent EOF,
+// so a truncated document is never mistaken for a complete one.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrPayloadTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// expectDelim reads the next JSON token and fails unless it is want.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %q in JSON payload, got %v", want, tok)
+	}
+	return nil
+}
+
+// streamArray hands each element of the array at the decoder's position to fn as
+// the bytes it was sent as, so one malformed element does not fail the rest; a null
+// array is treated as empty.
+func streamArray(dec *json.Decoder, fn func(json.RawMessage) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("expected an array in JSON payload, got %v", tok)
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// undecodable stands in for a feed element that does not decode into the
+// provider's item type. It carries the element's original bytes and a problem, so
+// the sync quarantines it and goes on with the rest of the feed.
+func undecodable(providerID, id string, raw []byte, err error) domainp.ProviderContent {
+	return domainp.ProviderContent{
+		ProviderID:        providerID,
+		ProviderContentID: id,
+		Raw:               raw,
+		Problems:          []string{fmt.Sprintf("invalid item: %v", err)},
+	}
+}
+
+// offsetRecorder keeps the bytes read through it, so the source text of a decoded
+// XML element can be cut out by xml.Decoder input offsets.
+type offsetRecorder struct {
+	r    io.Reader
+	buf  []byte
+	base int64 // input offset of buf[0]
+}
+
+func (o *offsetRecorder) Read(p []byte) (int, error) {
+	n, err := o.r.Read(p)
+	o.buf = append(o.buf, p[:n]...)
+	return n, err
+}
+
+// slice returns a copy of the input between the offsets from and to.
+func (o *offsetRecorder) slice(from, to int64) []byte {
+	return append([]byte(nil), o.buf[from-o.base:to-o.base]...)
+}
+
+// discard drops the input before off once enough of it has piled up.
+func (o *offsetRecorder) discard(off int64) {
+	if off-o.base < 64<<10 {
+		return
+	}
+	n := copy(o.buf, o.buf[off-o.base:])
+	o.buf = o.buf[:n]
+	o.base = off
+}
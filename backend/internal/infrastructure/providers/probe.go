@@ -0,0 +1,125 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// probeGET sends a single GET without retries, so a health check reports the
+// source as it is now rather than after backing off.
+func probeGET(ctx context.Context, client *http.Client, rawURL, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	return doWithRetry(ctx, client, req, RetryPolicy{})
+}
+
+// Probe requests a single item and checks that the response decodes.
+func (p *JSONProvider) Probe(ctx context.Context) (int, error) {
+	q := url.Values{"limit": {"1"}, "offset": {"0"}}
+	resp, err := probeGET(ctx, p.Client, p.BaseURL+"/contents?"+q.Encode(), "application/json")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("status %d from %s", resp.StatusCode, p.Provider)
+	}
+	var pr provider1Response
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return resp.StatusCode, fmt.Errorf("%s: %w", p.Provider, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// Probe requests a one-item page and checks that the feed decodes.
+func (p *XMLProvider) Probe(ctx context.Context) (int, error) {
+	q := url.Values{"page": {"1"}, "size": {"1"}}
+	resp, err := probeGET(ctx, p.Client, p.BaseURL+"/feed?"+q.Encode(), "application/xml")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("status %d from %s", resp.StatusCode, p.Provider)
+	}
+	var feed xmlFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return resp.StatusCode, fmt.Errorf("%s: %w", p.Provider, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// Probe requests the first page and checks that it decodes and contains items_path.
+func (p *GenericProvider) Probe(ctx context.Context) (int, error) {
+	u, err := p.pageURL(0, 0, domainp.Cursor{})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := probeGET(ctx, p.Client, u, "")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("status %d from %s", resp.StatusCode, p.Def.ID)
+	}
+	doc, err := p.decode(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("%s: %w", p.Def.ID, err)
+	}
+	if lookupPath(doc, p.Def.ItemsPath) == nil {
+		return resp.StatusCode, fmt.Errorf("%s: items_path %q not found", p.Def.ID, p.Def.ItemsPath)
+	}
+	return resp.StatusCode, nil
+}
+
+// Probe downloads the feed unconditionally and checks that it is RSS or Atom.
+func (p *FeedProvider) Probe(ctx context.Context) (int, error) {
+	resp, err := probeGET(ctx, p.Client, p.URL, "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("status %d from %s", resp.StatusCode, p.Provider)
+	}
+	if _, err := p.decode(resp.Body); err != nil {
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, nil
+}
+
+// Probe checks that every import file exists and can be opened, without reading
+// it: parsing is left to the sync, whose row errors never fail the import anyway.
+func (p *FileProvider) Probe(ctx context.Context) (int, error) {
+	files, err := p.files()
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		return 0, fmt.Errorf("%s: no importable files in %s", p.Provider, p.Path)
+	}
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		fh, err := os.Open(f)
+		if err != nil {
+			return 0, err
+		}
+		_ = fh.Close()
+	}
+	return 0, nil
+}
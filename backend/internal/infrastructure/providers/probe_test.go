@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONProvider_ProbeRequestsOneItemWithoutRetry(t *testing.T) {
+	calls, status := 0, http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("limit") != "1" {
+			t.Errorf("expected limit=1, got %q", r.URL.RawQuery)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"contents":[],"pagination":{"total":0}}`))
+	}))
+	defer srv.Close()
+
+	p := NewJSONProvider(srv.URL, 5*time.Second)
+	code, err := p.Probe(context.Background())
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("expected healthy probe, got %d %v", code, err)
+	}
+
+	calls, status = 0, http.StatusServiceUnavailable
+	code, err = p.Probe(context.Background())
+	if err == nil || code != http.StatusServiceUnavailable || calls != 1 {
+		t.Fatalf("expected a single failed request with 503, got %d %v after %d calls", code, err, calls)
+	}
+}
+
+func TestXMLProvider_ProbeRejectsMalformedPayload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<feed><items><item>`))
+	}))
+	defer srv.Close()
+	code, err := NewXMLProvider(srv.URL, 5*time.Second).Probe(context.Background())
+	if err == nil || code != http.StatusOK {
+		t.Fatalf("expected a parse error with status 200, got %d %v", code, err)
+	}
+}
+
+func TestGenericProvider_ProbeChecksItemsPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	}))
+	defer srv.Close()
+	def := ProviderDefinition{ID: "p3", BaseURL: srv.URL, Format: "json", ItemsPath: "data.list", Fields: FieldMapping{ID: "id", Title: "title"}}
+	_, err := NewGenericProvider(def, 5*time.Second).Probe(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "items_path") {
+		t.Fatalf("expected items_path error, got %v", err)
+	}
+	def.ItemsPath = "results"
+	if _, err := NewGenericProvider(def, 5*time.Second).Probe(context.Background()); err != nil {
+		t.Fatalf("expected healthy probe, got %v", err)
+	}
+}
+
+func TestFeedProvider_ProbeSendsNoValidators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("probe must not send validators")
+		}
+		w.Header().Set("ETag", `"v2"`)
+		_, _ = w.Write([]byte(rssBody))
+	}))
+	defer srv.Close()
+	if _, err := NewFeedProvider("blog", srv.URL, 5*time.Second).Probe(context.Background()); err != nil {
+		t.Fatalf("expected healthy probe, got %v", err)
+	}
+}
+
+func TestFileProvider_ProbeMissingPath(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewFileProvider("import", filepath.Join(dir, "missing.ndjson")).Probe(context.Background()); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	path := filepath.Join(dir, "items.ndjson")
+	if err := os.WriteFile(path, []byte("{\"id\":\"1\"}\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileProvider("import", path).Probe(context.Background()); err != nil {
+		t.Fatalf("row errors must not fail the probe, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrNonPublicAddress is returned when a restricted client would connect to a
+// loopback, private, link-local or otherwise internal address.
+var ErrNonPublicAddress = errors.New("refusing to connect to a non-public address")
+
+// Address ranges that are global unicast but not reachable on the public internet
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+func isPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewPreviewProvider builds a provider for an admin-supplied definition that is
+// fetched once and not stored. Only http and https URLs are accepted and, unless
+// allowPrivate is set, connections to internal addresses are refused. Transports are
+// refused: a one-off definition must not send the deployment's secrets anywhere.
+func NewPreviewProvider(def ProviderDefinition, timeout time.Duration, allowPrivate bool) (*GenericProvider, error) {
+	u, err := url.Parse(def.BaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("base_url must be an http or https URL")
+	}
+	if def.Transport != nil {
+		return nil, errors.New("transport is not accepted in previews; register the provider to use auth, proxies or a CA bundle")
+	}
+	p := NewGenericProvider(def, timeout)
+	if !allowPrivate {
+		if err := RestrictToPublic(p.Client); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// RestrictToPublic makes client refuse connections to non-public addresses. The
+// check runs on the resolved address of every connection, so redirects, token
+// requests and host names resolving inside the network are refused as well. Clients
+// using a proxy cannot be restricted, since the proxy picks the final address.
+func RestrictToPublic(client *http.Client) error {
+	base := baseTransport(client.Transport)
+	if base == nil {
+		return errors.New("client transport cannot be restricted")
+	}
+	if base.Proxy != nil {
+		return errors.New("a proxy cannot be used with public-only requests")
+	}
+	base.DialContext = (&net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddr(ap.Addr()) {
+				return fmt.Errorf("%w: %s", ErrNonPublicAddress, ap.Addr())
+			}
+			return nil
+		},
+	}).DialContext
+	return nil
+}
+
+func baseTransport(rt http.RoundTripper) *http.Transport {
+	switch t := rt.(type) {
+	case *http.Transport:
+		return t
+	case *authTransport:
+		return baseTransport(t.base)
+	}
+	return nil
+}
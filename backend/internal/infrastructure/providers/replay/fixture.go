@@ -0,0 +1,148 @@
+// Package replay records provider HTTP exchanges into fixture files and replays
+// them offline, so provider mapping can be tested deterministically against real
+// payloads and compared with golden files of the mapped ProviderContent.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"search_engine/internal/domain/entities"
+	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/infrastructure/providers"
+)
+
+// Fixture is a recorded provider fetch: the provider it came from and every HTTP
+// exchange in request order.
+type Fixture struct {
+	Source     Source     `json:"source"`
+	RecordedAt time.Time  `json:"recorded_at"`
+	Exchanges  []Exchange `json:"exchanges"`
+}
+
+// Source is the provider configuration a fixture was recorded with. Replaying needs
+// the same settings so the provider sends the same requests.
+type Source struct {
+	ProviderID string                `json:"provider_id"`
+	Kind       entities.ProviderKind `json:"kind"`
+	BaseURL    string                `json:"base_url"`
+	// Definition is the generic provider definition for kind generic
+	Definition json.RawMessage `json:"definition,omitempty"`
+	PageSize   int             `json:"page_size,omitempty"`
+	MaxPages   int             `json:"max_pages,omitempty"`
+}
+
+// Exchange is one request and the response it got. Requests are matched on method
+// and path with query; hosts, request headers and credentials are not stored.
+type Exchange struct {
+	Method   string            `json:"method"`
+	Path     string            `json:"path"`
+	Status   int               `json:"status"`
+	Header   map[string]string `json:"header,omitempty"`
+	Body     string            `json:"body"`
+	Duration string            `json:"duration,omitempty"`
+}
+
+// keptHeaders are the response headers mapping depends on; others are not recorded
+// so cookies and tracing headers never end up in fixtures.
+var keptHeaders = []string{"Content-Type", "ETag", "Last-Modified"}
+
+// Load reads a fixture file.
+func Load(path string) (*Fixture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture as indented JSON, creating the directory if needed.
+func (f *Fixture) Save(path string) error {
+	return writeJSON(path, f)
+}
+
+// Provider builds the fixture's provider with rt as its HTTP transport. Recorded
+// retries are replayed with the same attempts but without the waits between
+// attempts or pages, since nothing goes over the network.
+func (s Source) Provider(rt http.RoundTripper) (domainp.IContentProvider, error) {
+	retry := providers.DefaultRetryPolicy()
+	retry.BaseDelay, retry.MaxDelay = time.Millisecond, time.Millisecond
+	b := providers.Builder{Timeout: 10 * time.Second, PageSize: s.PageSize, MaxPages: s.MaxPages, Retry: retry}
+	return s.build(b, nil, maxRate, func(http.RoundTripper) http.RoundTripper { return rt })
+}
+
+// maxRate makes the rate-limit page spacing negligible during replay
+const maxRate = 1 << 30
+
+// build creates the provider and replaces its transport with wrap(transport).
+func (s Source) build(b providers.Builder, transport json.RawMessage, rate int, wrap func(http.RoundTripper) http.RoundTripper) (domainp.IContentProvider, error) {
+	p, err := b.Build(entities.Provider{
+		ID:                 s.ProviderID,
+		Kind:               s.Kind,
+		BaseURL:            s.BaseURL,
+		Definition:         s.Definition,
+		Transport:          transport,
+		RateLimitPerMinute: rate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := ClientOf(p)
+	if c == nil {
+		return nil, fmt.Errorf("provider %q: kind %s has no HTTP client to record or replay", s.ProviderID, s.Kind)
+	}
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.Transport = wrap(base)
+	return p, nil
+}
+
+// ClientOf returns the HTTP client of the built-in HTTP providers and nil otherwise.
+func ClientOf(p domainp.IContentProvider) *http.Client {
+	switch p := p.(type) {
+	case *providers.JSONProvider:
+		return p.Client
+	case *providers.XMLProvider:
+		return p.Client
+	case *providers.GenericProvider:
+		return p.Client
+	case *providers.FeedProvider:
+		return p.Client
+	}
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	b, err := encodeIndented(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// encodeIndented is json.MarshalIndent with a trailing newline and without HTML
+// escaping, so query strings and payloads stay readable in fixtures.
+func encodeIndented(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
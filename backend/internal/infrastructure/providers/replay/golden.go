@@ -0,0 +1,143 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// goldenItem is the golden form of a mapped item. Problems are part of the mapping
+// result although ProviderContent does not serialize them; Raw is left out.
+type goldenItem struct {
+	domainp.ProviderContent
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Golden renders mapped items as the indented JSON stored in golden files.
+func Golden(items []domainp.ProviderContent) ([]byte, error) {
+	out := make([]goldenItem, len(items))
+	for i, pc := range items {
+		out[i] = goldenItem{ProviderContent: pc, Problems: pc.Problems}
+	}
+	return encodeIndented(out)
+}
+
+// SaveGolden writes the golden file of items.
+func SaveGolden(path string, items []domainp.ProviderContent) error {
+	b, err := Golden(items)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// Diff compares items with a golden file and describes every difference, one per
+// line: "- id" for items missing from items, "+ id" for items not in the golden
+// file and "~ id field: want -> got" for changed fields. It returns nil when they match.
+func Diff(golden []byte, items []domainp.ProviderContent) ([]string, error) {
+	got, err := Golden(items)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(golden, got) {
+		return nil, nil
+	}
+	wantItems, err := keyed(golden)
+	if err != nil {
+		return nil, fmt.Errorf("golden file: %w", err)
+	}
+	gotItems, err := keyed(got)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, k := range wantItems.order {
+		g, ok := gotItems.items[k]
+		if !ok {
+			lines = append(lines, "- "+k)
+			continue
+		}
+		w := wantItems.items[k]
+		for _, f := range fieldNames(w, g) {
+			wv, gv := w[f], g[f]
+			if !bytes.Equal(wv, gv) {
+				lines = append(lines, fmt.Sprintf("~ %s %s: %s -> %s", k, f, orNone(wv), orNone(gv)))
+			}
+		}
+	}
+	for _, k := range gotItems.order {
+		if _, ok := wantItems.items[k]; !ok {
+			lines = append(lines, "+ "+k)
+		}
+	}
+	if len(lines) == 0 {
+		// Same items and values, different order or formatting
+		lines = append(lines, "~ item order or formatting differs")
+	}
+	return lines, nil
+}
+
+type keyedItems struct {
+	order []string
+	items map[string]map[string]json.RawMessage
+}
+
+// keyed indexes golden items by provider_content_id; repeated or empty IDs get an
+// occurrence suffix so they still pair up in order.
+func keyed(b []byte) (keyedItems, error) {
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &list); err != nil {
+		return keyedItems{}, err
+	}
+	out := keyedItems{items: make(map[string]map[string]json.RawMessage, len(list))}
+	seen := map[string]int{}
+	for _, it := range list {
+		var id string
+		_ = json.Unmarshal(it["provider_content_id"], &id)
+		k := id
+		if n := seen[id]; n > 0 || id == "" {
+			k = fmt.Sprintf("%s#%d", id, n+1)
+		}
+		seen[id]++
+		for f, v := range it {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, v); err == nil {
+				it[f] = compact.Bytes()
+			}
+		}
+		out.order = append(out.order, k)
+		out.items[k] = it
+	}
+	return out, nil
+}
+
+func fieldNames(a, b map[string]json.RawMessage) []string {
+	set := map[string]struct{}{}
+	for f := range a {
+		set[f] = struct{}{}
+	}
+	for f := range b {
+		set[f] = struct{}{}
+	}
+	out := make([]string, 0, len(set))
+	for f := range set {
+		out = append(out, f)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func orNone(v json.RawMessage) string {
+	if v == nil {
+		return "(none)"
+	}
+	return string(v)
+}
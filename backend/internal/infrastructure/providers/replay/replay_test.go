@@ -0,0 +1,147 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"search_engine/internal/domain/entities"
+)
+
+// TestGoldenFixtures replays the recorded provider feeds offline. After a deliberate
+// mapping change, refresh the golden files with
+// go run ./cmd/providerfixture check -fixture <fixture> -golden <golden> -update
+func TestGoldenFixtures(t *testing.T) {
+	for _, name := range []string{"provider1", "provider2"} {
+		t.Run(name, func(t *testing.T) {
+			f, err := Load("testdata/" + name + ".fixture.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			items, err := Replay(context.Background(), f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden, err := os.ReadFile("testdata/" + name + ".golden.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			diff, err := Diff(golden, items)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(diff) > 0 {
+				t.Fatalf("mapped output differs from the golden file:\n%s", strings.Join(diff, "\n"))
+			}
+		})
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Setenv("PROVIDER_SECRET_TEST_REPLAY", "s3cret")
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("X-API-Key") != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("offset") == "0" {
+			_, _ = w.Write([]byte(`{"contents":[{"id":"a1","title":"Clean Code","type":"article","metrics":{"reactions":5},"published_at":"2024-03-14"}],"pagination":{"total":1}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"contents":[]}`))
+	}))
+	defer srv.Close()
+
+	src := Source{ProviderID: "p1", Kind: entities.ProviderKindJSON, BaseURL: srv.URL + "/api", PageSize: 10}
+	f, recorded, err := Record(context.Background(), src, json.RawMessage(`{"auth":{"type":"api_key","secret":{"env":"PROVIDER_SECRET_TEST_REPLAY"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Exchanges) != 1 || f.Exchanges[0].Path != "/api/contents?limit=10&offset=0" || len(recorded) != 1 {
+		t.Fatalf("unexpected recording: %+v", f.Exchanges)
+	}
+	path := t.TempDir() + "/p1.fixture.json"
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := os.ReadFile(path)
+	if strings.Contains(string(saved), "s3cret") || strings.Contains(string(saved), "session") {
+		t.Fatalf("fixture holds credentials or cookies:\n%s", saved)
+	}
+
+	f, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+	replayed, err := Replay(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, _ := Golden(recorded)
+	if diff, _ := Diff(golden, replayed); len(diff) > 0 || calls != 1 {
+		t.Fatalf("replay should match the recording offline, diff %v, server calls %d", diff, calls)
+	}
+
+	f.Source.PageSize = 20
+	if _, err := Replay(context.Background(), f); !errors.Is(err, ErrNoExchange) {
+		t.Fatalf("an unrecorded request should fail with ErrNoExchange, got %v", err)
+	}
+}
+
+func TestReplayer_RepeatsRecordingsInOrder(t *testing.T) {
+	f := &Fixture{Exchanges: []Exchange{
+		{Method: "GET", Path: "/feed", Status: 503},
+		{Method: "GET", Path: "/feed", Status: 200, Body: "ok"},
+	}}
+	c := &http.Client{Transport: NewReplayer(f)}
+	var got []int
+	for i := 0; i < 3; i++ {
+		resp, err := c.Get("http://replay.invalid/feed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		got = append(got, resp.StatusCode)
+	}
+	if got[0] != 503 || got[1] != 200 || got[2] != 200 {
+		t.Fatalf("statuses %v", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	f, err := Load("testdata/provider1.fixture.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := Replay(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, _ := Golden(items)
+	items[0].Title = "Changed"
+	items = append(items[:1], items[2:]...)
+	items = append(items, items[0])
+	items[len(items)-1].ProviderContentID = "new"
+	diff, err := Diff(golden, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`~ v1 title: "Go Programming Tutorial" -> "Changed"`,
+		"- v2",
+		"+ new",
+	}
+	if strings.Join(diff, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("diff:\n%s\nwant:\n%s", strings.Join(diff, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,144 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/infrastructure/providers"
+)
+
+// ErrNoExchange is returned by a Replayer for a request the fixture does not hold.
+var ErrNoExchange = errors.New("replay: no recorded exchange for request")
+
+// Recorder is an http.RoundTripper that passes requests to Base and appends each
+// exchange to Fixture. It sits outside any auth transport, so credentials added
+// there are never seen or recorded.
+type Recorder struct {
+	Base    http.RoundTripper
+	Fixture *Fixture
+
+	mu sync.Mutex
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := r.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	ex := Exchange{
+		Method:   req.Method,
+		Path:     req.URL.RequestURI(),
+		Status:   resp.StatusCode,
+		Body:     string(body),
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	for _, h := range keptHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			if ex.Header == nil {
+				ex.Header = map[string]string{}
+			}
+			ex.Header[h] = v
+		}
+	}
+	r.mu.Lock()
+	r.Fixture.Exchanges = append(r.Fixture.Exchanges, ex)
+	r.mu.Unlock()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers from a fixture. Requests with the
+// same method and path are answered with their recordings in order; once those are
+// used up the last one is repeated.
+type Replayer struct {
+	fixture *Fixture
+
+	mu   sync.Mutex
+	next map[string]int
+}
+
+func NewReplayer(f *Fixture) *Replayer {
+	return &Replayer{fixture: f, next: map[string]int{}}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	key := req.Method + " " + req.URL.RequestURI()
+	r.mu.Lock()
+	var matches []*Exchange
+	for i := range r.fixture.Exchanges {
+		ex := &r.fixture.Exchanges[i]
+		if ex.Method+" "+ex.Path == key {
+			matches = append(matches, ex)
+		}
+	}
+	n := r.next[key]
+	if n < len(matches)-1 {
+		r.next[key] = n + 1
+	}
+	r.mu.Unlock()
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoExchange, key)
+	}
+	ex := matches[n]
+	header := http.Header{}
+	for k, v := range ex.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", ex.Status, http.StatusText(ex.Status)),
+		StatusCode:    ex.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(ex.Body)),
+		ContentLength: int64(len(ex.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Record fetches src over the network and returns the fixture of the fetch with the
+// mapped items. transport is the provider's auth/transport configuration, if any.
+func Record(ctx context.Context, src Source, transport json.RawMessage) (*Fixture, []domainp.ProviderContent, error) {
+	f := &Fixture{Source: src, RecordedAt: time.Now().UTC().Truncate(time.Second)}
+	b := providers.Builder{Timeout: 30 * time.Second, PageSize: src.PageSize, MaxPages: src.MaxPages, Retry: providers.DefaultRetryPolicy()}
+	p, err := src.build(b, transport, 0, func(base http.RoundTripper) http.RoundTripper {
+		return &Recorder{Base: base, Fixture: f}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := p.FetchContents(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, items, nil
+}
+
+// Replay fetches the fixture's provider from its recorded exchanges.
+func Replay(ctx context.Context, f *Fixture) ([]domainp.ProviderContent, error) {
+	p, err := f.Source.Provider(NewReplayer(f))
+	if err != nil {
+		return nil, err
+	}
+	return p.FetchContents(ctx)
+}
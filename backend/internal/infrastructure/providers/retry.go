@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// RetryPolicy controls how idempotent provider requests are retried after a
+// network error, a 429 or a 5xx response.
+type RetryPolicy struct {
+	// MaxAttempts includes the first request; values below 2 disable retries
+	MaxAttempts int
+	// BaseDelay is doubled after every attempt up to MaxDelay, then jittered
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Budget bounds the time spent on one request across all attempts and waits (0 = unbounded)
+	Budget time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 4, BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second, Budget: 30 * time.Second}
+}
+
+// jitter returns a random fraction in [0,1); tests replace it
+var jitter = rand.Float64
+
+// backoff is the wait after the given failed attempt: the exponential delay with
+// its upper half randomised, so replicas retrying together spread out.
+func (rp RetryPolicy) backoff(attempt int) time.Duration {
+	d := rp.MaxDelay
+	if attempt < 31 {
+		if exp := rp.BaseDelay << (attempt - 1); exp > 0 && (rp.MaxDelay <= 0 || exp < rp.MaxDelay) {
+			d = exp
+		}
+	}
+	return d/2 + time.Duration(jitter()*float64(d/2))
+}
+
+// retryable reports whether the outcome of an attempt is worth repeating.
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// The caller gave up; a per-attempt client timeout is still retried
+		return ctx.Err() == nil
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusNotImplemented, resp.StatusCode == http.StatusHTTPVersionNotSupported:
+		return false
+	}
+	return resp.StatusCode >= 500
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// doWithRetry sends req and retries GET and HEAD requests per rp. A Retry-After
+// header replaces the computed backoff; no retry starts when its wait would exceed
+// the budget or the context deadline, and the last response or error is returned.
+// Every attempt passes domainp.AwaitRequest first and is reported through
+// domainp.RecordAttempt.
+func doWithRetry(ctx context.Context, client *http.Client, req *http.Request, rp RetryPolicy) (*http.Response, error) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		if err := domainp.AwaitRequest(ctx); err != nil {
+			return nil, err
+		}
+		at := time.Now()
+		resp, err := client.Do(req.Clone(ctx))
+		a := domainp.RequestAttempt{URL: req.URL.Redacted(), Attempt: attempt, StartedAt: at.UTC(), Duration: time.Since(at)}
+		if err != nil {
+			a.Error = err.Error()
+		} else {
+			a.StatusCode = resp.StatusCode
+		}
+		retry := idempotent && attempt < rp.MaxAttempts && retryable(ctx, resp, err)
+		var wait time.Duration
+		if retry {
+			wait = rp.backoff(attempt)
+			if resp != nil {
+				if ra, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+					wait = ra
+				}
+			}
+			if rp.Budget > 0 && time.Since(start)+wait > rp.Budget {
+				retry = false
+			}
+			if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+				retry = false
+			}
+		}
+		if retry {
+			a.Backoff = wait
+		}
+		domainp.RecordAttempt(ctx, a)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+		if err := waitPage(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+func fastRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Budget: 2 * time.Second}
+}
+
+func TestJSONProvider_RetriesTransientFailures(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte(`{"contents":[{"id":"v1","title":"Go","type":"video"}],"pagination":{"total":1}}`))
+		}
+	}))
+	defer srv.Close()
+	p := NewJSONProvider(srv.URL, 5*time.Second)
+	p.Retry = fastRetry()
+	var attempts []domainp.RequestAttempt
+	ctx := domainp.WithAttemptRecorder(context.Background(), func(a domainp.RequestAttempt) { attempts = append(attempts, a) })
+	items, err := p.FetchContents(ctx)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("items=%d err=%v", len(items), err)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 recorded attempts, got %+v", attempts)
+	}
+	if attempts[0].StatusCode != 503 || attempts[0].Backoff <= 0 || attempts[1].StatusCode != 429 || attempts[2].Attempt != 3 || attempts[2].Backoff != 0 {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+}
+
+func TestDoWithRetry_PassesTheGateBeforeEveryAttempt(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	gated := 0
+	ctx := domainp.WithRequestGate(context.Background(), func(context.Context) error {
+		gated++
+		return nil
+	})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, http.NoBody)
+	resp, err := doWithRetry(ctx, srv.Client(), req, fastRetry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if gated != 2 || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("expected the gate passed for both attempts, gated=%d calls=%d", gated, calls)
+	}
+
+	refused := errors.New("no token")
+	ctx = domainp.WithRequestGate(context.Background(), func(context.Context) error { return refused })
+	if _, err := doWithRetry(ctx, srv.Client(), req, fastRetry()); !errors.Is(err, refused) || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("a refused request must not be sent, got %v after %d calls", err, calls)
+	}
+}
+
+func TestDoWithRetry_HonorsRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	// The computed backoff would exceed the budget; Retry-After replaces it
+	rp := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Minute, Budget: time.Second}
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+	resp, err := doWithRetry(context.Background(), srv.Client(), req, rp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("status=%d calls=%d", resp.StatusCode, calls)
+	}
+}
+
+func TestDoWithRetry_StopsAtBudget(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+	start := time.Now()
+	resp, err := doWithRetry(context.Background(), srv.Client(), req, fastRetry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || atomic.LoadInt32(&calls) != 1 || time.Since(start) > time.Second {
+		t.Fatalf("status=%d calls=%d elapsed=%s", resp.StatusCode, calls, time.Since(start))
+	}
+}
+
+func TestDoWithRetry_DoesNotRetryClientErrorsOrPosts(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		atomic.StoreInt32(&calls, 0)
+		req, _ := http.NewRequestWithContext(context.Background(), method, srv.URL, http.NoBody)
+		resp, err := doWithRetry(context.Background(), srv.Client(), req, fastRetry())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if atomic.LoadInt32(&calls) != 1 {
+			t.Fatalf("%s: expected a single call, got %d", method, calls)
+		}
+	}
+}
+
+func TestRetryPolicy_BackoffIsCappedAndJittered(t *testing.T) {
+	defer func(f func() float64) { jitter = f }(jitter)
+	rp := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	jitter = func() float64 { return 0 }
+	if d := rp.backoff(1); d != 50*time.Millisecond {
+		t.Fatalf("attempt 1 low = %s", d)
+	}
+	jitter = func() float64 { return 0.999999 }
+	if d := rp.backoff(3); d < 399*time.Millisecond || d > 400*time.Millisecond {
+		t.Fatalf("attempt 3 high = %s", d)
+	}
+	if d := rp.backoff(40); d > time.Second {
+		t.Fatalf("backoff not capped: %s", d)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"7", 7 * time.Second, true},
+		{"-1", 0, false},
+		{"Fri, 15 Mar 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Fri, 15 Mar 2024 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := retryAfter(c.in, now)
+		if got != c.want || ok != c.ok {
+			t.Errorf("retryAfter(%q) = %s, %v; want %s, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+}
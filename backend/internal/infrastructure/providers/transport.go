@@ -0,0 +1,404 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretEnvPrefix is the prefix of environment variables a SecretRef may read, so
+// provider configuration cannot point at the service's own settings.
+const SecretEnvPrefix = "PROVIDER_SECRET_"
+
+// SecretsDir is the directory secret files must live in; with none set, secrets can
+// only come from environment variables. It is set once at startup.
+var SecretsDir string
+
+// SecretRef names where a secret is read from: an environment variable named with
+// SecretEnvPrefix or a file under SecretsDir, e.g. a mounted Kubernetes secret.
+// Secret values are never part of provider configuration and are never logged.
+type SecretRef struct {
+	Env  string `json:"env,omitempty" yaml:"env,omitempty"`
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+}
+
+func (r SecretRef) IsZero() bool { return r.Env == "" && r.File == "" }
+
+// Value reads the secret. Files are read on every call so a rotated secret
+// applies without a restart.
+func (r SecretRef) Value() (string, error) {
+	switch {
+	case r.Env != "" && r.File != "":
+		return "", errors.New("secret: set either env or file, not both")
+	case r.Env != "":
+		if !strings.HasPrefix(r.Env, SecretEnvPrefix) || r.Env == SecretEnvPrefix {
+			return "", fmt.Errorf("secret: environment variable %s must start with %s", r.Env, SecretEnvPrefix)
+		}
+		v := os.Getenv(r.Env)
+		if v == "" {
+			return "", fmt.Errorf("secret: environment variable %s is not set", r.Env)
+		}
+		return v, nil
+	case r.File != "":
+		path, err := secretPath(r.File)
+		if err != nil {
+			return "", err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			// The path is configuration, not the secret, so it may be reported
+			return "", fmt.Errorf("secret: read %s: %w", r.File, errors.Unwrap(err))
+		}
+		v := strings.TrimSpace(string(b))
+		if v == "" {
+			return "", fmt.Errorf("secret: file %s is empty", r.File)
+		}
+		return v, nil
+	}
+	return "", errors.New("secret: env or file is required")
+}
+
+// secretPath resolves file against SecretsDir and refuses paths leading out of it.
+func secretPath(file string) (string, error) {
+	if SecretsDir == "" {
+		return "", errors.New("secret: files are not allowed without PROVIDER_SECRETS_DIR")
+	}
+	dir := filepath.Clean(SecretsDir)
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	path = filepath.Clean(path)
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("secret: file %s is outside %s", file, dir)
+	}
+	return path, nil
+}
+
+type AuthType string
+
+const (
+	AuthNone              AuthType = ""
+	AuthAPIKey            AuthType = "api_key"
+	AuthBearer            AuthType = "bearer"
+	AuthClientCredentials AuthType = "oauth2_client_credentials"
+)
+
+// AuthConfig authenticates provider requests.
+//
+//	api_key                    Secret in Header (default X-API-Key), or in QueryParam when set
+//	bearer                     Secret as "Authorization: Bearer <token>"
+//	oauth2_client_credentials  a token from TokenURL for ClientID/ClientSecret, refreshed before expiry
+type AuthConfig struct {
+	Type         AuthType  `json:"type,omitempty" yaml:"type,omitempty"`
+	Header       string    `json:"header,omitempty" yaml:"header,omitempty"`
+	QueryParam   string    `json:"query_param,omitempty" yaml:"query_param,omitempty"`
+	Secret       SecretRef `json:"secret,omitempty" yaml:"secret,omitempty"`
+	TokenURL     string    `json:"token_url,omitempty" yaml:"token_url,omitempty"`
+	ClientID     string    `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	ClientSecret SecretRef `json:"client_secret,omitempty" yaml:"client_secret,omitempty"`
+	Scopes       []string  `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// TransportConfig holds the per-provider HTTP settings. Headers are static and
+// must not carry secrets; use Auth for those. CABundle is a PEM file of CAs
+// trusted in addition to the system roots. Proxy is the outbound proxy URL; its
+// password, if any, comes from ProxyPassword.
+type TransportConfig struct {
+	Auth          AuthConfig        `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	CABundle      string            `json:"ca_bundle,omitempty" yaml:"ca_bundle,omitempty"`
+	Proxy         string            `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	ProxyPassword SecretRef         `json:"proxy_password,omitempty" yaml:"proxy_password,omitempty"`
+}
+
+// ParseTransportConfig decodes a stored transport configuration; unknown fields
+// are rejected so a misspelt setting is not silently ignored.
+func ParseTransportConfig(raw []byte) (TransportConfig, error) {
+	var cfg TransportConfig
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("transport: %w", err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the configuration and that every referenced secret resolves.
+func (c TransportConfig) Validate() error {
+	a := c.Auth
+	switch a.Type {
+	case AuthNone:
+	case AuthAPIKey:
+		if a.Header != "" && a.QueryParam != "" {
+			return errors.New("transport: api_key takes either header or query_param")
+		}
+		if _, err := a.Secret.Value(); err != nil {
+			return fmt.Errorf("transport: api_key %w", err)
+		}
+	case AuthBearer:
+		if _, err := a.Secret.Value(); err != nil {
+			return fmt.Errorf("transport: bearer %w", err)
+		}
+	case AuthClientCredentials:
+		if err := checkHTTPURL(a.TokenURL); err != nil {
+			return fmt.Errorf("transport: token_url %w", err)
+		}
+		if a.ClientID == "" {
+			return errors.New("transport: client_id is required for oauth2_client_credentials")
+		}
+		if _, err := a.ClientSecret.Value(); err != nil {
+			return fmt.Errorf("transport: client_secret %w", err)
+		}
+	default:
+		return fmt.Errorf("transport: unknown auth type %q", a.Type)
+	}
+	for k := range c.Headers {
+		if strings.EqualFold(k, "Authorization") {
+			return errors.New("transport: set Authorization through auth, not headers")
+		}
+	}
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			return errors.New("transport: proxy must be an http, https or socks5 URL")
+		}
+		if _, ok := u.User.Password(); ok {
+			return errors.New("transport: proxy password must come from proxy_password")
+		}
+	}
+	if !c.ProxyPassword.IsZero() {
+		if c.Proxy == "" {
+			return errors.New("transport: proxy_password requires proxy")
+		}
+		if _, err := c.ProxyPassword.Value(); err != nil {
+			return fmt.Errorf("transport: proxy_password %w", err)
+		}
+	}
+	if c.CABundle != "" {
+		if _, err := loadCABundle(c.CABundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("transport: ca_bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("transport: ca_bundle %s has no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// NewHTTPClient returns a provider client with the shared dial and connection
+// pool settings plus the TLS, proxy and authentication of cfg.
+func NewHTTPClient(timeout time.Duration, cfg TransportConfig) (*http.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	base := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+	}
+	if cfg.CABundle != "" {
+		pool, err := loadCABundle(cfg.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		base.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	if cfg.Proxy != "" {
+		proxy, _ := url.Parse(cfg.Proxy)
+		password := cfg.ProxyPassword
+		base.Proxy = func(*http.Request) (*url.URL, error) {
+			u := *proxy
+			if !password.IsZero() {
+				v, err := password.Value()
+				if err != nil {
+					return nil, err
+				}
+				u.User = url.UserPassword(proxy.User.Username(), v)
+			}
+			return &u, nil
+		}
+	}
+	if cfg.Auth.Type == AuthNone && len(cfg.Headers) == 0 {
+		return &http.Client{Timeout: timeout, Transport: base}, nil
+	}
+	t := &authTransport{base: base, auth: cfg.Auth, headers: cfg.Headers}
+	if cfg.Auth.Type == AuthClientCredentials {
+		// Token requests share TLS and proxy settings but not the provider auth
+		t.tokens = &clientCredentials{auth: cfg.Auth, client: &http.Client{Timeout: timeout, Transport: base}, now: time.Now}
+	}
+	return &http.Client{Timeout: timeout, Transport: t}, nil
+}
+
+// authTransport adds the static headers and credentials to a copy of every request,
+// so secrets never reach the request URL seen by retries, attempts and errors.
+// Credentials only go to the host the caller addressed; a redirect elsewhere is
+// followed without them.
+type authTransport struct {
+	base    http.RoundTripper
+	auth    AuthConfig
+	headers map[string]string
+	tokens  *clientCredentials
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	for k, v := range t.headers {
+		r.Header.Set(k, v)
+	}
+	if r.URL.Host != originalRequest(req).URL.Host {
+		return t.base.RoundTrip(r)
+	}
+	switch t.auth.Type {
+	case AuthAPIKey:
+		key, err := t.auth.Secret.Value()
+		if err != nil {
+			return nil, err
+		}
+		if t.auth.QueryParam != "" {
+			q := r.URL.Query()
+			q.Set(t.auth.QueryParam, key)
+			r.URL.RawQuery = q.Encode()
+		} else {
+			header := t.auth.Header
+			if header == "" {
+				header = "X-API-Key"
+			}
+			r.Header.Set(header, key)
+		}
+	case AuthBearer:
+		token, err := t.auth.Secret.Value()
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Authorization", "Bearer "+token)
+	case AuthClientCredentials:
+		token, err := t.tokens.Token(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Authorization", "Bearer "+token)
+		resp, err := t.base.RoundTrip(r)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized {
+			// A revoked or rotated token is fetched again on the next request
+			t.tokens.Invalidate()
+		}
+		return resp, err
+	}
+	return t.base.RoundTrip(r)
+}
+
+// originalRequest follows a redirected request back to the one the caller made.
+func originalRequest(req *http.Request) *http.Request {
+	for req.Response != nil && req.Response.Request != nil {
+		req = req.Response.Request
+	}
+	return req
+}
+
+// tokenRefreshMargin renews a token this long before it expires
+const tokenRefreshMargin = 30 * time.Second
+
+// clientCredentials fetches and caches OAuth2 client-credentials tokens.
+type clientCredentials struct {
+	auth   AuthConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func (c *clientCredentials) Invalidate() {
+	c.mu.Lock()
+	c.token = ""
+	c.mu.Unlock()
+}
+
+// Token returns the cached token or requests a new one. Requests are serialized so
+// concurrent callers share one refresh.
+func (c *clientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && c.now().Before(c.expiry) {
+		return c.token, nil
+	}
+	secret, err := c.auth.ClientSecret.Value()
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.auth.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.auth.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.auth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// RFC 6749 section 2.3.1: credentials are form-encoded inside Basic auth
+	req.SetBasicAuth(url.QueryEscape(c.auth.ClientID), url.QueryEscape(secret))
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oauth2 token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oauth2 token: status %d from token endpoint", resp.StatusCode)
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("oauth2 token: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", errors.New("oauth2 token: response has no access_token")
+	}
+	if body.TokenType != "" && !strings.EqualFold(body.TokenType, "bearer") {
+		return "", fmt.Errorf("oauth2 token: unsupported token_type %q", body.TokenType)
+	}
+	ttl := time.Duration(body.ExpiresIn) * time.Second
+	if ttl <= 0 {
+		// No lifetime given; refresh hourly and rely on 401 invalidation
+		ttl = time.Hour
+	}
+	margin := tokenRefreshMargin
+	if ttl/10 < margin {
+		margin = ttl / 10
+	}
+	c.token, c.expiry = body.AccessToken, c.now().Add(ttl-margin)
+	return c.token, nil
+}
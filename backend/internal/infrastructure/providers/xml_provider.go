@@ -3,7 +3,9 @@ package providers
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -16,8 +18,19 @@ type XMLProvider struct {
 	Client   *http.Client
 	BaseURL  string
 	Provider string
-	Page     int
-	Size     int
+	// Page is the first page of the crawl and Size the page size
+	Page int
+	Size int
+	// MaxPages caps the number of pages per fetch (0 = unlimited)
+	MaxPages int
+	// PageDelay is the minimum pause between page requests; the rate limit may raise it
+	PageDelay time.Duration
+	// Retry applies to every page request
+	Retry RetryPolicy
+	// RequestsPerMinute is the limit applied by ProviderService
+	RequestsPerMinute int
+	// MaxPayloadBytes caps each page response (0 = unlimited)
+	MaxPayloadBytes int64
 }
 
 func NewXMLProvider(baseURL string, timeout time.Duration) *XMLProvider {
@@ -32,24 +45,32 @@ func NewXMLProvider(baseURL string, timeout time.Duration) *XMLProvider {
 			MaxIdleConnsPerHost: 10,
 		},
 	}
-	return &XMLProvider{Client: client, BaseURL: baseURL, Provider: "provider2", Page: 1, Size: 40}
+	return &XMLProvider{Client: client, BaseURL: baseURL, Provider: "provider2", Page: 1, Size: defaultPageSize, MaxPages: defaultMaxPages, Retry: DefaultRetryPolicy(), RequestsPerMinute: 80, MaxPayloadBytes: DefaultMaxPayloadBytes}
 }
 
 func (p *XMLProvider) GetProviderID() string { return p.Provider }
 func (p *XMLProvider) GetRateLimit() domainp.RateLimit {
-	return domainp.RateLimit{RequestsPerMinute: 80}
+	return domainp.RateLimit{RequestsPerMinute: p.RequestsPerMinute}
 }
 
 type xmlFeed struct {
 	XMLName xml.Name  `xml:"feed"`
 	Items   []xmlItem `xml:"items>item"`
+	Meta    xmlMeta   `xml:"meta"`
+}
+type xmlMeta struct {
+	TotalCount   int `xml:"total_count"`
+	CurrentPage  int `xml:"current_page"`
+	ItemsPerPage int `xml:"items_per_page"`
 }
 type xmlItem struct {
-	ID       string   `xml:"id"`
-	Headline string   `xml:"headline"`
-	Type     string   `xml:"type"`
-	Stats    xmlStats `xml:"stats"`
-	PubDate  string   `xml:"publication_date"`
+	XMLName    xml.Name `xml:"item"`
+	ID         string   `xml:"id"`
+	Headline   string   `xml:"headline"`
+	Type       string   `xml:"type"`
+	Stats      xmlStats `xml:"stats"`
+	PubDate    string   `xml:"publication_date"`
+	Categories []string `xml:"categories>category"`
 }
 type xmlStats struct {
 	Views       *int64 `xml:"views"`
@@ -60,62 +81,180 @@ type xmlStats struct {
 	Comments    *int   `xml:"comments"`
 }
 
-func (p *XMLProvider) FetchContents() ([]domainp.ProviderContent, error) {
+// FetchContents collects every item of StreamContents.
+func (p *XMLProvider) FetchContents(ctx context.Context) ([]domainp.ProviderContent, error) {
+	var out []domainp.ProviderContent
+	err := p.StreamContents(ctx, func(pc domainp.ProviderContent) error {
+		out = append(out, pc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StreamContents walks pages until an empty page is returned, the reported
+// total_count is reached, a page yields no unseen items, or MaxPages is hit.
+// Items are decoded from the response one at a time and passed to yield.
+func (p *XMLProvider) StreamContents(ctx context.Context, yield func(domainp.ProviderContent) error) error {
+	size := p.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	page := p.Page
+	if page <= 0 {
+		page = 1
+	}
+	delay := pageDelay(p.PageDelay, p.GetRateLimit())
+	seen := make(map[string]struct{})
+	fetched := 0
+	for n := 0; p.MaxPages <= 0 || n < p.MaxPages; n++ {
+		if n > 0 {
+			if err := waitPage(ctx, delay); err != nil {
+				return err
+			}
+		}
+		count, fresh := 0, 0
+		meta, err := p.streamPage(ctx, page, size, func(raw []byte) error {
+			count++
+			var it xmlItem
+			if err := xml.Unmarshal(raw, &it); err != nil {
+				fresh++
+				return yield(undecodable(p.Provider, xmlItemID(raw), raw, err))
+			}
+			if _, dup := seen[it.ID]; dup {
+				return nil
+			}
+			seen[it.ID] = struct{}{}
+			fresh++
+			return yield(p.mapItem(it, raw))
+		})
+		if err != nil {
+			return fmt.Errorf("provider2 page %d: %w", page, err)
+		}
+		fetched += count
+		if count == 0 || fresh == 0 {
+			return nil
+		}
+		if meta.TotalCount > 0 && fetched >= meta.TotalCount {
+			return nil
+		}
+		page++
+	}
+	reportMaxPages(ctx, p.MaxPages)
+	return nil
+}
+
+// streamPage requests one page and walks its tokens, handing the source bytes of each
+// feed>items>item element to fn; feed>meta is decoded into the result.
+func (p *XMLProvider) streamPage(ctx context.Context, page, size int, fn func([]byte) error) (xmlMeta, error) {
+	var meta xmlMeta
 	u, _ := url.Parse(p.BaseURL + "/feed")
 	q := u.Query()
-	q.Set("page", fmt.Sprintf("%d", p.Page))
-	q.Set("size", fmt.Sprintf("%d", p.Size))
+	q.Set("page", fmt.Sprintf("%d", page))
+	q.Set("size", fmt.Sprintf("%d", size))
 	u.RawQuery = q.Encode()
 
-	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, u.String(), http.NoBody)
-	resp, err := p.Client.Do(req)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	resp, err := doWithRetry(ctx, p.Client, req, p.Retry)
 	if err != nil {
-		return nil, err
+		return meta, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status %d from provider2", resp.StatusCode)
+		return meta, fmt.Errorf("status %d from provider2", resp.StatusCode)
 	}
-	var feed xmlFeed
-	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
-		return nil, err
+	body, err := limitBody(resp, p.MaxPayloadBytes)
+	if err != nil {
+		return meta, err
 	}
-	out := make([]domainp.ProviderContent, 0, len(feed.Items))
-	for _, it := range feed.Items {
-		pc := domainp.ProviderContent{
-			ProviderID:        p.Provider,
-			ProviderContentID: it.ID,
-			Title:             it.Headline,
-			Description:       "", // No description in this provider format
+	rec := &offsetRecorder{r: body}
+	dec := xml.NewDecoder(rec)
+	// path holds the open elements above the decoder position
+	var path []string
+	root := false
+	for {
+		start := dec.InputOffset()
+		rec.discard(start)
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
 		}
-		// parse time - try multiple formats
-		var ts time.Time
-		if t, err := time.Parse(time.RFC3339, it.PubDate); err == nil {
-			ts = t
-		} else if t, err := time.Parse("2006-01-02", it.PubDate); err == nil {
-			ts = t
-		} else {
-			ts = time.Now().UTC()
+		if err != nil {
+			return meta, err
 		}
-		pc.PublishedAt = ts
-
-		switch it.Type {
-		case "video":
-			pc.ContentType = "video"
-			pc.URL = fmt.Sprintf("https://example.com/video/%s", it.ID)
-			pc.ThumbnailURL = fmt.Sprintf("https://example.com/thumb/%s.jpg", it.ID)
-			pc.Views = it.Stats.Views
-			pc.Likes = it.Stats.Likes
-		case "article":
-			pc.ContentType = "text"
-			pc.URL = fmt.Sprintf("https://example.com/article/%s", it.ID)
-			pc.ReadingTime = it.Stats.ReadingTime
-			pc.Reactions = it.Stats.Reactions
-		default:
-			pc.ContentType = "text"
-			pc.URL = fmt.Sprintf("https://example.com/content/%s", it.ID)
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			switch {
+			case len(path) == 0 && name != "feed":
+				return meta, fmt.Errorf("expected element <feed>, got <%s>", name)
+			case len(path) == 0:
+				root = true
+			case len(path) == 2 && path[1] == "items" && name == "item":
+				if err := dec.Skip(); err != nil {
+					return meta, err
+				}
+				if err := fn(rec.slice(start, dec.InputOffset())); err != nil {
+					return meta, err
+				}
+				continue
+			case len(path) == 1 && name == "meta":
+				if err := dec.DecodeElement(&meta, &t); err != nil {
+					return meta, err
+				}
+				continue
+			}
+			path = append(path, name)
+		case xml.EndElement:
+			path = path[:len(path)-1]
 		}
-		out = append(out, pc)
 	}
-	return out, nil
+	if !root || len(path) > 0 {
+		return meta, io.ErrUnexpectedEOF
+	}
+	return meta, nil
+}
+
+// mapItem maps a decoded element; raw is the element as received, kept for quarantine.
+func (p *XMLProvider) mapItem(it xmlItem, raw []byte) domainp.ProviderContent {
+	pc := domainp.ProviderContent{
+		ProviderID:        p.Provider,
+		ProviderContentID: it.ID,
+		Title:             it.Headline,
+		Description:       "", // No description in this provider format
+		Tags:              it.Categories,
+		Comments:          it.Stats.Comments,
+		Raw:               raw,
+	}
+	mapPublishedAt(&pc, "publication_date", it.PubDate)
+
+	switch it.Type {
+	case "video":
+		pc.ContentType = "video"
+		pc.URL = fmt.Sprintf("https://example.com/video/%s", it.ID)
+		pc.ThumbnailURL = fmt.Sprintf("https://example.com/thumb/%s.jpg", it.ID)
+		pc.Views = it.Stats.Views
+		pc.Likes = it.Stats.Likes
+		mapDuration(&pc, "stats.duration", it.Stats.Duration)
+	case "article":
+		pc.ContentType = "text"
+		pc.URL = fmt.Sprintf("https://example.com/article/%s", it.ID)
+		pc.ReadingTime = it.Stats.ReadingTime
+		pc.Reactions = it.Stats.Reactions
+	default:
+		// Left unmapped so validation quarantines the item
+		pc.ContentType = it.Type
+	}
+	return pc
+}
+
+// xmlItemID reads the <id> of an element that did not decode as a whole, if it can.
+func xmlItemID(raw []byte) string {
+	var v struct {
+		ID string `xml:"id"`
+	}
+	_ = xml.Unmarshal(raw, &v)
+	return v.ID
 }
@@ -1,10 +1,17 @@
 package providers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	domainp "search_engine/internal/domain/providers"
 )
 
 func TestXMLProvider_FetchContents(t *testing.T) {
@@ -21,6 +28,10 @@ func TestXMLProvider_FetchContents(t *testing.T) {
         <duration>25:15</duration>
       </stats>
       <publication_date>2024-03-15</publication_date>
+      <categories>
+        <category>devops</category>
+        <category>containers</category>
+      </categories>
     </item>
     <item>
       <id>a1</id>
@@ -47,7 +58,7 @@ func TestXMLProvider_FetchContents(t *testing.T) {
 	defer srv.Close()
 
 	p := NewXMLProvider(srv.URL, 5*time.Second)
-	items, err := p.FetchContents()
+	items, err := p.FetchContents(context.Background())
 	if err != nil {
 		t.Fatalf("FetchContents error: %v", err)
 	}
@@ -63,6 +74,15 @@ func TestXMLProvider_FetchContents(t *testing.T) {
 	if items[0].Title != "Introduction to Docker" {
 		t.Fatalf("unexpected title: %s", items[0].Title)
 	}
+	if len(items[0].Tags) != 2 || items[0].Tags[0] != "devops" {
+		t.Fatalf("expected categories as tags, got %v", items[0].Tags)
+	}
+	if items[0].DurationSeconds == nil || *items[0].DurationSeconds != 1515 {
+		t.Fatalf("expected duration 25:15 as 1515s, got %v", items[0].DurationSeconds)
+	}
+	if items[1].Comments == nil || *items[1].Comments != 25 {
+		t.Fatalf("expected 25 comments, got %v", items[1].Comments)
+	}
 }
 
 func TestXMLProvider_Timeout(t *testing.T) {
@@ -72,7 +92,151 @@ func TestXMLProvider_Timeout(t *testing.T) {
 	}))
 	defer srv.Close()
 	p := NewXMLProvider(srv.URL, 50*time.Millisecond)
-	if _, err := p.FetchContents(); err == nil {
+	if _, err := p.FetchContents(context.Background()); err == nil {
 		t.Fatalf("expected timeout")
 	}
 }
+
+func TestXMLProvider_PaginationStopsOnEmptyPage(t *testing.T) {
+	all := []string{"v1", "a1", "v2"}
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		var b strings.Builder
+		b.WriteString("<feed><items>")
+		for i := (page - 1) * size; i < page*size && i < len(all); i++ {
+			fmt.Fprintf(&b, "<item><id>%s</id><headline>H %s</headline><type>article</type><publication_date>2024-03-15</publication_date></item>", all[i], all[i])
+		}
+		b.WriteString("</items></feed>")
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(b.String()))
+	}))
+	defer srv.Close()
+
+	p := NewXMLProvider(srv.URL, 5*time.Second)
+	p.Size = 2
+	items, err := p.FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if len(items) != len(all) {
+		t.Fatalf("expected %d items got %d", len(all), len(items))
+	}
+	// two pages with items plus the empty page that ends the crawl
+	if calls != 3 {
+		t.Fatalf("expected 3 page requests, got %d", calls)
+	}
+}
+
+func TestXMLProvider_ReportsUnmappableValues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<feed><items>
+<item><id>v1</id><headline>Bad date</headline><type>video</type><stats><duration>1:75</duration></stats><publication_date>15/03/2024</publication_date></item>
+<item><id>p1</id><headline>Podcast</headline><type>podcast</type><publication_date>2024-03-15</publication_date></item>
+</items><meta><total_count>2</total_count></meta></feed>`))
+	}))
+	defer srv.Close()
+
+	items, err := NewXMLProvider(srv.URL, 5*time.Second).FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("FetchContents error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items got %d", len(items))
+	}
+	if !items[0].PublishedAt.IsZero() || len(items[0].Problems) != 2 {
+		t.Fatalf("bad date and duration should be reported instead of defaulted: %+v", items[0])
+	}
+	if !strings.Contains(string(items[0].Raw), "<publication_date>15/03/2024</publication_date>") {
+		t.Fatalf("raw item should be kept, got %s", items[0].Raw)
+	}
+	if items[1].ContentType != "podcast" {
+		t.Fatalf("unknown type should be passed through, got %q", items[1].ContentType)
+	}
+}
+
+func TestXMLProvider_QuarantinesUndecodableItems(t *testing.T) {
+	bad := `<item><id>v1</id><type>video</type><stats><views>many</views></stats></item>`
+	good := `<item>
+  <id>a1</id><headline>Kept &amp; sent</headline><type>article</type><publication_date>2024-03-15</publication_date>
+</item>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if r.URL.Query().Get("page") != "1" {
+			_, _ = w.Write([]byte(`<feed><items></items></feed>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<feed><items>` + bad + good + `</items></feed>`))
+	}))
+	defer srv.Close()
+
+	items, err := NewXMLProvider(srv.URL, 5*time.Second).FetchContents(context.Background())
+	if err != nil {
+		t.Fatalf("an undecodable element must not fail the page: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].ProviderContentID != "v1" || len(items[0].Problems) != 1 || string(items[0].Raw) != bad {
+		t.Fatalf("undecodable element should carry its ID, a problem and its bytes: %+v", items[0])
+	}
+	if items[1].Title != "Kept & sent" || string(items[1].Raw) != good {
+		t.Fatalf("decoded element should keep its original bytes, got %s", items[1].Raw)
+	}
+}
+
+func TestXMLProvider_StreamsItemsBeforeTheBodyEnds(t *testing.T) {
+	first := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "1" {
+			_, _ = w.Write([]byte(`<feed><items></items></feed>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><feed><meta><total_count>2</total_count></meta><items><item><id>v1</id><headline>A</headline><type>article</type></item>`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-first:
+		case <-time.After(2 * time.Second):
+			return
+		}
+		_, _ = w.Write([]byte(`<item><id>v2</id><headline>B</headline><type>article</type><stats><item>nested</item></stats></item></items></feed>`))
+	}))
+	defer srv.Close()
+
+	p := NewXMLProvider(srv.URL, 5*time.Second)
+	var ids []string
+	err := p.StreamContents(context.Background(), func(pc domainp.ProviderContent) error {
+		if len(ids) == 0 {
+			close(first)
+		}
+		ids = append(ids, pc.ProviderContentID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "v1" || ids[1] != "v2" {
+		t.Fatalf("unexpected items %v", ids)
+	}
+}
+
+func TestXMLProvider_RejectsTruncatedAndOversizedFeeds(t *testing.T) {
+	body := `<feed><items><item><id>v1</id><headline>A</headline><type>article</type></item>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	p := NewXMLProvider(srv.URL, 5*time.Second)
+	if _, err := p.FetchContents(context.Background()); err == nil {
+		t.Fatal("a feed without its closing elements should fail")
+	}
+	body = `<feed><items>` + strings.Repeat(`<item><id>v1</id><headline>A</headline><type>article</type></item>`, 20) + `</items></feed>`
+	p.MaxPayloadBytes = 256
+	if _, err := p.FetchContents(context.Background()); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("expected ErrPayloadTooLarge, got %v", err)
+	}
+}
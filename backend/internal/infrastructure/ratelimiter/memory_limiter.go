@@ -0,0 +1,76 @@
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryLimiter is the process-local counterpart of RedisLimiter, used while Redis
+// is unreachable. Its buckets and slots are per instance, so N replicas together
+// may send up to N times a provider's limit during an outage.
+type MemoryLimiter struct {
+	// Now is the bucket clock; tests replace it
+	Now func() time.Time
+
+	mu       sync.Mutex
+	buckets  map[string]*memoryBucket
+	inFlight map[string]int
+}
+
+type memoryBucket struct {
+	tokens float64
+	at     time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{Now: time.Now, buckets: make(map[string]*memoryBucket), inFlight: make(map[string]int)}
+}
+
+// TryAcquire has the semantics of RedisLimiter.TryAcquire; a slot is held until
+// released, there is no lease.
+func (m *MemoryLimiter) TryAcquire(providerID string, lim Limit) (Release, error) {
+	if lim.RequestsPerMinute <= 0 && lim.MaxInFlight <= 0 {
+		return noRelease, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lim.MaxInFlight > 0 && m.inFlight[providerID] >= lim.MaxInFlight {
+		return nil, &RateLimitedError{ProviderID: providerID, Reason: ReasonInFlight}
+	}
+	if lim.RequestsPerMinute > 0 {
+		burst := float64(lim.Burst)
+		if burst <= 0 {
+			burst = float64(lim.RequestsPerMinute)
+		}
+		perSecond := float64(lim.RequestsPerMinute) / 60
+		now := m.Now()
+		b, ok := m.buckets[providerID]
+		if !ok {
+			b = &memoryBucket{tokens: burst, at: now}
+			m.buckets[providerID] = b
+		}
+		if elapsed := now.Sub(b.at).Seconds(); elapsed > 0 {
+			b.tokens = min(burst, b.tokens+elapsed*perSecond)
+		}
+		b.at = now
+		if b.tokens < 1 {
+			wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+			return nil, &RateLimitedError{ProviderID: providerID, Reason: ReasonRate, RetryAfter: wait.Round(time.Millisecond)}
+		}
+		b.tokens--
+	}
+	if lim.MaxInFlight <= 0 {
+		return noRelease, nil
+	}
+	m.inFlight[providerID]++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if m.inFlight[providerID]--; m.inFlight[providerID] <= 0 {
+				delete(m.inFlight, providerID)
+			}
+		})
+	}, nil
+}
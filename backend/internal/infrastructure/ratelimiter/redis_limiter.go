@@ -2,57 +2,206 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+
+	"search_engine/internal/infrastructure/cache"
+)
+
+// ErrRateLimited is matched by errors.Is for every *RateLimitedError.
+var ErrRateLimited = errors.New("rate limited")
+
+const (
+	// ReasonRate means the provider's token bucket is empty
+	ReasonRate = "rate"
+	// ReasonInFlight means the provider already has MaxInFlight fetches running
+	ReasonInFlight = "in_flight"
 )
 
+// RateLimitedError is returned when a provider call is refused by its limit.
+type RateLimitedError struct {
+	ProviderID string
+	Reason     string
+	// RetryAfter is when a token or slot is expected to be free
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	what := "request rate"
+	if e.Reason == ReasonInFlight {
+		what = "concurrent fetches"
+	}
+	return fmt.Sprintf("rate limited: %s limit of %s reached, retry after %s", what, e.ProviderID, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *RateLimitedError) Is(target error) bool { return target == ErrRateLimited }
+
+// Limit is the budget of one provider. RequestsPerMinute refills a token bucket of
+// Burst tokens (0 = RequestsPerMinute); MaxInFlight caps calls running at the same
+// time across all instances. Zero values disable the respective limit.
+type Limit struct {
+	RequestsPerMinute int
+	Burst             int
+	MaxInFlight       int
+}
+
+// Release returns an in-flight slot; it is safe to call more than once.
+type Release func()
+
+// RedisLimiter keeps per-provider token buckets and in-flight leases in Redis so
+// all replicas share them; each acquisition is a single Lua script and therefore
+// atomic.
 type RedisLimiter struct {
 	Client  *redis.Client
 	Enabled bool
-	Window  time.Duration
+	// Lease bounds how long an unreleased in-flight slot is held, e.g. after a crash
+	Lease time.Duration
+	// PollInterval is how often Wait retries while every in-flight slot is taken
+	PollInterval time.Duration
+	// Now overrides the clock of the buckets and leases in tests; when nil the Redis
+	// server's clock is used, so instances with drifting clocks share one timeline
+	Now func() time.Time
+	// Fallback is optional; it limits calls while Health reports Redis down and
+	// when a Redis call fails
+	Fallback *MemoryLimiter
+	Health   *cache.RedisHealth
 }
 
 func NewRedisLimiter(client *redis.Client, enabled bool) *RedisLimiter {
 	return &RedisLimiter{
-		Client:  client,
-		Enabled: enabled,
-		Window:  time.Minute,
+		Client:       client,
+		Enabled:      enabled,
+		Lease:        5 * time.Minute,
+		PollInterval: 250 * time.Millisecond,
 	}
 }
 
-func (r *RedisLimiter) key(providerID string) string {
-	return fmt.Sprintf("rl:%s:%d", providerID, time.Now().UTC().Unix()/int64(r.Window.Seconds()))
-}
+func (r *RedisLimiter) bucketKey(providerID string) string   { return "rl:" + providerID }
+func (r *RedisLimiter) inFlightKey(providerID string) string { return "rl:" + providerID + ":inflight" }
+
+// acquireScript takes one token from the bucket and, when in-flight calls are
+// capped, leases a slot; it takes neither unless both are available. It returns
+// {1} or {0, reason, retry_after_ms}. Times are Redis server milliseconds unless
+// ARGV[1] overrides them.
+var acquireScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+if now <= 0 then
+  local t = redis.call('TIME')
+  now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+end
+local rate = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+local maxfl = tonumber(ARGV[4])
+local lease = tonumber(ARGV[5])
+if maxfl > 0 then
+  redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', now)
+  if redis.call('ZCARD', KEYS[2]) >= maxfl then
+    local first = redis.call('ZRANGE', KEYS[2], 0, 0, 'WITHSCORES')
+    return {0, 'in_flight', tonumber(first[2]) - now}
+  end
+end
+if rate > 0 then
+  local tokens = burst
+  local h = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+  if h[1] and h[2] then
+    tokens = math.min(burst, tonumber(h[1]) + math.max(0, now - tonumber(h[2])) * rate)
+  end
+  if tokens < 1 then
+    return {0, 'rate', math.ceil((1 - tokens) / rate)}
+  end
+  redis.call('HSET', KEYS[1], 'tokens', tostring(tokens - 1), 'ts', now)
+  redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + 1000)
+end
+if maxfl > 0 then
+  redis.call('ZADD', KEYS[2], now + lease, ARGV[6])
+  redis.call('PEXPIRE', KEYS[2], lease + 1000)
+end
+return {1}
+`)
+
+func noRelease() {}
 
-// CheckLimit ensures current count < limit
-func (r *RedisLimiter) CheckLimit(ctx context.Context, providerID string, limit int) (bool, error) {
-	if !r.Enabled {
-		return true, nil
+// TryAcquire takes a token and an in-flight slot for providerID, or fails at once
+// with a *RateLimitedError. The returned Release must be called when the call ends.
+func (r *RedisLimiter) TryAcquire(ctx context.Context, providerID string, lim Limit) (Release, error) {
+	if r == nil || !r.Enabled || (lim.RequestsPerMinute <= 0 && lim.MaxInFlight <= 0) {
+		return noRelease, nil
 	}
-	k := r.key(providerID)
-	cntStr, err := r.Client.Get(ctx, k).Result()
-	if err == redis.Nil {
-		return true, nil
+	if r.Fallback != nil && !r.Health.Up() {
+		return r.Fallback.TryAcquire(providerID, lim)
 	}
+	burst := lim.Burst
+	if burst <= 0 {
+		burst = lim.RequestsPerMinute
+	}
+	perMs := float64(lim.RequestsPerMinute) / float64(time.Minute/time.Millisecond)
+	member := uuid.NewString()
+	var now int64
+	if r.Now != nil {
+		now = r.Now().UnixMilli()
+	}
+	res, err := acquireScript.Run(ctx, r.Client, []string{r.bucketKey(providerID), r.inFlightKey(providerID)},
+		now, perMs, burst, lim.MaxInFlight, r.Lease.Milliseconds(), member).Slice()
 	if err != nil {
-		return false, err
+		if r.Fallback != nil && ctx.Err() == nil {
+			return r.Fallback.TryAcquire(providerID, lim)
+		}
+		return nil, err
+	}
+	if len(res) == 3 && res[0] == int64(0) {
+		reason, _ := res[1].(string)
+		wait, _ := res[2].(int64)
+		return nil, &RateLimitedError{ProviderID: providerID, Reason: reason, RetryAfter: time.Duration(wait) * time.Millisecond}
 	}
-	var cnt int64
-	_, _ = fmt.Sscanf(cntStr, "%d", &cnt)
-	return cnt < int64(limit), nil
+	if lim.MaxInFlight <= 0 {
+		return noRelease, nil
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			// The caller's context may already be done; the slot is returned regardless
+			rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+			defer cancel()
+			_ = r.Client.ZRem(rctx, r.inFlightKey(providerID), member).Err()
+		})
+	}, nil
 }
 
-// RecordRequest increments count with TTL window
-func (r *RedisLimiter) RecordRequest(ctx context.Context, providerID string) error {
-	if !r.Enabled {
-		return nil
-	}
-	k := r.key(providerID)
-	pipe := r.Client.TxPipeline()
-	pipe.Incr(ctx, k)
-	pipe.Expire(ctx, k, r.Window+5*time.Second)
-	_, err := pipe.Exec(ctx)
-	return err
+// Wait is TryAcquire that waits for a token or slot while ctx allows. When the
+// expected wait ends after ctx's deadline it gives up at once with the
+// *RateLimitedError instead of sleeping until the deadline.
+func (r *RedisLimiter) Wait(ctx context.Context, providerID string, lim Limit) (Release, error) {
+	for {
+		release, err := r.TryAcquire(ctx, providerID, lim)
+		var rle *RateLimitedError
+		if !errors.As(err, &rle) {
+			return release, err
+		}
+		wait := rle.RetryAfter
+		if rle.Reason == ReasonInFlight && (wait <= 0 || wait > r.PollInterval) {
+			// Slots are usually released long before their lease ends
+			wait = r.PollInterval
+		}
+		if wait <= 0 {
+			wait = time.Millisecond
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return nil, rle
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, rle
+			}
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
 }
@@ -2,51 +2,248 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	miniredis "github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
+
+	"search_engine/internal/infrastructure/cache"
 )
 
-func TestRedisLimiter_WithinLimit(t *testing.T) {
-	mr, _ := miniredis.Run()
-	defer mr.Close()
-	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
-	rl := NewRedisLimiter(rc, true)
+func newTestLimiter(t *testing.T) (*RedisLimiter, *time.Time) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mr.Close)
+	rl := NewRedisLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}), true)
+	now := time.Unix(1_700_000_000, 0)
+	rl.Now = func() time.Time { return now }
+	return rl, &now
+}
+
+func TestRedisLimiter_TokenBucket(t *testing.T) {
+	rl, now := newTestLimiter(t)
 	ctx := context.Background()
-	ok, err := rl.CheckLimit(ctx, "p1", 3)
-	if err != nil || !ok {
-		t.Fatalf("expected ok, err=%v", err)
+	lim := Limit{RequestsPerMinute: 60, Burst: 2}
+	for i := 0; i < 2; i++ {
+		if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+	}
+	_, err := rl.TryAcquire(ctx, "p1", lim)
+	var rle *RateLimitedError
+	if !errors.As(err, &rle) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected rate limited, got %v", err)
 	}
-	_ = rl.RecordRequest(ctx, "p1")
-	_ = rl.RecordRequest(ctx, "p1")
-	ok, _ = rl.CheckLimit(ctx, "p1", 3)
-	if !ok {
-		t.Fatalf("should be within limit")
+	if rle.Reason != ReasonRate || rle.RetryAfter != time.Second {
+		t.Fatalf("reason %q retry after %s", rle.Reason, rle.RetryAfter)
+	}
+	*now = now.Add(time.Second)
+	if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+		t.Fatalf("token should have refilled: %v", err)
+	}
+	if _, err := rl.TryAcquire(ctx, "p2", lim); err != nil {
+		t.Fatalf("each provider has its own bucket: %v", err)
+	}
+}
+
+func TestRedisLimiter_Atomic(t *testing.T) {
+	rl, _ := newTestLimiter(t)
+	lim := Limit{RequestsPerMinute: 5}
+	var granted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := rl.TryAcquire(context.Background(), "p1", lim); err == nil {
+				granted.Add(1)
+			} else if !errors.Is(err, ErrRateLimited) {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if granted.Load() != 5 {
+		t.Fatalf("granted %d, want 5", granted.Load())
 	}
 }
 
-func TestRedisLimiter_ExceedLimit(t *testing.T) {
-	mr, _ := miniredis.Run()
+func TestRedisLimiter_InFlight(t *testing.T) {
+	rl, now := newTestLimiter(t)
+	ctx := context.Background()
+	lim := Limit{MaxInFlight: 1}
+	release, err := rl.TryAcquire(ctx, "p1", lim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rl.TryAcquire(ctx, "p1", lim)
+	var rle *RateLimitedError
+	if !errors.As(err, &rle) || rle.Reason != ReasonInFlight {
+		t.Fatalf("expected in-flight limit, got %v", err)
+	}
+	release()
+	release()
+	if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+		t.Fatalf("slot should be free after release: %v", err)
+	}
+	// The slot just taken is never released; its lease ends it
+	*now = now.Add(rl.Lease + time.Second)
+	if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+		t.Fatalf("slot should be free after its lease: %v", err)
+	}
+}
+
+func TestRedisLimiter_InFlightKeepsToken(t *testing.T) {
+	rl, _ := newTestLimiter(t)
+	ctx := context.Background()
+	lim := Limit{RequestsPerMinute: 60, Burst: 2, MaxInFlight: 1}
+	release, err := rl.TryAcquire(ctx, "p1", lim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rl.TryAcquire(ctx, "p1", lim); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected in-flight limit, got %v", err)
+	}
+	release()
+	// The refused call must not have spent the second token
+	if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+		t.Fatalf("second token should remain: %v", err)
+	}
+}
+
+func TestRedisLimiter_Wait(t *testing.T) {
+	rl, _ := newTestLimiter(t)
+	rl.Now = nil
+	lim := Limit{RequestsPerMinute: 600, Burst: 1}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := rl.Wait(ctx, "p1", lim); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := rl.Wait(ctx, "p1", lim); err != nil {
+		t.Fatalf("wait should get the next token: %v", err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Fatalf("returned after %s, expected to wait for the refill", d)
+	}
+}
+
+func TestRedisLimiter_UsesRedisServerClock(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
 	defer mr.Close()
-	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
-	rl := NewRedisLimiter(rc, true)
+	rl := NewRedisLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}), true)
 	ctx := context.Background()
-	for i := 0; i < 5; i++ {
-		_ = rl.RecordRequest(ctx, "p2")
+	lim := Limit{RequestsPerMinute: 60, Burst: 1}
+	server := time.Unix(1_700_000_000, 0)
+	mr.SetTime(server)
+	if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rl.TryAcquire(ctx, "p1", lim); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected the bucket empty, got %v", err)
+	}
+	// Only the server's clock refills the bucket, whatever the instance's clock says
+	mr.SetTime(server.Add(time.Second))
+	if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+		t.Fatalf("expected a token after a second of server time: %v", err)
+	}
+}
+
+func TestRedisLimiter_ReleaseIsIdempotent(t *testing.T) {
+	rl, _ := newTestLimiter(t)
+	ctx := context.Background()
+	lim := Limit{MaxInFlight: 1}
+	release, err := rl.TryAcquire(ctx, "p1", lim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release()
+		}()
+	}
+	wg.Wait()
+	if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+		t.Fatalf("slot should be free after release: %v", err)
+	}
+}
+
+func TestRedisLimiter_WaitFailsFastPastDeadline(t *testing.T) {
+	rl, _ := newTestLimiter(t)
+	lim := Limit{RequestsPerMinute: 1}
+	if _, err := rl.TryAcquire(context.Background(), "p1", lim); err != nil {
+		t.Fatal(err)
 	}
-	ok, _ := rl.CheckLimit(ctx, "p2", 3)
-	if ok {
-		t.Fatalf("expected over limit")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := rl.Wait(ctx, "p1", lim)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected rate limited, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("should not sleep until the deadline")
 	}
 }
 
 func TestRedisLimiter_Disabled(t *testing.T) {
 	rc := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}) // unreachable
 	rl := NewRedisLimiter(rc, false)
-	ctx := context.Background()
-	ok, err := rl.CheckLimit(ctx, "p3", 1)
-	if err != nil || !ok {
+	release, err := rl.TryAcquire(context.Background(), "p3", Limit{RequestsPerMinute: 1, MaxInFlight: 1})
+	if err != nil {
 		t.Fatalf("disabled limiter should pass, err=%v", err)
 	}
+	release()
+}
+
+func TestRedisLimiter_FallbackWhileRedisIsDown(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	health := cache.NewRedisHealth(rc, nil)
+	health.FailureThreshold = 1
+	rl := NewRedisLimiter(rc, true)
+	rl.Health = health
+	rl.Fallback = NewMemoryLimiter()
+	ctx := context.Background()
+	lim := Limit{RequestsPerMinute: 60, Burst: 1, MaxInFlight: 1}
+
+	mr.SetError("LOADING")
+	health.Probe(ctx)
+	release, err := rl.TryAcquire(ctx, "p1", lim)
+	if err != nil {
+		t.Fatalf("fallback should limit, not fail: %v", err)
+	}
+	_, err = rl.TryAcquire(ctx, "p1", lim)
+	var rle *RateLimitedError
+	if !errors.As(err, &rle) || rle.Reason != ReasonInFlight {
+		t.Fatalf("expected in-flight limit from the fallback, got %v", err)
+	}
+	release()
+	if _, err := rl.TryAcquire(ctx, "p1", lim); !errors.As(err, &rle) || rle.Reason != ReasonRate {
+		t.Fatalf("expected empty fallback bucket, got %v", err)
+	}
+
+	mr.SetError("")
+	health.Probe(ctx)
+	if _, err := rl.TryAcquire(ctx, "p1", lim); err != nil {
+		t.Fatalf("redis bucket should be used again: %v", err)
+	}
 }
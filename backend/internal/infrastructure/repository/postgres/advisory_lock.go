@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdvisoryLock is a session-level Postgres advisory lock named after a job. It is
+// taken on a connection of its own, outside the pool, and held until that
+// connection is closed; Postgres then drops the lock with the session, so a
+// crashed holder never keeps it.
+type AdvisoryLock struct {
+	connConfig *pgx.ConnConfig
+	name       string
+	// CheckEvery is how often the connection holding the lock is pinged; when a ping
+	// fails the session, and with it the lock, may be gone, so the run is cancelled
+	CheckEvery time.Duration
+}
+
+func NewAdvisoryLock(pool *pgxpool.Pool, name string) *AdvisoryLock {
+	return &AdvisoryLock{connConfig: pool.Config().ConnConfig, name: name, CheckEvery: 5 * time.Second}
+}
+
+// TryLock takes the lock without waiting. When it is taken, the returned context is
+// derived from parent and cancelled if the lock's connection is lost; release must
+// be called when the run ends. ok is false when another session holds the lock.
+func (l *AdvisoryLock) TryLock(parent context.Context) (ctx context.Context, release context.CancelFunc, ok bool, err error) {
+	conn, err := pgx.ConnectConfig(parent, l.connConfig.Copy())
+	if err != nil {
+		return parent, func() {}, false, err
+	}
+	closeConn := func() {
+		cctx, cancel := context.WithTimeout(context.WithoutCancel(parent), 5*time.Second)
+		defer cancel()
+		_ = conn.Close(cctx)
+	}
+	if err := conn.QueryRow(parent, `SELECT pg_try_advisory_lock(hashtext($1))`, "job:"+l.name).Scan(&ok); err != nil || !ok {
+		closeConn()
+		return parent, func() {}, false, err
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t := time.NewTicker(l.CheckEvery)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				// Not derived from ctx: a ping cancelled midway would break the connection
+				pctx, pcancel := context.WithTimeout(context.Background(), l.CheckEvery)
+				err := conn.Ping(pctx)
+				pcancel()
+				if err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			cancel()
+			<-done
+			closeConn()
+		})
+	}, true, nil
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAdvisoryLock_OneHolderAtATime(t *testing.T) {
+	pool := getTestPool(t)
+	defer pool.Close()
+	ctx := context.Background()
+	a, b := NewAdvisoryLock(pool, "test-job"), NewAdvisoryLock(pool, "test-job")
+
+	runCtx, release, ok, err := a.TryLock(ctx)
+	if err != nil || !ok {
+		t.Fatalf("expected the lock, ok=%v err=%v", ok, err)
+	}
+	if _, _, ok, err := b.TryLock(ctx); err != nil || ok {
+		t.Fatalf("a second session must not take a held lock, ok=%v err=%v", ok, err)
+	}
+	release()
+	if runCtx.Err() == nil {
+		t.Fatal("expected the run context cancelled on release")
+	}
+	_, releaseB, ok, err := b.TryLock(ctx)
+	if err != nil || !ok {
+		t.Fatalf("expected the lock after release, ok=%v err=%v", ok, err)
+	}
+	releaseB()
+}
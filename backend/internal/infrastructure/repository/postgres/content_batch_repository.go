@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/repositories"
+)
+
+type contentBatchRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewContentBatchRepository(pool *pgxpool.Pool) repositories.ContentBatchRepository {
+	return &contentBatchRepository{pool: pool}
+}
+
+func (r *contentBatchRepository) GetByProviderKeys(ctx context.Context, providerID string, providerContentIDs []string) ([]repositories.ContentWithMetrics, error) {
+	if len(providerContentIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			c.id, c.provider_id, c.provider_content_id, c.title, c.content_type, c.description, c.url, c.thumbnail_url, c.published_at, c.language, c.created_at, c.updated_at,
+			COALESCE(cm.id, 0), COALESCE(cm.content_id, 0), COALESCE(cm.views, 0), COALESCE(cm.likes, 0), COALESCE(cm.reading_time, 0), COALESCE(cm.reactions, 0),
+			COALESCE(cm.duration_seconds, 0), COALESCE(cm.comments, 0), COALESCE(cm.final_score, 0), cm.recalculated_at
+		FROM contents c
+		LEFT JOIN content_metrics cm ON cm.content_id = c.id
+		WHERE c.provider_id = $1 AND c.provider_content_id = ANY($2)
+	`, providerID, providerContentIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make([]repositories.ContentWithMetrics, 0, len(providerContentIDs))
+	for rows.Next() {
+		var cw repositories.ContentWithMetrics
+		c, m := &cw.Content, &cw.Metrics
+		if err := rows.Scan(
+			&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.Language, &c.CreatedAt, &c.UpdatedAt,
+			&m.ID, &m.ContentID, &m.Views, &m.Likes, &m.ReadingTime, &m.Reactions, &m.DurationSeconds, &m.Comments, &m.FinalScore, &m.RecalculatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, cw)
+	}
+	return out, rows.Err()
+}
+
+func (r *contentBatchRepository) UpsertBatch(ctx context.Context, contents []entities.Content) error {
+	if len(contents) == 0 {
+		return nil
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if err := upsertContents(ctx, tx, contents); err != nil {
+		return err
+	}
+	if err := upsertMetrics(ctx, tx, contents); err != nil {
+		return err
+	}
+	if err := replaceTags(ctx, tx, contents); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// upsertContents copies contents into a staging table and merges it into contents.
+// Unchanged rows are not rewritten, so their updated_at and search vector stay put.
+func upsertContents(ctx context.Context, tx pgx.Tx, contents []entities.Content) error {
+	// content_type is staged as text: COPY cannot encode the enum without its type registered
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE content_stage (
+			provider_id TEXT, provider_content_id TEXT, title TEXT, content_type TEXT, description TEXT,
+			url TEXT, thumbnail_url TEXT, published_at TIMESTAMPTZ, language TEXT
+		) ON COMMIT DROP
+	`); err != nil {
+		return err
+	}
+	rows := make([][]any, len(contents))
+	for i, c := range contents {
+		rows[i] = []any{c.ProviderID, c.ProviderContentID, c.Title, string(c.ContentType), c.Description, c.URL, c.ThumbnailURL, c.PublishedAt, c.Language}
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"content_stage"},
+		[]string{"provider_id", "provider_content_id", "title", "content_type", "description", "url", "thumbnail_url", "published_at", "language"},
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO contents(provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at, language)
+		SELECT provider_id, provider_content_id, title, content_type::content_type_enum, description, url, thumbnail_url, published_at, language
+		FROM content_stage
+		ON CONFLICT (provider_id, provider_content_id) DO UPDATE SET
+			title=EXCLUDED.title,
+			content_type=EXCLUDED.content_type,
+			description=EXCLUDED.description,
+			url=EXCLUDED.url,
+			thumbnail_url=EXCLUDED.thumbnail_url,
+			published_at=EXCLUDED.published_at,
+			language=EXCLUDED.language,
+			updated_at=NOW()
+		WHERE (contents.title, contents.content_type, contents.description, contents.url, contents.thumbnail_url, contents.published_at, contents.language)
+			IS DISTINCT FROM (EXCLUDED.title, EXCLUDED.content_type, EXCLUDED.description, EXCLUDED.url, EXCLUDED.thumbnail_url, EXCLUDED.published_at, EXCLUDED.language)
+	`); err != nil {
+		return err
+	}
+	// RETURNING skips unchanged rows, so IDs are read back for the whole batch
+	idRows, err := tx.Query(ctx, `
+		SELECT c.id, c.provider_id, c.provider_content_id
+		FROM contents c
+		INNER JOIN content_stage s ON s.provider_id = c.provider_id AND s.provider_content_id = c.provider_content_id
+	`)
+	if err != nil {
+		return err
+	}
+	defer idRows.Close()
+	ids := make(map[[2]string]int64, len(contents))
+	for idRows.Next() {
+		var id int64
+		var providerID, providerContentID string
+		if err := idRows.Scan(&id, &providerID, &providerContentID); err != nil {
+			return err
+		}
+		ids[[2]string{providerID, providerContentID}] = id
+	}
+	if err := idRows.Err(); err != nil {
+		return err
+	}
+	for i := range contents {
+		contents[i].ID = ids[[2]string{contents[i].ProviderID, contents[i].ProviderContentID}]
+	}
+	return nil
+}
+
+// upsertMetrics copies the metrics of contents that carry them into a staging table
+// and merges it into content_metrics.
+func upsertMetrics(ctx context.Context, tx pgx.Tx, contents []entities.Content) error {
+	var rows [][]any
+	for i := range contents {
+		m := contents[i].Metrics
+		if m == nil {
+			continue
+		}
+		m.ContentID = contents[i].ID
+		rows = append(rows, []any{m.ContentID, m.Views, m.Likes, m.ReadingTime, m.Reactions, m.DurationSeconds, m.Comments, m.FinalScore, m.RecalculatedAt})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE metrics_stage (
+			content_id BIGINT, views BIGINT, likes BIGINT, reading_time INT, reactions INT,
+			duration_seconds INT, comments INT, final_score DOUBLE PRECISION, recalculated_at TIMESTAMPTZ
+		) ON COMMIT DROP
+	`); err != nil {
+		return err
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"metrics_stage"},
+		[]string{"content_id", "views", "likes", "reading_time", "reactions", "duration_seconds", "comments", "final_score", "recalculated_at"},
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO content_metrics(content_id, views, likes, reading_time, reactions, duration_seconds, comments, final_score, recalculated_at)
+		SELECT content_id, views, likes, reading_time, reactions, duration_seconds, comments, final_score, recalculated_at
+		FROM metrics_stage
+		ON CONFLICT (content_id) DO UPDATE SET
+			views=EXCLUDED.views,
+			likes=EXCLUDED.likes,
+			reading_time=EXCLUDED.reading_time,
+			reactions=EXCLUDED.reactions,
+			duration_seconds=EXCLUDED.duration_seconds,
+			comments=EXCLUDED.comments,
+			final_score=EXCLUDED.final_score,
+			recalculated_at=EXCLUDED.recalculated_at,
+			updated_at=NOW()
+	`)
+	return err
+}
+
+// replaceTags sets the tags of every content in the batch, creating missing tags.
+func replaceTags(ctx context.Context, tx pgx.Tx, contents []entities.Content) error {
+	contentIDs := make([]int64, len(contents))
+	var pairIDs []int64
+	var pairNames []string
+	for i, c := range contents {
+		contentIDs[i] = c.ID
+		for _, name := range c.Tags {
+			pairIDs = append(pairIDs, c.ID)
+			pairNames = append(pairNames, name)
+		}
+	}
+	if pairNames == nil {
+		pairIDs, pairNames = []int64{}, []string{}
+	}
+	batch := &pgx.Batch{}
+	// Sorted so concurrent batches lock new tag names in the same order
+	batch.Queue(`INSERT INTO tags(name) SELECT DISTINCT n FROM unnest($1::text[]) AS n ORDER BY n ON CONFLICT (name) DO NOTHING`, pairNames)
+	batch.Queue(`
+		DELETE FROM content_tags ct
+		WHERE ct.content_id = ANY($1) AND NOT EXISTS (
+			SELECT 1 FROM unnest($2::bigint[], $3::text[]) AS p(content_id, name)
+			INNER JOIN tags t ON t.name = p.name
+			WHERE p.content_id = ct.content_id AND t.id = ct.tag_id
+		)
+	`, contentIDs, pairIDs, pairNames)
+	batch.Queue(`
+		INSERT INTO content_tags(content_id, tag_id)
+		SELECT p.content_id, t.id FROM unnest($1::bigint[], $2::text[]) AS p(content_id, name)
+		INNER JOIN tags t ON t.name = p.name
+		ON CONFLICT DO NOTHING
+	`, pairIDs, pairNames)
+	br := tx.SendBatch(ctx, batch)
+	defer func() { _ = br.Close() }()
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"search_engine/internal/domain/entities"
+)
+
+func TestContentBatchRepository_UpsertBatch(t *testing.T) {
+	pool := getTestPool(t)
+	defer pool.Close()
+	ctx := context.Background()
+
+	repo := NewContentBatchRepository(pool)
+	prefix := fmt.Sprintf("b-%d-", time.Now().UnixNano())
+	en := "en"
+	contents := []entities.Content{
+		{ProviderID: "test", ProviderContentID: prefix + "1", Title: "Batch One", ContentType: entities.ContentTypeVideo, Language: &en,
+			Metrics: &entities.ContentMetrics{Views: 10, FinalScore: 1.5}, Tags: []string{"batch", "go"}},
+		{ProviderID: "test", ProviderContentID: prefix + "2", Title: "Batch Two", ContentType: entities.ContentTypeText},
+	}
+	if err := repo.UpsertBatch(ctx, contents); err != nil {
+		t.Fatalf("UpsertBatch: %v", err)
+	}
+	if contents[0].ID == 0 || contents[1].ID == 0 {
+		t.Fatalf("expected IDs set after insert, got %d and %d", contents[0].ID, contents[1].ID)
+	}
+
+	rows, err := repo.GetByProviderKeys(ctx, "test", []string{prefix + "1", prefix + "2", prefix + "missing"})
+	if err != nil {
+		t.Fatalf("GetByProviderKeys: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 stored rows, got %d", len(rows))
+	}
+	for _, r := range rows {
+		switch r.Content.ProviderContentID {
+		case prefix + "1":
+			if r.Metrics.ContentID != contents[0].ID || r.Metrics.Views != 10 {
+				t.Fatalf("unexpected metrics for first row: %+v", r.Metrics)
+			}
+		case prefix + "2":
+			if r.Metrics.ContentID != 0 {
+				t.Fatalf("expected no metrics row for second content, got %+v", r.Metrics)
+			}
+		}
+	}
+
+	// A second batch updates changed rows only and replaces tags
+	tr := "tr"
+	contents[0].Metrics = &entities.ContentMetrics{Views: 20, FinalScore: 2.5}
+	contents[0].Tags = []string{"go"}
+	contents[1].Language = &tr
+	contents[1].ID = 0
+	if err := repo.UpsertBatch(ctx, contents); err != nil {
+		t.Fatalf("second UpsertBatch: %v", err)
+	}
+	if contents[1].ID == 0 {
+		t.Fatalf("expected ID of existing row to be resolved")
+	}
+	detail, err := NewContentRepository(pool).GetDetailByID(ctx, contents[0].ID)
+	if err != nil || detail == nil {
+		t.Fatalf("GetDetailByID: %v", err)
+	}
+	if detail.Metrics.Views != 20 || len(detail.Content.Tags) != 1 || detail.Content.Tags[0] != "go" {
+		t.Fatalf("expected updated metrics and tags, got %+v %v", detail.Metrics, detail.Content.Tags)
+	}
+	second, err := NewContentRepository(pool).GetByID(ctx, contents[1].ID)
+	if err != nil || second.Language == nil || *second.Language != "tr" {
+		t.Fatalf("expected language tr on second row, got %+v (%v)", second, err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"search_engine/internal/domain/entities"
 	"search_engine/internal/domain/repositories"
+	"search_engine/internal/infrastructure/language"
 )
 
 type contentRepository struct {
@@ -23,23 +24,23 @@ func NewContentRepository(pool *pgxpool.Pool) repositories.ContentRepository {
 func (r *contentRepository) Create(ctx context.Context, c *entities.Content) error {
 	const q = `
 		INSERT INTO contents(
-			provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at
-		) VALUES($1,$2,$3,$4,$5,$6,$7,$8)
+			provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at, language
+		) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9)
 		RETURNING id, created_at, updated_at
 	`
 	return r.pool.QueryRow(ctx, q,
-		c.ProviderID, c.ProviderContentID, c.Title, c.ContentType, c.Description, c.URL, c.ThumbnailURL, c.PublishedAt,
+		c.ProviderID, c.ProviderContentID, c.Title, c.ContentType, c.Description, c.URL, c.ThumbnailURL, c.PublishedAt, c.Language,
 	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
 }
 
 func (r *contentRepository) GetByID(ctx context.Context, id int64) (*entities.Content, error) {
 	const q = `
-		SELECT id, provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at, created_at, updated_at
+		SELECT id, provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at, language, created_at, updated_at
 		FROM contents WHERE id=$1
 	`
 	var c entities.Content
 	err := r.pool.QueryRow(ctx, q, id).Scan(
-		&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.CreatedAt, &c.UpdatedAt,
+		&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.Language, &c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -125,12 +126,12 @@ func (r *contentRepository) GetAll(ctx context.Context, filters repositories.Con
 func (r *contentRepository) Update(ctx context.Context, c *entities.Content) error {
 	const q = `
 		UPDATE contents
-		SET provider_id=$1, provider_content_id=$2, title=$3, content_type=$4, description=$5, url=$6, thumbnail_url=$7, published_at=$8, updated_at=NOW()
-		WHERE id=$9
+		SET provider_id=$1, provider_content_id=$2, title=$3, content_type=$4, description=$5, url=$6, thumbnail_url=$7, published_at=$8, language=$9, updated_at=NOW()
+		WHERE id=$10
 		RETURNING updated_at
 	`
 	return r.pool.QueryRow(ctx, q,
-		c.ProviderID, c.ProviderContentID, c.Title, c.ContentType, c.Description, c.URL, c.ThumbnailURL, c.PublishedAt, c.ID,
+		c.ProviderID, c.ProviderContentID, c.Title, c.ContentType, c.Description, c.URL, c.ThumbnailURL, c.PublishedAt, c.Language, c.ID,
 	).Scan(&c.UpdatedAt)
 }
 
@@ -142,10 +143,10 @@ func (r *contentRepository) BulkInsert(ctx context.Context, contents []entities.
 	for i := range contents {
 		c := contents[i]
 		batch.Queue(`
-			INSERT INTO contents(provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at)
-			VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+			INSERT INTO contents(provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at, language)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
 			ON CONFLICT (provider_id, provider_content_id) DO NOTHING
-		`, c.ProviderID, c.ProviderContentID, c.Title, c.ContentType, c.Description, c.URL, c.ThumbnailURL, c.PublishedAt)
+		`, c.ProviderID, c.ProviderContentID, c.Title, c.ContentType, c.Description, c.URL, c.ThumbnailURL, c.PublishedAt, c.Language)
 	}
 	br := r.pool.SendBatch(ctx, batch)
 	defer func() { _ = br.Close() }()
@@ -226,12 +227,12 @@ func (r *contentRepository) SearchByKeyword(ctx context.Context, keyword string,
 
 func (r *contentRepository) GetByProviderKey(ctx context.Context, providerID, providerContentID string) (*entities.Content, error) {
 	const q = `
-		SELECT id, provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at, created_at, updated_at
+		SELECT id, provider_id, provider_content_id, title, content_type, description, url, thumbnail_url, published_at, language, created_at, updated_at
 		FROM contents WHERE provider_id=$1 AND provider_content_id=$2
 	`
 	var c entities.Content
 	if err := r.pool.QueryRow(ctx, q, providerID, providerContentID).Scan(
-		&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.CreatedAt, &c.UpdatedAt,
+		&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.Language, &c.CreatedAt, &c.UpdatedAt,
 	); err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil // Content not found
@@ -269,10 +270,10 @@ func (r *contentRepository) CountAll(ctx context.Context) (int64, error) {
 	return total, nil
 }
 
-func (r *contentRepository) SearchWithFilters(ctx context.Context, keyword string, contentType *entities.ContentType, pagination repositories.Pagination, sort repositories.SearchSort) ([]repositories.ContentWithMetrics, int64, error) {
+func (r *contentRepository) SearchWithFilters(ctx context.Context, keyword string, filters repositories.ContentFilters, pagination repositories.Pagination, sort repositories.SearchSort) ([]repositories.ContentWithMetrics, int64, error) {
 	// Use full-text search if keyword is provided
 	if keyword != "" {
-		return r.searchWithFullText(ctx, keyword, contentType, pagination, sort)
+		return r.searchWithFullText(ctx, keyword, filters, pagination, sort)
 	}
 
 	// Fallback to basic LIKE search for backward compatibility
@@ -286,11 +287,10 @@ func (r *contentRepository) SearchWithFilters(ctx context.Context, keyword strin
 		args = append(args, k, k)
 		arg += 2
 	}
-	if contentType != nil {
-		where += fmt.Sprintf(" AND c.content_type = $%d", arg)
-		args = append(args, *contentType)
-		arg++
-	}
+	filterSQL, filterArgs := searchFilterSQL(filters, arg)
+	where += filterSQL
+	args = append(args, filterArgs...)
+	arg += len(filterArgs)
 	countSQL := "SELECT COUNT(*) FROM contents c INNER JOIN content_metrics cm ON cm.content_id = c.id " + where + " AND c.deleted_at IS NULL"
 	var total int64
 	if err := r.pool.QueryRow(ctx, countSQL, args...).Scan(&total); err != nil {
@@ -314,8 +314,9 @@ func (r *contentRepository) SearchWithFilters(ctx context.Context, keyword strin
 	offset := (pagination.Page - 1) * pagination.PageSize
 	sql := `
 		SELECT
-			c.id, c.provider_id, c.provider_content_id, c.title, c.content_type, c.description, c.url, c.thumbnail_url, c.published_at, c.created_at, c.updated_at,
-			cm.id, cm.content_id, cm.views, cm.likes, cm.reading_time, cm.reactions, cm.final_score, cm.recalculated_at, cm.created_at, cm.updated_at
+			c.id, c.provider_id, c.provider_content_id, c.title, c.content_type, c.description, c.url, c.thumbnail_url, c.published_at, c.language, c.created_at, c.updated_at,
+			cm.id, cm.content_id, cm.views, cm.likes, cm.reading_time, cm.reactions, cm.duration_seconds, cm.comments, cm.final_score, cm.recalculated_at, cm.created_at, cm.updated_at,
+			` + tagsColumn + `
 		FROM contents c
 		INNER JOIN content_metrics cm ON cm.content_id = c.id
 	` + where + `
@@ -335,8 +336,9 @@ func (r *contentRepository) SearchWithFilters(ctx context.Context, keyword strin
 		var c entities.Content
 		var m entities.ContentMetrics
 		if err := rows.Scan(
-			&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.CreatedAt, &c.UpdatedAt,
-			&m.ID, &m.ContentID, &m.Views, &m.Likes, &m.ReadingTime, &m.Reactions, &m.FinalScore, &m.RecalculatedAt, &m.CreatedAt, &m.UpdatedAt,
+			&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.Language, &c.CreatedAt, &c.UpdatedAt,
+			&m.ID, &m.ContentID, &m.Views, &m.Likes, &m.ReadingTime, &m.Reactions, &m.DurationSeconds, &m.Comments, &m.FinalScore, &m.RecalculatedAt, &m.CreatedAt, &m.UpdatedAt,
+			&c.Tags,
 		); err != nil {
 			return nil, 0, err
 		}
@@ -349,10 +351,11 @@ func (r *contentRepository) SearchWithFilters(ctx context.Context, keyword strin
 }
 
 func (r *contentRepository) GetDetailByID(ctx context.Context, id int64) (*repositories.ContentWithMetrics, error) {
-	const q = `
+	q := `
 		SELECT 
-			c.id, c.provider_id, c.provider_content_id, c.title, c.content_type, c.description, c.url, c.thumbnail_url, c.published_at, c.created_at, c.updated_at,
-			cm.id, cm.content_id, cm.views, cm.likes, cm.reading_time, cm.reactions, cm.final_score, cm.recalculated_at, cm.created_at, cm.updated_at
+			c.id, c.provider_id, c.provider_content_id, c.title, c.content_type, c.description, c.url, c.thumbnail_url, c.published_at, c.language, c.created_at, c.updated_at,
+			cm.id, cm.content_id, cm.views, cm.likes, cm.reading_time, cm.reactions, cm.duration_seconds, cm.comments, cm.final_score, cm.recalculated_at, cm.created_at, cm.updated_at,
+			` + tagsColumn + `
 		FROM contents c
 		INNER JOIN content_metrics cm ON cm.content_id = c.id
 		WHERE c.id=$1 AND c.deleted_at IS NULL
@@ -360,8 +363,9 @@ func (r *contentRepository) GetDetailByID(ctx context.Context, id int64) (*repos
 	var c entities.Content
 	var m entities.ContentMetrics
 	if err := r.pool.QueryRow(ctx, q, id).Scan(
-		&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.CreatedAt, &c.UpdatedAt,
-		&m.ID, &m.ContentID, &m.Views, &m.Likes, &m.ReadingTime, &m.Reactions, &m.FinalScore, &m.RecalculatedAt, &m.CreatedAt, &m.UpdatedAt,
+		&c.ID, &c.ProviderID, &c.ProviderContentID, &c.Title, &c.ContentType, &c.Description, &c.URL, &c.ThumbnailURL, &c.PublishedAt, &c.Language, &c.CreatedAt, &c.UpdatedAt,
+		&m.ID, &m.ContentID, &m.Views, &m.Likes, &m.ReadingTime, &m.Reactions, &m.DurationSeconds, &m.Comments, &m.FinalScore, &m.RecalculatedAt, &m.CreatedAt, &m.UpdatedAt,
+		&c.Tags,
 	); err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil // Content not found
@@ -421,6 +425,61 @@ func (r *contentRepository) SoftDelete(ctx context.Context, id int64) error {
 	return err
 }
 
+func (r *contentRepository) MarkMissing(ctx context.Context, providerID string, seen []string, misses int) ([]int64, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE contents c SET missed_syncs = c.missed_syncs + 1
+		WHERE c.provider_id = $1 AND c.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM unnest($2::text[]) AS s(id) WHERE s.id = c.provider_content_id)
+		RETURNING c.id, c.missed_syncs
+	`, providerID, seen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var missed int
+		if err := rows.Scan(&id, &missed); err != nil {
+			return nil, err
+		}
+		if missed >= misses {
+			ids = append(ids, id)
+		}
+	}
+	return ids, rows.Err()
+}
+
+func (r *contentRepository) RestoreSeen(ctx context.Context, providerID string, seen []string) (int, error) {
+	// Only rows with missed syncs were removed by a sync; other deletions stay in place
+	rows, err := r.pool.Query(ctx, `
+		WITH hit AS (
+			SELECT c.id, c.deleted_at IS NOT NULL AS removed
+			FROM contents c
+			WHERE c.provider_id = $1 AND c.missed_syncs > 0
+				AND EXISTS (SELECT 1 FROM unnest($2::text[]) AS s(id) WHERE s.id = c.provider_content_id)
+		)
+		UPDATE contents c SET missed_syncs = 0, deleted_at = NULL, updated_at = CASE WHEN hit.removed THEN NOW() ELSE c.updated_at END
+		FROM hit WHERE c.id = hit.id
+		RETURNING hit.removed
+	`, providerID, seen)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	restored := 0
+	for rows.Next() {
+		var removed bool
+		if err := rows.Scan(&removed); err != nil {
+			return 0, err
+		}
+		if removed {
+			restored++
+		}
+	}
+	return restored, rows.Err()
+}
+
 func (r *contentRepository) ListIDsByType(ctx context.Context, t entities.ContentType, offset, limit int) ([]int64, error) {
 	rows, err := r.pool.Query(ctx, `SELECT id FROM contents WHERE content_type=$1 AND deleted_at IS NULL ORDER BY id LIMIT $2 OFFSET $3`, t, limit, offset)
 	if err != nil {
@@ -452,10 +511,13 @@ func (r *contentRepository) GetAverageScoreByProvider(ctx context.Context, provi
 }
 
 // searchWithFullText performs advanced full-text search with fuzzy matching
-func (r *contentRepository) searchWithFullText(ctx context.Context, keyword string, contentType *entities.ContentType, pagination repositories.Pagination, sort repositories.SearchSort) ([]repositories.ContentWithMetrics, int64, error) {
+func (r *contentRepository) searchWithFullText(ctx context.Context, keyword string, filters repositories.ContentFilters, pagination repositories.Pagination, sort repositories.SearchSort) ([]repositories.ContentWithMetrics, int64, error) {
+	args := []interface{}{keyword}
+	tsquery := searchTSQuery(filters.Language, &args)
 	// Build the query with full-text search and fuzzy matching
 	query := `
-		WITH search_results AS (
+		WITH q AS (SELECT ` + tsquery + ` AS query),
+		search_results AS (
 			SELECT
 				c.id,
 				c.provider_id,
@@ -466,28 +528,33 @@ func (r *contentRepository) searchWithFullText(ctx context.Context, keyword stri
 				c.url,
 				c.thumbnail_url,
 				c.published_at,
+				c.language,
 				c.created_at,
 				c.updated_at,
 				cm.views,
 				cm.likes,
 				cm.reading_time,
 				cm.reactions,
+				cm.duration_seconds,
+				cm.comments,
 				cm.final_score,
 				cm.recalculated_at,
-				-- Full-text search relevance
-				content_search_relevance($1, c.title, c.description) as fts_relevance,
+				` + tagsColumn + ` as tags,
+				-- Full-text search relevance; title terms weigh A (1.0), description terms B (0.4)
+				ts_rank(c.search_vector, q.query) as fts_relevance,
 				-- Fuzzy search relevance (trigram similarity)
 				fuzzy_search_relevance($1, c.title, c.description) as fuzzy_relevance,
 				-- Combined relevance score
-				(content_search_relevance($1, c.title, c.description) * 0.7 +
+				(ts_rank(c.search_vector, q.query) * 0.7 +
 				 fuzzy_search_relevance($1, c.title, c.description) * 0.3) as combined_relevance
 			FROM contents c
 			JOIN content_metrics cm ON c.id = cm.content_id
+			CROSS JOIN q
 			WHERE
 				c.deleted_at IS NULL
 				AND (
 					-- Full-text search match
-					to_tsvector('english', c.title || ' ' || COALESCE(c.description, '')) @@ plainto_tsquery('english', $1)
+					c.search_vector @@ q.query
 					OR
 					-- Fuzzy search fallback (for partial matches)
 					similarity($1, c.title) > 0.1
@@ -496,22 +563,20 @@ func (r *contentRepository) searchWithFullText(ctx context.Context, keyword stri
 				)
 	`
 
-	args := []interface{}{keyword}
-	argIndex := 2
+	argIndex := len(args) + 1
 
-	// Add content type filter
-	if contentType != nil {
-		query += fmt.Sprintf(" AND c.content_type = $%d", argIndex)
-		args = append(args, *contentType)
-		argIndex++
-	}
+	// Add type/provider/tag filters
+	filterSQL, filterArgs := searchFilterSQL(filters, argIndex)
+	query += filterSQL
+	args = append(args, filterArgs...)
+	argIndex += len(filterArgs)
 
 	query += `
 		)
 		SELECT
 			id, provider_id, provider_content_id, title, content_type, description, url, thumbnail_url,
-			published_at, created_at, updated_at, views, likes, reading_time, reactions, final_score,
-			recalculated_at, combined_relevance
+			published_at, language, created_at, updated_at, views, likes, reading_time, reactions, duration_seconds, comments, final_score,
+			recalculated_at, tags, combined_relevance
 		FROM search_results
 	`
 
@@ -557,10 +622,11 @@ func (r *contentRepository) searchWithFullText(ctx context.Context, keyword stri
 			&item.Content.ID, &item.Content.ProviderID, &item.Content.ProviderContentID,
 			&item.Content.Title, &item.Content.ContentType, &item.Content.Description,
 			&item.Content.URL, &item.Content.ThumbnailURL, &item.Content.PublishedAt,
-			&item.Content.CreatedAt, &item.Content.UpdatedAt,
+			&item.Content.Language, &item.Content.CreatedAt, &item.Content.UpdatedAt,
 			&item.Metrics.Views, &item.Metrics.Likes, &item.Metrics.ReadingTime,
-			&item.Metrics.Reactions, &item.Metrics.FinalScore, &item.Metrics.RecalculatedAt,
-			&combinedRelevance,
+			&item.Metrics.Reactions, &item.Metrics.DurationSeconds, &item.Metrics.Comments,
+			&item.Metrics.FinalScore, &item.Metrics.RecalculatedAt,
+			&item.Content.Tags, &combinedRelevance,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan search result: %w", err)
@@ -574,13 +640,15 @@ func (r *contentRepository) searchWithFullText(ctx context.Context, keyword stri
 
 	// Get total count
 	countQuery := `
+		WITH q AS (SELECT ` + tsquery + ` AS query)
 		SELECT COUNT(*)
 		FROM contents c
 		JOIN content_metrics cm ON c.id = cm.content_id
+		CROSS JOIN q
 		WHERE
 			c.deleted_at IS NULL
 			AND (
-				to_tsvector('english', c.title || ' ' || COALESCE(c.description, '')) @@ plainto_tsquery('english', $1)
+				c.search_vector @@ q.query
 				OR
 				similarity($1, c.title) > 0.1
 				OR
@@ -589,10 +657,10 @@ func (r *contentRepository) searchWithFullText(ctx context.Context, keyword stri
 	`
 
 	countArgs := []interface{}{keyword}
-	if contentType != nil {
-		countQuery += fmt.Sprintf(" AND c.content_type = $%d", len(countArgs)+1)
-		countArgs = append(countArgs, *contentType)
-	}
+	searchTSQuery(filters.Language, &countArgs)
+	countFilterSQL, countFilterArgs := searchFilterSQL(filters, len(countArgs)+1)
+	countQuery += countFilterSQL
+	countArgs = append(countArgs, countFilterArgs...)
 
 	var total int64
 	err = r.pool.QueryRow(ctx, countQuery, countArgs...).Scan(&total)
@@ -602,3 +670,52 @@ func (r *contentRepository) searchWithFullText(ctx context.Context, keyword stri
 
 	return items, total, nil
 }
+
+// searchTSQuery renders the tsquery of the keyword bound as $1. With a language it
+// is parsed with that language's configuration, appended to args; otherwise the
+// parses of every configuration are OR-ed, so each row matches through the one its
+// search_vector was built with and the GIN index stays usable.
+func searchTSQuery(lang *string, args *[]any) string {
+	if lang != nil {
+		*args = append(*args, language.TSConfig(*lang))
+		return fmt.Sprintf("plainto_tsquery($%d::regconfig, $1)", len(*args))
+	}
+	return "(plainto_tsquery('english', $1) || plainto_tsquery('turkish', $1) || plainto_tsquery('simple', $1))"
+}
+
+// tagsColumn selects the sorted tag names of the content aliased as c
+const tagsColumn = `ARRAY(SELECT t.name FROM content_tags ct INNER JOIN tags t ON t.id = ct.tag_id WHERE ct.content_id = c.id ORDER BY t.name)`
+
+// searchFilterSQL renders type, provider and tag filters as AND clauses with placeholders starting at $arg
+func searchFilterSQL(filters repositories.ContentFilters, arg int) (string, []any) {
+	var sql strings.Builder
+	var args []any
+	if filters.ContentType != nil {
+		fmt.Fprintf(&sql, " AND c.content_type = $%d", arg)
+		args = append(args, *filters.ContentType)
+		arg++
+	}
+	if filters.ProviderID != nil {
+		fmt.Fprintf(&sql, " AND c.provider_id = $%d", arg)
+		args = append(args, *filters.ProviderID)
+		arg++
+	}
+	if filters.Language != nil {
+		// Rows stored before language detection have none yet and are searched as
+		// english, as content_ts_config indexes them; their next sync detects it
+		fmt.Fprintf(&sql, " AND COALESCE(c.language, 'en') = $%d", arg)
+		args = append(args, *filters.Language)
+		arg++
+	}
+	if len(filters.Tags) > 0 {
+		const tagMatch = `SELECT %s FROM content_tags ct INNER JOIN tags t ON t.id = ct.tag_id WHERE ct.content_id = c.id AND t.name = ANY($%d)`
+		if filters.MatchAllTags {
+			fmt.Fprintf(&sql, " AND ("+tagMatch+") = $%d", "COUNT(DISTINCT t.name)", arg, arg+1)
+			args = append(args, filters.Tags, len(filters.Tags))
+		} else {
+			fmt.Fprintf(&sql, " AND EXISTS ("+tagMatch+")", "1", arg)
+			args = append(args, filters.Tags)
+		}
+	}
+	return sql.String(), args
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -94,3 +95,23 @@ func TestContentRepository_CRUD(t *testing.T) {
 		t.Fatalf("expected search results")
 	}
 }
+
+func TestSearchFilterSQL_Tags(t *testing.T) {
+	sql, args := searchFilterSQL(repositories.ContentFilters{Tags: []string{"devops", "kubernetes"}}, 2)
+	if !strings.Contains(sql, "EXISTS") || !strings.Contains(sql, "ANY($2)") || len(args) != 1 {
+		t.Fatalf("unexpected any-match filter: %s %v", sql, args)
+	}
+	vt := entities.ContentTypeVideo
+	sql, args = searchFilterSQL(repositories.ContentFilters{ContentType: &vt, Tags: []string{"devops", "kubernetes"}, MatchAllTags: true}, 1)
+	if !strings.Contains(sql, "content_type = $1") || !strings.Contains(sql, "ANY($2)) = $3") || len(args) != 3 || args[2] != 2 {
+		t.Fatalf("unexpected all-match filter: %s %v", sql, args)
+	}
+}
+
+func TestSearchFilterSQL_LanguageMatchesUndetectedRowsAsEnglish(t *testing.T) {
+	en := "en"
+	sql, args := searchFilterSQL(repositories.ContentFilters{Language: &en}, 2)
+	if !strings.Contains(sql, "COALESCE(c.language, 'en') = $2") || len(args) != 1 || args[0] != "en" {
+		t.Fatalf("unexpected language filter: %s %v", sql, args)
+	}
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/repositories"
+)
+
+type ingestDeliveryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewIngestDeliveryRepository(pool *pgxpool.Pool) repositories.IngestDeliveryRepository {
+	return &ingestDeliveryRepository{pool: pool}
+}
+
+func (r *ingestDeliveryRepository) Claim(ctx context.Context, providerID, deliveryID string, staleAfter time.Duration) (bool, *entities.IngestDelivery, error) {
+	// The conditional upsert only returns a row when this call owns the delivery
+	const q = `
+		INSERT INTO ingest_deliveries(provider_id, delivery_id, status, received_at)
+		VALUES ($1,$2,'processing',NOW())
+		ON CONFLICT (provider_id, delivery_id) DO UPDATE
+		SET received_at=NOW()
+		WHERE ingest_deliveries.status='processing'
+		  AND ingest_deliveries.received_at < NOW() - make_interval(secs => $3)
+		RETURNING provider_id
+	`
+	var id string
+	err := r.pool.QueryRow(ctx, q, providerID, deliveryID, staleAfter.Seconds()).Scan(&id)
+	if err == nil {
+		return true, nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, nil, err
+	}
+	var d entities.IngestDelivery
+	var result []byte
+	err = r.pool.QueryRow(ctx, `
+		SELECT provider_id, delivery_id, status, result, received_at, completed_at
+		FROM ingest_deliveries WHERE provider_id=$1 AND delivery_id=$2
+	`, providerID, deliveryID).Scan(&d.ProviderID, &d.DeliveryID, &d.Status, &result, &d.ReceivedAt, &d.CompletedAt)
+	if err != nil {
+		return false, nil, err
+	}
+	d.Result = result
+	return false, &d, nil
+}
+
+func (r *ingestDeliveryRepository) Complete(ctx context.Context, providerID, deliveryID string, result []byte) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE ingest_deliveries SET status='completed', result=$3, completed_at=NOW()
+		WHERE provider_id=$1 AND delivery_id=$2
+	`, providerID, deliveryID, result)
+	return err
+}
+
+func (r *ingestDeliveryRepository) Release(ctx context.Context, providerID, deliveryID string) error {
+	_, err := r.pool.Exec(ctx, `
+		DELETE FROM ingest_deliveries WHERE provider_id=$1 AND delivery_id=$2 AND status='processing'
+	`, providerID, deliveryID)
+	return err
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/repositories"
+)
+
+type providerRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewProviderRepository(pool *pgxpool.Pool) repositories.ProviderRepository {
+	return &providerRepository{pool: pool}
+}
+
+const providerColumns = `id, kind, base_url, enabled, rate_limit_per_minute, timeout_ms, sync_interval_seconds, definition, transport, source, created_at, updated_at`
+
+func scanProvider(row pgx.Row) (entities.Provider, error) {
+	var p entities.Provider
+	var timeoutMs, intervalSec int
+	var def, transport []byte
+	err := row.Scan(&p.ID, &p.Kind, &p.BaseURL, &p.Enabled, &p.RateLimitPerMinute, &timeoutMs, &intervalSec, &def, &transport, &p.Source, &p.CreatedAt, &p.UpdatedAt)
+	p.Timeout = time.Duration(timeoutMs) * time.Millisecond
+	p.SyncInterval = time.Duration(intervalSec) * time.Second
+	if len(def) > 0 {
+		p.Definition = def
+	}
+	if len(transport) > 0 {
+		p.Transport = transport
+	}
+	return p, err
+}
+
+// definitionParam sends an absent definition as NULL rather than invalid JSON.
+func definitionParam(p *entities.Provider) any {
+	if len(p.Definition) == 0 {
+		return nil
+	}
+	return string(p.Definition)
+}
+
+func transportParam(p *entities.Provider) any {
+	if len(p.Transport) == 0 {
+		return nil
+	}
+	return string(p.Transport)
+}
+
+// sourceParam stores rows without a source as managed through the admin API.
+func sourceParam(p *entities.Provider) string {
+	if p.Source == "" {
+		return string(entities.ProviderSourceAdmin)
+	}
+	return string(p.Source)
+}
+
+func (r *providerRepository) List(ctx context.Context) ([]entities.Provider, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+providerColumns+` FROM providers ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []entities.Provider
+	for rows.Next() {
+		p, err := scanProvider(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *providerRepository) Get(ctx context.Context, id string) (*entities.Provider, error) {
+	p, err := scanProvider(r.pool.QueryRow(ctx, `SELECT `+providerColumns+` FROM providers WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *providerRepository) Create(ctx context.Context, p *entities.Provider) error {
+	const q = `
+		INSERT INTO providers(id, kind, base_url, enabled, rate_limit_per_minute, timeout_ms, sync_interval_seconds, definition, transport, source)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+		ON CONFLICT (id) DO NOTHING
+		RETURNING created_at, updated_at
+	`
+	err := r.pool.QueryRow(ctx, q,
+		p.ID, p.Kind, p.BaseURL, p.Enabled, p.RateLimitPerMinute, p.Timeout.Milliseconds(), int64(p.SyncInterval/time.Second), definitionParam(p), transportParam(p), sourceParam(p),
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repositories.ErrProviderExists
+	}
+	return err
+}
+
+func (r *providerRepository) Update(ctx context.Context, p *entities.Provider) error {
+	const q = `
+		UPDATE providers
+		SET kind=$2,
+		    base_url=$3,
+		    enabled=$4,
+		    rate_limit_per_minute=$5,
+		    timeout_ms=$6,
+		    sync_interval_seconds=$7,
+		    definition=$8,
+		    transport=$9,
+		    source=$10,
+		    updated_at=NOW()
+		WHERE id=$1
+		RETURNING created_at, updated_at
+	`
+	err := r.pool.QueryRow(ctx, q,
+		p.ID, p.Kind, p.BaseURL, p.Enabled, p.RateLimitPerMinute, p.Timeout.Milliseconds(), int64(p.SyncInterval/time.Second), definitionParam(p), transportParam(p), sourceParam(p),
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repositories.ErrProviderNotFound
+	}
+	return err
+}
+
+func (r *providerRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM providers WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrProviderNotFound
+	}
+	return nil
+}
+
+func (r *providerRepository) Version(ctx context.Context) (string, error) {
+	var n int64
+	var latest time.Time
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*), COALESCE(MAX(updated_at), 'epoch') FROM providers`).Scan(&n, &latest)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d:%d", n, latest.UnixNano()), nil
+}
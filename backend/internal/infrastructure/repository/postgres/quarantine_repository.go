@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/repositories"
+)
+
+type quarantineRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewQuarantineRepository(pool *pgxpool.Pool) repositories.QuarantineRepository {
+	return &quarantineRepository{pool: pool}
+}
+
+const quarantineColumns = `id, provider_id, item_key, provider_content_id, reasons, raw_payload, item, status, occurrences, first_seen_at, last_seen_at`
+
+func scanQuarantined(row pgx.Row) (entities.QuarantinedItem, error) {
+	var it entities.QuarantinedItem
+	var item []byte
+	err := row.Scan(&it.ID, &it.ProviderID, &it.ItemKey, &it.ProviderContentID, &it.Reasons, &it.RawPayload, &item, &it.Status, &it.Occurrences, &it.FirstSeenAt, &it.LastSeenAt)
+	it.Item = item
+	return it, err
+}
+
+func (r *quarantineRepository) Upsert(ctx context.Context, it *entities.QuarantinedItem) error {
+	const q = `
+		INSERT INTO quarantined_items(provider_id, item_key, provider_content_id, reasons, raw_payload, item)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		ON CONFLICT (provider_id, item_key) DO UPDATE
+		SET provider_content_id=EXCLUDED.provider_content_id,
+		    reasons=EXCLUDED.reasons,
+		    raw_payload=EXCLUDED.raw_payload,
+		    item=EXCLUDED.item,
+		    occurrences=quarantined_items.occurrences + 1,
+		    last_seen_at=NOW()
+		RETURNING ` + quarantineColumns
+	saved, err := scanQuarantined(r.pool.QueryRow(ctx, q, it.ProviderID, it.ItemKey, it.ProviderContentID, it.Reasons, it.RawPayload, []byte(it.Item)))
+	if err != nil {
+		return err
+	}
+	*it = saved
+	return nil
+}
+
+func (r *quarantineRepository) Get(ctx context.Context, id int64) (*entities.QuarantinedItem, error) {
+	it, err := scanQuarantined(r.pool.QueryRow(ctx, `SELECT `+quarantineColumns+` FROM quarantined_items WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
+
+// quarantineFilter appends the optional provider and status conditions.
+func quarantineFilter(q string, providerID *string, status *entities.QuarantineStatus) (string, []any) {
+	args := []any{}
+	if providerID != nil && *providerID != "" {
+		args = append(args, *providerID)
+		q += ` AND provider_id = $` + strconv.Itoa(len(args))
+	}
+	if status != nil && *status != "" {
+		args = append(args, *status)
+		q += ` AND status = $` + strconv.Itoa(len(args))
+	}
+	return q, args
+}
+
+func (r *quarantineRepository) List(ctx context.Context, providerID *string, status *entities.QuarantineStatus, limit, offset int) ([]entities.QuarantinedItem, error) {
+	q, args := quarantineFilter(`SELECT `+quarantineColumns+` FROM quarantined_items WHERE 1=1`, providerID, status)
+	args = append(args, limit, offset)
+	q += ` ORDER BY last_seen_at DESC, id DESC LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []entities.QuarantinedItem
+	for rows.Next() {
+		it, err := scanQuarantined(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, rows.Err()
+}
+
+func (r *quarantineRepository) Count(ctx context.Context, providerID *string, status *entities.QuarantineStatus) (int64, error) {
+	q, args := quarantineFilter(`SELECT COUNT(*) FROM quarantined_items WHERE 1=1`, providerID, status)
+	var n int64
+	err := r.pool.QueryRow(ctx, q, args...).Scan(&n)
+	return n, err
+}
+
+func (r *quarantineRepository) SetStatus(ctx context.Context, id int64, status entities.QuarantineStatus) error {
+	_, err := r.pool.Exec(ctx, `UPDATE quarantined_items SET status=$2 WHERE id=$1`, id, status)
+	return err
+}
+
+func (r *quarantineRepository) UpdateReasons(ctx context.Context, id int64, reasons []string) error {
+	_, err := r.pool.Exec(ctx, `UPDATE quarantined_items SET reasons=$2 WHERE id=$1`, id, reasons)
+	return err
+}
+
+func (r *quarantineRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM quarantined_items WHERE id=$1`, id)
+	return err
+}
+
+func (r *quarantineRepository) Resolve(ctx context.Context, providerID string, itemKeys []string) error {
+	if len(itemKeys) == 0 {
+		return nil
+	}
+	_, err := r.pool.Exec(ctx, `
+		DELETE FROM quarantined_items WHERE provider_id=$1 AND status='pending' AND item_key = ANY($2)
+	`, providerID, itemKeys)
+	return err
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/repositories"
+)
+
+type syncCursorRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSyncCursorRepository(pool *pgxpool.Pool) repositories.SyncCursorRepository {
+	return &syncCursorRepository{pool: pool}
+}
+
+func (r *syncCursorRepository) Get(ctx context.Context, providerID string) (*entities.SyncCursor, error) {
+	var c entities.SyncCursor
+	err := r.pool.QueryRow(ctx, `
+		SELECT provider_id, etag, last_modified, max_published_at, updated_at
+		FROM sync_cursors WHERE provider_id=$1
+	`, providerID).Scan(&c.ProviderID, &c.ETag, &c.LastModified, &c.MaxPublishedAt, &c.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *syncCursorRepository) Upsert(ctx context.Context, c *entities.SyncCursor) error {
+	const q = `
+		INSERT INTO sync_cursors(provider_id, etag, last_modified, max_published_at, updated_at)
+		VALUES ($1,$2,$3,$4,NOW())
+		ON CONFLICT (provider_id) DO UPDATE
+		SET etag=EXCLUDED.etag,
+		    last_modified=EXCLUDED.last_modified,
+		    max_published_at=EXCLUDED.max_published_at,
+		    updated_at=NOW()
+		RETURNING updated_at
+	`
+	return r.pool.QueryRow(ctx, q, c.ProviderID, c.ETag, c.LastModified, c.MaxPublishedAt).Scan(&c.UpdatedAt)
+}
+
+func (r *syncCursorRepository) Delete(ctx context.Context, providerID string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM sync_cursors WHERE provider_id=$1`, providerID)
+	return err
+}
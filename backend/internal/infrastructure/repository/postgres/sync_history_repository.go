@@ -21,12 +21,12 @@ func NewSyncHistoryRepository(pool *pgxpool.Pool) repositories.SyncHistoryReposi
 func (r *syncHistoryRepository) Create(ctx context.Context, h *entities.SyncHistory) error {
 	const q = `
 		INSERT INTO sync_history(
-			provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, error_message, started_at, completed_at, duration_ms
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+			provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, quarantined_contents, removed_contents, changed_contents, error_message, started_at, completed_at, duration_ms, attempts
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)
 		RETURNING id
 	`
 	return r.pool.QueryRow(ctx, q,
-		h.ProviderID, h.SyncStatus, h.TotalFetched, h.NewContents, h.UpdatedContents, h.SkippedContents, h.FailedContents, h.ErrorMessage, h.StartedAt, h.CompletedAt, h.DurationMs,
+		h.ProviderID, h.SyncStatus, h.TotalFetched, h.NewContents, h.UpdatedContents, h.SkippedContents, h.FailedContents, h.QuarantinedContents, h.RemovedContents, h.ChangedContents, h.ErrorMessage, h.StartedAt, h.CompletedAt, h.DurationMs, attemptsOrEmpty(h.Attempts),
 	).Scan(&h.ID)
 }
 
@@ -39,10 +39,14 @@ func (r *syncHistoryRepository) Update(ctx context.Context, h *entities.SyncHist
 		    updated_contents=$4,
 		    skipped_contents=$5,
 		    failed_contents=$6,
-		    error_message=$7,
-		    completed_at=$8,
-		    duration_ms=$9
-		WHERE id=$10
+		    quarantined_contents=$7,
+		    removed_contents=$8,
+		    changed_contents=$9,
+		    error_message=$10,
+		    completed_at=$11,
+		    duration_ms=$12,
+		    attempts=$13
+		WHERE id=$14
 	`
 	_, err := r.pool.Exec(ctx, q,
 		h.SyncStatus,
@@ -51,9 +55,13 @@ func (r *syncHistoryRepository) Update(ctx context.Context, h *entities.SyncHist
 		h.UpdatedContents,
 		h.SkippedContents,
 		h.FailedContents,
+		h.QuarantinedContents,
+		h.RemovedContents,
+		h.ChangedContents,
 		h.ErrorMessage,
 		h.CompletedAt,
 		h.DurationMs,
+		attemptsOrEmpty(h.Attempts),
 		h.ID,
 	)
 	return err
@@ -61,7 +69,7 @@ func (r *syncHistoryRepository) Update(ctx context.Context, h *entities.SyncHist
 
 func (r *syncHistoryRepository) GetByProviderID(ctx context.Context, providerID string, limit int) ([]entities.SyncHistory, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, error_message, started_at, completed_at, duration_ms
+		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, quarantined_contents, removed_contents, changed_contents, error_message, started_at, completed_at, duration_ms, attempts
 		FROM sync_history WHERE provider_id=$1 ORDER BY started_at DESC LIMIT $2
 	`, providerID, limit)
 	if err != nil {
@@ -71,7 +79,7 @@ func (r *syncHistoryRepository) GetByProviderID(ctx context.Context, providerID
 	var out []entities.SyncHistory
 	for rows.Next() {
 		var h entities.SyncHistory
-		if err := rows.Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs); err != nil {
+		if err := rows.Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.QuarantinedContents, &h.RemovedContents, &h.ChangedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs, &h.Attempts); err != nil {
 			return nil, err
 		}
 		out = append(out, h)
@@ -82,9 +90,23 @@ func (r *syncHistoryRepository) GetByProviderID(ctx context.Context, providerID
 func (r *syncHistoryRepository) GetLastSync(ctx context.Context, providerID string) (*entities.SyncHistory, error) {
 	var h entities.SyncHistory
 	err := r.pool.QueryRow(ctx, `
-		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, error_message, started_at, completed_at, duration_ms
+		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, quarantined_contents, removed_contents, changed_contents, error_message, started_at, completed_at, duration_ms, attempts
 		FROM sync_history WHERE provider_id=$1 ORDER BY started_at DESC LIMIT 1
-	`, providerID).Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs)
+	`, providerID).Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.QuarantinedContents, &h.RemovedContents, &h.ChangedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs, &h.Attempts)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *syncHistoryRepository) GetLastSuccessfulSync(ctx context.Context, providerID string) (*entities.SyncHistory, error) {
+	var h entities.SyncHistory
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, quarantined_contents, removed_contents, changed_contents, error_message, started_at, completed_at, duration_ms, attempts
+		FROM sync_history
+		WHERE provider_id=$1 AND sync_status IN ('success','partial') AND completed_at IS NOT NULL
+		ORDER BY completed_at DESC LIMIT 1
+	`, providerID).Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.QuarantinedContents, &h.RemovedContents, &h.ChangedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs, &h.Attempts)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +115,7 @@ func (r *syncHistoryRepository) GetLastSync(ctx context.Context, providerID stri
 
 func (r *syncHistoryRepository) GetAll(ctx context.Context, limit int) ([]entities.SyncHistory, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, error_message, started_at, completed_at, duration_ms
+		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, quarantined_contents, removed_contents, changed_contents, error_message, started_at, completed_at, duration_ms, attempts
 		FROM sync_history ORDER BY started_at DESC LIMIT $1
 	`, limit)
 	if err != nil {
@@ -103,7 +125,7 @@ func (r *syncHistoryRepository) GetAll(ctx context.Context, limit int) ([]entiti
 	var out []entities.SyncHistory
 	for rows.Next() {
 		var h entities.SyncHistory
-		if err := rows.Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs); err != nil {
+		if err := rows.Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.QuarantinedContents, &h.RemovedContents, &h.ChangedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs, &h.Attempts); err != nil {
 			return nil, err
 		}
 		out = append(out, h)
@@ -113,7 +135,7 @@ func (r *syncHistoryRepository) GetAll(ctx context.Context, limit int) ([]entiti
 
 func (r *syncHistoryRepository) List(ctx context.Context, providerID *string, status *entities.SyncStatus, limit, offset int) ([]entities.SyncHistory, error) {
 	q := `
-		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, error_message, started_at, completed_at, duration_ms
+		SELECT id, provider_id, sync_status, total_fetched, new_contents, updated_contents, skipped_contents, failed_contents, quarantined_contents, removed_contents, changed_contents, error_message, started_at, completed_at, duration_ms, attempts
 		FROM sync_history WHERE 1=1
 	`
 	args := []any{}
@@ -138,7 +160,7 @@ func (r *syncHistoryRepository) List(ctx context.Context, providerID *string, st
 	var out []entities.SyncHistory
 	for rows.Next() {
 		var h entities.SyncHistory
-		if err := rows.Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs); err != nil {
+		if err := rows.Scan(&h.ID, &h.ProviderID, &h.SyncStatus, &h.TotalFetched, &h.NewContents, &h.UpdatedContents, &h.SkippedContents, &h.FailedContents, &h.QuarantinedContents, &h.RemovedContents, &h.ChangedContents, &h.ErrorMessage, &h.StartedAt, &h.CompletedAt, &h.DurationMs, &h.Attempts); err != nil {
 			return nil, err
 		}
 		out = append(out, h)
@@ -165,3 +187,11 @@ func (r *syncHistoryRepository) Count(ctx context.Context, providerID *string, s
 	}
 	return total, nil
 }
+
+// attemptsOrEmpty keeps the NOT NULL attempts column valid for runs without requests.
+func attemptsOrEmpty(a []entities.SyncAttempt) []entities.SyncAttempt {
+	if a == nil {
+		return []entities.SyncAttempt{}
+	}
+	return a
+}
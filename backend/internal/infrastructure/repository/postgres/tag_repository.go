@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/repositories"
+)
+
+type tagRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTagRepository(pool *pgxpool.Pool) repositories.TagRepository {
+	return &tagRepository{pool: pool}
+}
+
+func (r *tagRepository) SetContentTags(ctx context.Context, contentID int64, names []string) error {
+	if names == nil {
+		names = []string{}
+	}
+	// A batch runs as one implicit transaction in a single round trip
+	batch := &pgx.Batch{}
+	batch.Queue(`INSERT INTO tags(name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`, names)
+	batch.Queue(`
+		DELETE FROM content_tags
+		WHERE content_id=$1 AND tag_id NOT IN (SELECT id FROM tags WHERE name = ANY($2))
+	`, contentID, names)
+	batch.Queue(`
+		INSERT INTO content_tags(content_id, tag_id)
+		SELECT $1, id FROM tags WHERE name = ANY($2)
+		ON CONFLICT DO NOTHING
+	`, contentID, names)
+	br := r.pool.SendBatch(ctx, batch)
+	defer func() { _ = br.Close() }()
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *tagRepository) ListWithCounts(ctx context.Context, limit int) ([]entities.TagCount, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT t.name, COUNT(*)
+		FROM tags t
+		INNER JOIN content_tags ct ON ct.tag_id = t.id
+		INNER JOIN contents c ON c.id = ct.content_id
+		WHERE c.deleted_at IS NULL
+		GROUP BY t.name
+		ORDER BY COUNT(*) DESC, t.name
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []entities.TagCount{}
+	for rows.Next() {
+		var tc entities.TagCount
+		if err := rows.Scan(&tc.Name, &tc.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, tc)
+	}
+	return out, rows.Err()
+}
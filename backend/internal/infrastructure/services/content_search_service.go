@@ -12,17 +12,17 @@ import (
 	"search_engine/internal/domain/entities"
 	"search_engine/internal/domain/repositories"
 	"search_engine/internal/infrastructure/cache"
-
-	"github.com/redis/go-redis/v9"
+	"search_engine/internal/infrastructure/language"
 )
 
 type ContentSearchService struct {
 	Repo            repositories.ContentRepository
 	HistoryRepo     repositories.SyncHistoryRepository
+	TagRepo         repositories.TagRepository
 	DefaultPageSize int
 	MaxPageSize     int
 	// Optional cache
-	CacheClient  *redis.Client
+	Cache        *cache.Store
 	CacheEnabled bool
 	CacheTTL     time.Duration
 }
@@ -55,26 +55,44 @@ func (s *ContentSearchService) SearchContents(ctx context.Context, req dto.Searc
 	default:
 		// keep default
 	}
+	filters := repositories.ContentFilters{ContentType: ct, Tags: NormalizeTags(req.Tags)}
+	if req.Language != "" {
+		lang := language.Normalize(req.Language)
+		if lang == "" || lang == language.Undetermined {
+			return nil, 0, errors.New("invalid language")
+		}
+		filters.Language = &lang
+	}
+	switch strings.ToLower(req.TagMatch) {
+	case "", "any":
+	case "all":
+		filters.MatchAllTags = true
+	default:
+		return nil, 0, errors.New("invalid tag_match")
+	}
 
 	// Cache key
 	var cached struct {
 		Items []dto.ContentSummaryDTO `json:"items"`
 		Total int64                   `json:"total"`
 	}
-	cacheKey := fmt.Sprintf("sc:%s|%s|%s|%d|%d",
+	cacheKey := fmt.Sprintf("sc:%s|%s|%s|%s|%t|%s|%d|%d",
 		strings.ToLower(strings.TrimSpace(req.Keyword)),
 		strings.ToLower(strings.TrimSpace(req.ContentType)),
 		string(sort),
+		strings.Join(filters.Tags, ","),
+		filters.MatchAllTags,
+		strValue(filters.Language),
 		req.Page,
 		req.PageSize,
 	)
-	if s.CacheEnabled && s.CacheClient != nil && s.CacheTTL > 0 {
-		if ok, _ := cache.GetJSON(ctx, s.CacheClient, cacheKey, &cached); ok {
+	if s.CacheEnabled && s.Cache != nil && s.CacheTTL > 0 {
+		if ok, _ := s.Cache.GetJSON(ctx, cacheKey, &cached); ok {
 			return cached.Items, cached.Total, nil
 		}
 	}
 
-	items, total, err := s.Repo.SearchWithFilters(ctx, req.Keyword, ct, repositories.Pagination{Page: req.Page, PageSize: req.PageSize}, sort)
+	items, total, err := s.Repo.SearchWithFilters(ctx, req.Keyword, filters, repositories.Pagination{Page: req.Page, PageSize: req.PageSize}, sort)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -92,10 +110,12 @@ func (s *ContentSearchService) SearchContents(ctx context.Context, req dto.Searc
 			Score:        score,
 			PublishedAt:  row.Content.PublishedAt,
 			Provider:     row.Content.ProviderID,
+			Tags:         row.Content.Tags,
+			Language:     strValue(row.Content.Language),
 		})
 	}
-	if s.CacheEnabled && s.CacheClient != nil && s.CacheTTL > 0 {
-		_ = cache.SetJSON(ctx, s.CacheClient, cacheKey, struct {
+	if s.CacheEnabled && s.Cache != nil && s.CacheTTL > 0 {
+		_ = s.Cache.SetJSON(ctx, cacheKey, struct {
 			Items []dto.ContentSummaryDTO `json:"items"`
 			Total int64                   `json:"total"`
 		}{Items: out, Total: total}, s.CacheTTL)
@@ -107,9 +127,9 @@ func (s *ContentSearchService) GetContentByID(ctx context.Context, id int64) (*d
 	cacheKey := fmt.Sprintf("content:detail:%d", id)
 
 	// Try cache first if enabled
-	if s.CacheEnabled && s.CacheClient != nil {
+	if s.CacheEnabled && s.Cache != nil {
 		var cached dto.ContentDetailDTO
-		if ok, _ := cache.GetJSON(ctx, s.CacheClient, cacheKey, &cached); ok {
+		if ok, _ := s.Cache.GetJSON(ctx, cacheKey, &cached); ok {
 			return &cached, nil
 		}
 	}
@@ -122,7 +142,7 @@ func (s *ContentSearchService) GetContentByID(ctx context.Context, id int64) (*d
 		return nil, nil
 	}
 	var viewsPtr, likesPtr *int64
-	var rtPtr, reacPtr *int
+	var rtPtr, reacPtr, durPtr, commentsPtr *int
 	if row.Metrics.Views != 0 {
 		v := row.Metrics.Views
 		viewsPtr = &v
@@ -139,6 +159,14 @@ func (s *ContentSearchService) GetContentByID(ctx context.Context, id int64) (*d
 		v := row.Metrics.Reactions
 		reacPtr = &v
 	}
+	if row.Metrics.DurationSeconds != 0 {
+		v := row.Metrics.DurationSeconds
+		durPtr = &v
+	}
+	if row.Metrics.Comments != 0 {
+		v := row.Metrics.Comments
+		commentsPtr = &v
+	}
 	desc := row.Content.Description
 	result := &dto.ContentDetailDTO{
 		ContentSummaryDTO: dto.ContentSummaryDTO{
@@ -151,19 +179,23 @@ func (s *ContentSearchService) GetContentByID(ctx context.Context, id int64) (*d
 			Score:        row.Metrics.FinalScore,
 			PublishedAt:  row.Content.PublishedAt,
 			Provider:     row.Content.ProviderID,
+			Tags:         row.Content.Tags,
+			Language:     strValue(row.Content.Language),
 		},
 		Metrics: dto.MetricsDTO{
-			Views:          viewsPtr,
-			Likes:          likesPtr,
-			ReadingTime:    rtPtr,
-			Reactions:      reacPtr,
-			RecalculatedAt: row.Metrics.RecalculatedAt,
+			Views:           viewsPtr,
+			Likes:           likesPtr,
+			ReadingTime:     rtPtr,
+			Reactions:       reacPtr,
+			DurationSeconds: durPtr,
+			Comments:        commentsPtr,
+			RecalculatedAt:  row.Metrics.RecalculatedAt,
 		},
 	}
 
 	// Cache the result if enabled
-	if s.CacheEnabled && s.CacheClient != nil {
-		_ = cache.SetJSON(ctx, s.CacheClient, cacheKey, result, s.CacheTTL)
+	if s.CacheEnabled && s.Cache != nil {
+		_ = s.Cache.SetJSON(ctx, cacheKey, result, s.CacheTTL)
 	}
 
 	return result, nil
@@ -171,41 +203,40 @@ func (s *ContentSearchService) GetContentByID(ctx context.Context, id int64) (*d
 
 // InvalidateContentCache clears the cache for a specific content item
 func (s *ContentSearchService) InvalidateContentCache(ctx context.Context, id int64) error {
-	if !s.CacheEnabled || s.CacheClient == nil {
+	if !s.CacheEnabled || s.Cache == nil {
 		return nil
 	}
 
 	cacheKey := fmt.Sprintf("content:detail:%d", id)
-	return s.CacheClient.Del(ctx, cacheKey).Err()
+	return s.Cache.Delete(ctx, cacheKey)
 }
 
 // InvalidateSearchCache clears search result caches (pattern-based invalidation)
 func (s *ContentSearchService) InvalidateSearchCache(ctx context.Context) error {
-	if !s.CacheEnabled || s.CacheClient == nil {
+	if !s.CacheEnabled || s.Cache == nil {
 		return nil
 	}
 
-	// Note: Redis doesn't support pattern deletion efficiently in production
-	// This is a simplified implementation for demo purposes
-	// In production, consider using cache tags or separate cache keys with TTL
-
-	// For now, we'll just clear some common search patterns
-	patterns := []string{
-		"search:*",
-		"stats:*",
+	for _, prefix := range []string{"search:", "stats:"} {
+		_ = s.Cache.DeletePrefix(ctx, prefix)
 	}
+	return nil
+}
 
-	for _, pattern := range patterns {
-		keys, err := s.CacheClient.Keys(ctx, pattern).Result()
-		if err != nil {
-			continue
-		}
-		if len(keys) > 0 {
-			s.CacheClient.Del(ctx, keys...)
-		}
+// ListTags returns the most used tags with their content counts, for topic browsing
+func (s *ContentSearchService) ListTags(ctx context.Context, limit int) ([]dto.TagDTO, error) {
+	out := []dto.TagDTO{}
+	if s.TagRepo == nil {
+		return out, nil
 	}
-
-	return nil
+	tags, err := s.TagRepo.ListWithCounts(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		out = append(out, dto.TagDTO{Name: t.Name, Count: t.Count})
+	}
+	return out, nil
 }
 
 func (s *ContentSearchService) GetStats(ctx context.Context) (dto.StatsDTO, error) {
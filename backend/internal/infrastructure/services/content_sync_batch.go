@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/domain/repositories"
+)
+
+func (s *ContentSyncService) batchSize() int {
+	if s.BatchSize > 0 {
+		return s.BatchSize
+	}
+	return defaultSyncBatchSize
+}
+
+// processChunk is processItems for one chunk in batched mode: stored rows are loaded
+// in one query per provider, diffs and scores are computed in memory and every write
+// goes through a single UpsertBatch transaction. When loading or writing fails the
+// chunk is retried item by item, so one bad row cannot fail its neighbours. Repeated
+// provider keys within a chunk count as skipped. Forced runs rewrite and rescore
+// every stored row.
+func (s *ContentSyncService) processChunk(ctx context.Context, providerID string, items []domainp.ProviderContent, res *SyncResult) {
+	unique := make([]domainp.ProviderContent, 0, len(items))
+	idsByProvider := map[string][]string{}
+	seen := make(map[[2]string]struct{}, len(items))
+	for _, pc := range items {
+		key := [2]string{pc.ProviderID, pc.ProviderContentID}
+		if _, ok := seen[key]; ok {
+			res.SkippedContents++
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, pc)
+		idsByProvider[pc.ProviderID] = append(idsByProvider[pc.ProviderID], pc.ProviderContentID)
+	}
+
+	stored := make(map[[2]string]repositories.ContentWithMetrics, len(unique))
+	for pid, ids := range idsByProvider {
+		rows, err := s.Batch.GetByProviderKeys(ctx, pid, ids)
+		if err != nil {
+			s.Logger.Warn("batch load failed, syncing chunk item by item", zap.String("provider", providerID), zap.Int("items", len(unique)), zap.Error(err))
+			s.processEach(ctx, providerID, unique, res)
+			return
+		}
+		for _, row := range rows {
+			stored[[2]string{row.Content.ProviderID, row.Content.ProviderContentID}] = row
+		}
+	}
+
+	now := time.Now().UTC()
+	batch := make([]entities.Content, 0, len(unique))
+	pending := make([]domainp.ProviderContent, 0, len(unique))
+	force := isForced(ctx)
+	var created, updated, modified, skipped int
+	for _, pc := range unique {
+		row, exists := stored[[2]string{pc.ProviderID, pc.ProviderContentID}]
+		var c entities.Content
+		isNew, changed, attrsChanged := !exists, false, false
+		if isNew {
+			c = contentOf(&pc)
+			m := metricsOf(&pc)
+			c.Metrics = &m
+		} else {
+			c = row.Content
+			attrsChanged = applyAttributes(&c, &pc)
+			applyLanguage(&c, &pc)
+			next := metricsOf(&pc)
+			m := row.Metrics
+			// A content without a metrics row gets one, as if every metric changed
+			if force || row.Metrics.ContentID == 0 || HasMetricsChanged(snapshotOf(row.Metrics), snapshotOf(next), s.Thresholds) {
+				m.Views, m.Likes, m.ReadingTime = next.Views, next.Likes, next.ReadingTime
+				m.Reactions, m.DurationSeconds, m.Comments = next.Reactions, next.DurationSeconds, next.Comments
+				c.Metrics = &m
+				changed = true
+			} else if attrsChanged {
+				// Type and publish date feed the score, so the stored metrics are rescored
+				c.Metrics = &m
+				changed = true
+			}
+		}
+		if c.Metrics != nil {
+			score, err := s.ScoreCalc.Engine.CalculateScore(&c, c.Metrics)
+			if err != nil {
+				res.FailedContents++
+				s.Logger.Error("score calculation failed", zap.String("provider", providerID), zap.String("provider_content_id", pc.ProviderContentID), zap.Error(err))
+				continue
+			}
+			c.Metrics.FinalScore = score
+			c.Metrics.RecalculatedAt = &now
+		}
+		c.Tags = NormalizeTags(pc.Tags)
+		batch = append(batch, c)
+		pending = append(pending, pc)
+		switch {
+		case isNew:
+			created++
+		case changed:
+			updated++
+			if attrsChanged {
+				modified++
+			}
+		default:
+			skipped++
+		}
+	}
+
+	if err := s.Batch.UpsertBatch(ctx, batch); err != nil {
+		s.Logger.Warn("batch write failed, syncing chunk item by item", zap.String("provider", providerID), zap.Int("items", len(pending)), zap.Error(err))
+		s.processEach(ctx, providerID, pending, res)
+		return
+	}
+	res.NewContents += created
+	res.UpdatedContents += updated
+	res.ChangedContents += modified
+	res.SkippedContents += skipped
+	s.Logger.Debug("sync batch stored", zap.String("provider", providerID), zap.Int("items", len(batch)), zap.Int("new", created), zap.Int("updated", updated))
+}
+
+func snapshotOf(m entities.ContentMetrics) MetricsSnapshot {
+	return MetricsSnapshot{
+		Views:           m.Views,
+		Likes:           m.Likes,
+		ReadingTime:     m.ReadingTime,
+		Reactions:       m.Reactions,
+		DurationSeconds: m.DurationSeconds,
+		Comments:        m.Comments,
+	}
+}
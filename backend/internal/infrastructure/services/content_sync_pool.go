@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// ProviderIDs returns the IDs of every registered provider.
+func (s *ContentSyncService) ProviderIDs() []string {
+	ps := s.Factory.GetAllProviders()
+	ids := make([]string, 0, len(ps))
+	for _, p := range ps {
+		ids = append(ids, p.GetProviderID())
+	}
+	return ids
+}
+
+// SyncProviders syncs ids on up to Concurrency workers, each provider under its own
+// ProviderTimeout, so a slow provider holds up one worker rather than the whole run.
+// Results are returned in the order of ids; onResult, when set, receives each one as
+// its provider finishes, one call at a time.
+func (s *ContentSyncService) SyncProviders(ctx context.Context, ids []string, onResult func(SyncResult)) []SyncResult {
+	results := make([]SyncResult, len(ids))
+	workers := s.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+	next := make(chan int)
+	var (
+		wg     sync.WaitGroup
+		report sync.Mutex
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				res := s.syncOne(ctx, ids[i])
+				results[i] = res
+				if onResult != nil {
+					report.Lock()
+					onResult(res)
+					report.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range ids {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// syncOne runs SyncProvider under the provider's own timeout. Its errors are already
+// recorded on the result and in sync history.
+func (s *ContentSyncService) syncOne(ctx context.Context, providerID string) SyncResult {
+	if s.ProviderTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.ProviderTimeout)
+		defer cancel()
+	}
+	res, _ := s.SyncProvider(ctx, providerID)
+	return res
+}
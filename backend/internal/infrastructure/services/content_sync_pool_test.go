@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+)
+
+func TestContentSyncService_SyncProvidersConcurrently(t *testing.T) {
+	svc := &ContentSyncService{
+		Logger:          zap.NewNop(),
+		Factory:         &fakeFactory{ids: []string{"slow", "a", "b", "c"}},
+		ProviderClient:  &fakeProviderClient{hang: "slow"},
+		HistoryRepo:     &noopHistoryRepo{},
+		Concurrency:     2,
+		ProviderTimeout: 200 * time.Millisecond,
+	}
+	var finished []string
+	results := svc.SyncProviders(context.Background(), svc.ProviderIDs(), func(r SyncResult) {
+		finished = append(finished, r.ProviderID)
+	})
+	if len(results) != 4 || len(finished) != 4 {
+		t.Fatalf("expected 4 results, got %d (%v reported)", len(results), finished)
+	}
+	for i, id := range []string{"slow", "a", "b", "c"} {
+		if results[i].ProviderID != id {
+			t.Fatalf("expected results in provider order, got %s at %d", results[i].ProviderID, i)
+		}
+	}
+	// The other providers finish on the second worker while slow runs into its timeout
+	if finished[3] != "slow" {
+		t.Fatalf("expected slow provider reported last, got %v", finished)
+	}
+	if len(results[0].Errors) == 0 || len(results[1].Errors) != 0 {
+		t.Fatalf("expected only the slow provider to fail, got %+v", results)
+	}
+}
+
+func TestContentSyncService_RecordsProviderTimeoutInHistory(t *testing.T) {
+	history := &recordingHistoryRepo{}
+	svc := &ContentSyncService{
+		Logger:          zap.NewNop(),
+		Factory:         &fakeFactory{ids: []string{"slow"}},
+		ProviderClient:  &fakeProviderClient{hang: "slow"},
+		HistoryRepo:     history,
+		ProviderTimeout: 50 * time.Millisecond,
+	}
+	results := svc.SyncProviders(context.Background(), []string{"slow"}, nil)
+	if len(results[0].Errors) == 0 {
+		t.Fatalf("expected the slow provider to fail, got %+v", results[0])
+	}
+	if history.last.SyncStatus != entities.SyncStatusFailed || history.last.CompletedAt == nil {
+		t.Fatalf("expected the timed out run recorded as failed, got %+v", history.last)
+	}
+}
@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -9,6 +12,9 @@ import (
 	"search_engine/internal/domain/entities"
 	domainp "search_engine/internal/domain/providers"
 	"search_engine/internal/domain/repositories"
+	"search_engine/internal/infrastructure/circuitbreaker"
+	"search_engine/internal/infrastructure/language"
+	"search_engine/internal/infrastructure/ratelimiter"
 )
 
 type SyncResult struct {
@@ -21,6 +27,39 @@ type SyncResult struct {
 	Duration        time.Duration
 	Errors          []string
 	SyncedAt        time.Time
+	// NotModified is set when the provider reported no changes since the stored cursor
+	NotModified bool
+	// SkipReason explains why a run was recorded as skipped or rate limited
+	SkipReason string
+	// RateLimited is set when the provider's rate limit refused the fetch
+	RateLimited bool
+	// Retries counts provider requests repeated after a transient failure
+	Retries int
+	// Quarantined counts items that failed validation and were quarantined
+	Quarantined int
+	// RemovedContents counts stored items soft-deleted because the provider stopped returning them
+	RemovedContents int
+	// RestoredContents counts removed items restored because the provider returned them again
+	RestoredContents int
+	// ChangedContents counts updated items whose title, type, description, URLs or
+	// publish date changed at the provider
+	ChangedContents int
+	// Forced is set when the run ignored the cursor and rewrote every item
+	Forced bool
+}
+
+type forceKey struct{}
+
+// WithForce returns a context whose syncs fetch in full, ignoring the stored cursor,
+// and rewrite every item's attributes and metrics and recalculate its score, whether
+// or not anything changed or crossed MetricsThresholds.
+func WithForce(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceKey{}, true)
+}
+
+func isForced(ctx context.Context) bool {
+	f, _ := ctx.Value(forceKey{}).(bool)
+	return f
 }
 
 type IContentSyncService interface {
@@ -43,22 +82,66 @@ type ContentSyncService struct {
 	Metrics     repositories.ContentMetricsRepository
 	ScoreCalc   *ScoreCalculatorService
 	HistoryRepo repositories.SyncHistoryRepository
-	Thresholds  MetricsThresholds
+	// TagRepo is optional; when set, provider tags are stored for every synced item
+	TagRepo repositories.TagRepository
+	// CursorRepo is optional; when set, providers are fetched incrementally from the stored cursor
+	CursorRepo repositories.SyncCursorRepository
+	// Quarantine is optional; when set, items failing validation are kept there for
+	// review instead of being counted as failed
+	Quarantine repositories.QuarantineRepository
+	// Batch is optional; when set, items are loaded and stored BatchSize at a time,
+	// one transaction per chunk, instead of item by item
+	Batch      repositories.ContentBatchRepository
+	BatchSize  int
+	Thresholds MetricsThresholds
+	// RemovalMisses is how many complete fetches in a row a stored item must be missing
+	// from before it is soft-deleted; zero disables removal detection
+	RemovalMisses int
+	// Concurrency is how many providers a multi-provider sync runs at once; values
+	// below 2 sync them one after another
+	Concurrency int
+	// ProviderTimeout bounds each provider's sync within a multi-provider run; zero
+	// leaves it to the caller's context
+	ProviderTimeout time.Duration
+}
+
+// incrementalClient is implemented by ProviderService; other clients always fetch in full.
+type incrementalClient interface {
+	FetchIncremental(ctx context.Context, providerID string, cur domainp.Cursor) (domainp.FetchResult, error)
+}
+
+// streamingClient is implemented by ProviderService; it hands items over as the
+// provider decodes them.
+type streamingClient interface {
+	StreamFromProvider(ctx context.Context, providerID string, yield func(domainp.ProviderContent) error) error
 }
 
+// streamBatchSize is the number of streamed items screened and stored at a time
+const streamBatchSize = 100
+
+// defaultSyncBatchSize is the chunk size of batched syncs when BatchSize is unset
+const defaultSyncBatchSize = 500
+
 func (s *ContentSyncService) SyncAllProviders(ctx context.Context) ([]SyncResult, error) {
-	providers := s.Factory.GetAllProviders()
-	results := make([]SyncResult, 0, len(providers))
-	for _, p := range providers {
-		res, _ := s.SyncProvider(ctx, p.GetProviderID())
-		results = append(results, res)
+	return s.SyncProviders(ctx, s.ProviderIDs(), nil), nil
+}
+
+// SyncDueProviders syncs the providers whose last run started at least
+// intervalOf(providerID) ago, or that have never run.
+func (s *ContentSyncService) SyncDueProviders(ctx context.Context, intervalOf func(providerID string) time.Duration) ([]SyncResult, error) {
+	var due []string
+	for _, id := range s.ProviderIDs() {
+		if last, err := s.HistoryRepo.GetLastSync(ctx, id); err == nil && last != nil && time.Since(last.StartedAt) < intervalOf(id) {
+			continue
+		}
+		due = append(due, id)
 	}
-	return results, nil
+	return s.SyncProviders(ctx, due, nil), nil
 }
 
 func (s *ContentSyncService) SyncProvider(ctx context.Context, providerID string) (SyncResult, error) {
 	start := time.Now().UTC()
-	res := SyncResult{ProviderID: providerID, SyncedAt: start}
+	res := SyncResult{ProviderID: providerID, SyncedAt: start, Forced: isForced(ctx)}
 	h := entities.SyncHistory{
 		ProviderID: providerID,
 		SyncStatus: entities.SyncStatusInProgress,
@@ -68,78 +151,413 @@ func (s *ContentSyncService) SyncProvider(ctx context.Context, providerID string
 		s.Logger.Warn("failed to create sync history", zap.String("provider", providerID), zap.Error(err))
 	}
 
-	items, err := s.ProviderClient.FetchFromProvider(ctx, providerID)
-	if err != nil {
-		msg := "fetch failed: " + err.Error()
-		s.Logger.Error("provider fetch failed", zap.String("provider", providerID), zap.Error(err))
-		res.Errors = append(res.Errors, msg)
-		res.FailedContents = 0
+	if sc, ok := s.streamSource(providerID); ok {
+		return s.syncStream(ctx, sc, &h, res)
+	}
+	attempts, partial := &attemptLog{}, &partialFetch{}
+	fetchCtx := domainp.WithPartialRecorder(domainp.WithAttemptRecorder(ctx, attempts.record), partial.record)
+	fetched, cursor, err := s.fetch(fetchCtx, providerID)
+	h.Attempts = attempts.list()
+	res.Retries = attempts.retries()
+	items := fetched.Items
+	if errors.Is(err, ratelimiter.ErrRateLimited) {
+		s.finishRateLimited(ctx, &h, &res, err)
+		return res, nil
+	}
+	if errors.Is(err, circuitbreaker.ErrOpen) {
+		// The provider was not called; record why instead of counting a failure
+		res.SkipReason = err.Error()
 		res.Duration = time.Since(start)
-		res.TotalFetched = 0
-		h.SyncStatus = entities.SyncStatusFailed
-		now := time.Now().UTC()
-		h.CompletedAt = &now
-		h.ErrorMessage = &msg
-		h.DurationMs = int(res.Duration.Milliseconds())
-		s.persistHistory(ctx, &h)
+		s.finishSkipped(ctx, &h, res.SkipReason, res.Duration)
+		s.Logger.Info("sync skipped, circuit open", zap.String("provider", providerID))
+		return res, nil
+	}
+	if err != nil {
+		s.failFetch(ctx, &h, &res, err)
 		return res, err
 	}
+	if fetched.NotModified {
+		res.NotModified = true
+		res.SkipReason = "not modified since last sync"
+		res.Duration = time.Since(start)
+		s.finishSkipped(ctx, &h, res.SkipReason, res.Duration)
+		s.Logger.Info("sync skipped, provider not modified", zap.String("provider", providerID))
+		return res, nil
+	}
 	res.TotalFetched = len(items)
+	s.recordRowErrors(providerID, fetched.RowErrors, &res)
+	seen := providerContentIDs(items)
+	items = s.screen(ctx, providerID, items, &res)
+	s.processItems(ctx, providerID, items, &res)
+	s.reconcile(ctx, providerID, seen, partial.reason(), &res)
+	s.complete(ctx, &h, &res, start)
+	// Only a clean run may advance the cursor, otherwise failed items would never be retried
+	if cursor != nil && res.FailedContents == 0 && len(res.Errors) == 0 {
+		var newest *time.Time
+		for _, it := range fetched.Items {
+			newest = laterPublished(newest, it)
+		}
+		s.advanceCursor(ctx, cursor, fetched.Cursor, newest)
+	}
+	s.Logger.Info("sync completed", zap.String("provider", providerID), zap.Int("fetched", res.TotalFetched), zap.Int("retries", res.Retries), zap.Duration("duration", res.Duration))
+	return res, nil
+}
+
+// streamSource returns the client to stream providerID from. Providers fetched
+// incrementally keep the slice path, which carries their conditional-request cursor.
+func (s *ContentSyncService) streamSource(providerID string) (streamingClient, bool) {
+	sc, ok := s.ProviderClient.(streamingClient)
+	if !ok {
+		return nil, false
+	}
+	p, err := s.Factory.GetProviderByID(providerID)
+	if err != nil {
+		return nil, false
+	}
+	if _, ok := p.(domainp.StreamingProvider); !ok {
+		return nil, false
+	}
+	if _, ok := p.(domainp.IncrementalProvider); ok && s.CursorRepo != nil {
+		return nil, false
+	}
+	return sc, true
+}
 
+// syncStream is SyncProvider for streaming providers: items are screened and stored
+// in batches of streamBatchSize, or BatchSize when batching, while the provider is
+// still decoding, so memory does not grow with the feed. Items stored before a fetch
+// error are kept and the run is recorded as partial.
+func (s *ContentSyncService) syncStream(ctx context.Context, sc streamingClient, h *entities.SyncHistory, res SyncResult) (SyncResult, error) {
+	providerID, start := res.ProviderID, res.SyncedAt
+	var cursor *entities.SyncCursor
+	if s.CursorRepo != nil {
+		cursor = s.loadCursor(ctx, providerID)
+	}
+	var newest *time.Time
+	var screened screening
+	flushAt := streamBatchSize
+	if s.Batch != nil {
+		flushAt = s.batchSize()
+	}
+	batch := make([]domainp.ProviderContent, 0, flushAt)
+	flush := func() {
+		valid := s.screenBatch(ctx, providerID, batch, &res, &screened)
+		s.processItems(ctx, providerID, valid, &res)
+		batch = batch[:0]
+	}
+	var seen []string
+	attempts, partial := &attemptLog{}, &partialFetch{}
+	streamCtx := domainp.WithPartialRecorder(domainp.WithAttemptRecorder(ctx, attempts.record), partial.record)
+	err := sc.StreamFromProvider(streamCtx, providerID, func(pc domainp.ProviderContent) error {
+		res.TotalFetched++
+		newest = laterPublished(newest, pc)
+		if pc.ProviderContentID != "" {
+			seen = append(seen, pc.ProviderContentID)
+		}
+		batch = append(batch, pc)
+		if len(batch) == flushAt {
+			flush()
+		}
+		return nil
+	})
+	if len(batch) > 0 {
+		flush()
+	}
+	s.finishScreening(providerID, &res, &screened)
+	h.Attempts = attempts.list()
+	res.Retries = attempts.retries()
+	if errors.Is(err, ratelimiter.ErrRateLimited) && res.TotalFetched == 0 {
+		s.finishRateLimited(ctx, h, &res, err)
+		return res, nil
+	}
+	if errors.Is(err, circuitbreaker.ErrOpen) {
+		res.SkipReason = err.Error()
+		res.Duration = time.Since(start)
+		s.finishSkipped(ctx, h, res.SkipReason, res.Duration)
+		s.Logger.Info("sync skipped, circuit open", zap.String("provider", providerID))
+		return res, nil
+	}
+	if err != nil && res.TotalFetched == 0 {
+		s.failFetch(ctx, h, &res, err)
+		return res, err
+	}
+	if err != nil {
+		s.Logger.Error("provider stream failed", zap.String("provider", providerID), zap.Int("fetched", res.TotalFetched), zap.Error(err))
+		res.Errors = append(res.Errors, "fetch failed: "+err.Error())
+	}
+	s.reconcile(ctx, providerID, seen, partial.reason(), &res)
+	s.complete(ctx, h, &res, start)
+	if cursor != nil && res.FailedContents == 0 && len(res.Errors) == 0 {
+		s.advanceCursor(ctx, cursor, domainp.Cursor{ETag: cursor.ETag, LastModified: cursor.LastModified}, newest)
+	}
+	s.Logger.Info("sync completed", zap.String("provider", providerID), zap.Int("fetched", res.TotalFetched), zap.Int("retries", res.Retries), zap.Bool("streamed", true), zap.Duration("duration", res.Duration))
+	return res, err
+}
+
+// failFetch records a run whose fetch failed before any item was processed.
+func (s *ContentSyncService) failFetch(ctx context.Context, h *entities.SyncHistory, res *SyncResult, err error) {
+	msg := "fetch failed: " + err.Error()
+	s.Logger.Error("provider fetch failed", zap.String("provider", res.ProviderID), zap.Error(err))
+	res.Errors = append(res.Errors, msg)
+	res.FailedContents = 0
+	res.Duration = time.Since(res.SyncedAt)
+	res.TotalFetched = 0
+	h.SyncStatus = entities.SyncStatusFailed
+	now := time.Now().UTC()
+	h.CompletedAt = &now
+	h.ErrorMessage = &msg
+	h.DurationMs = int(res.Duration.Milliseconds())
+	s.persistHistory(ctx, h)
+}
+
+// IngestBatch runs items pushed by a provider through the same pipeline as
+// SyncProvider and records the run in sync history. Nothing is fetched and the
+// sync cursor is left alone; items failing validation count as failed contents.
+func (s *ContentSyncService) IngestBatch(ctx context.Context, providerID string, items []domainp.ProviderContent) (SyncResult, error) {
+	start := time.Now().UTC()
+	res := SyncResult{ProviderID: providerID, SyncedAt: start, TotalFetched: len(items)}
+	h := entities.SyncHistory{
+		ProviderID: providerID,
+		SyncStatus: entities.SyncStatusInProgress,
+		StartedAt:  start,
+	}
+	if err := s.HistoryRepo.Create(ctx, &h); err != nil {
+		s.Logger.Warn("failed to create sync history", zap.String("provider", providerID), zap.Error(err))
+	}
+	pushed := make([]domainp.ProviderContent, len(items))
+	for i, pc := range items {
+		// The path decides the provider; a batch cannot write into another provider's contents
+		pc.ProviderID = providerID
+		pushed[i] = pc
+	}
+	valid := s.screen(ctx, providerID, pushed, &res)
+	s.processItems(ctx, providerID, valid, &res)
+	// A push carries only some items, so it restores reappearing items but never removes any
+	s.restoreSeen(ctx, providerID, providerContentIDs(pushed), &res)
+	s.complete(ctx, &h, &res, start)
+	s.Logger.Info("ingest completed", zap.String("provider", providerID), zap.Int("items", len(items)), zap.Int("quarantined", res.Quarantined), zap.Duration("duration", res.Duration))
+	return res, nil
+}
+
+// processItems stores new items and updates existing ones whose attributes changed
+// or whose metrics changed beyond the thresholds, counting each outcome on res.
+// Forced runs update every existing item.
+func (s *ContentSyncService) processItems(ctx context.Context, providerID string, items []domainp.ProviderContent, res *SyncResult) {
+	if s.Batch == nil {
+		s.processEach(ctx, providerID, items, res)
+		return
+	}
+	size := s.batchSize()
+	for start := 0; start < len(items); start += size {
+		s.processChunk(ctx, providerID, items[start:min(start+size, len(items))], res)
+	}
+}
+
+// processEach is processItems one item at a time.
+func (s *ContentSyncService) processEach(ctx context.Context, providerID string, items []domainp.ProviderContent, res *SyncResult) {
+	force := isForced(ctx)
 	for _, pc := range items {
-		// check existing
-		existing, err := s.Contents.GetByProviderKey(ctx, pc.ProviderID, pc.ProviderContentID)
-		if err == nil && existing != nil {
-			// compare metrics
-			oldM, err := s.Metrics.GetByContentID(ctx, existing.ID)
-			if err != nil {
-				res.FailedContents++
-				s.Logger.Warn("metrics fetch failed", zap.Int64("content_id", existing.ID), zap.Error(err))
-				continue
-			}
-			newSnap := MetricsSnapshot{
-				Views:       val64(pc.Views),
-				Likes:       val64(pc.Likes),
-				ReadingTime: valInt(pc.ReadingTime),
-				Reactions:   valInt(pc.Reactions),
-			}
-			oldSnap := MetricsSnapshot{
-				Views:       oldM.Views,
-				Likes:       oldM.Likes,
-				ReadingTime: oldM.ReadingTime,
-				Reactions:   oldM.Reactions,
-			}
-			if HasMetricsChanged(oldSnap, newSnap, s.Thresholds) {
-				oldM.Views = newSnap.Views
-				oldM.Likes = newSnap.Likes
-				oldM.ReadingTime = newSnap.ReadingTime
-				oldM.Reactions = newSnap.Reactions
-				if err := s.Metrics.UpdateByContentID(ctx, existing.ID, oldM); err != nil {
-					res.FailedContents++
-					s.Logger.Error("metrics update failed", zap.Int64("content_id", existing.ID), zap.Error(err))
-					continue
-				}
-				// recalc score
-				if _, err := s.ScoreCalc.RecalculateScore(ctx, existing.ID); err != nil {
+		_ = s.processOne(ctx, providerID, pc, force, res)
+	}
+}
+
+// processOne stores a single item and counts it on res. A failed item is logged,
+// counted and its error returned.
+func (s *ContentSyncService) processOne(ctx context.Context, providerID string, pc domainp.ProviderContent, force bool, res *SyncResult) error {
+	// check existing
+	existing, err := s.Contents.GetByProviderKey(ctx, pc.ProviderID, pc.ProviderContentID)
+	if err == nil && existing != nil {
+		s.syncTags(ctx, existing.ID, pc.Tags)
+		attrsChanged := applyAttributes(existing, &pc)
+		langChanged := applyLanguage(existing, &pc)
+		if attrsChanged || langChanged || force {
+			if err := s.Contents.Update(ctx, existing); err != nil {
+				if attrsChanged || force {
 					res.FailedContents++
-					s.Logger.Error("score recalc failed", zap.Int64("content_id", existing.ID), zap.Error(err))
-					continue
+					s.Logger.Error("content update failed", zap.Int64("content_id", existing.ID), zap.Error(err))
+					return fmt.Errorf("update content: %w", err)
 				}
-				res.UpdatedContents++
-			} else {
-				res.SkippedContents++
+				// A missed language backfill is retried on the next sync
+				s.Logger.Warn("content language update failed", zap.Int64("content_id", existing.ID), zap.Error(err))
 			}
-			continue
 		}
-		// new content
-		if _, _, err := s.ScoreCalc.ProcessNewContent(ctx, &pc); err != nil {
+		// compare metrics
+		oldM, err := s.Metrics.GetByContentID(ctx, existing.ID)
+		if err != nil {
 			res.FailedContents++
-			s.Logger.Error("new content processing failed", zap.String("provider", providerID), zap.Error(err))
+			s.Logger.Warn("metrics fetch failed", zap.Int64("content_id", existing.ID), zap.Error(err))
+			return fmt.Errorf("fetch metrics: %w", err)
+		}
+		newSnap := snapshotOf(metricsOf(&pc))
+		metricsChanged := force || HasMetricsChanged(snapshotOf(*oldM), newSnap, s.Thresholds)
+		if metricsChanged {
+			oldM.Views = newSnap.Views
+			oldM.Likes = newSnap.Likes
+			oldM.ReadingTime = newSnap.ReadingTime
+			oldM.Reactions = newSnap.Reactions
+			oldM.DurationSeconds = newSnap.DurationSeconds
+			oldM.Comments = newSnap.Comments
+			if err := s.Metrics.UpdateByContentID(ctx, existing.ID, oldM); err != nil {
+				res.FailedContents++
+				s.Logger.Error("metrics update failed", zap.Int64("content_id", existing.ID), zap.Error(err))
+				return fmt.Errorf("update metrics: %w", err)
+			}
+		}
+		if !metricsChanged && !attrsChanged {
+			res.SkippedContents++
+			return nil
+		}
+		// Type and publish date feed the score, so attribute changes rescore too
+		if _, err := s.ScoreCalc.RecalculateScore(ctx, existing.ID); err != nil {
+			res.FailedContents++
+			s.Logger.Error("score recalc failed", zap.Int64("content_id", existing.ID), zap.Error(err))
+			return fmt.Errorf("recalculate score: %w", err)
+		}
+		res.UpdatedContents++
+		if attrsChanged {
+			res.ChangedContents++
+		}
+		return nil
+	}
+	// new content
+	id, _, err := s.ScoreCalc.ProcessNewContent(ctx, &pc)
+	if err != nil {
+		res.FailedContents++
+		s.Logger.Error("new content processing failed", zap.String("provider", providerID), zap.Error(err))
+		return fmt.Errorf("create content: %w", err)
+	}
+	s.syncTags(ctx, id, pc.Tags)
+	res.NewContents++
+	return nil
+}
+
+// applyAttributes copies the provider's title, type, description, URLs and publish
+// date onto existing and reports whether any of them changed. Language is left to
+// applyLanguage. Publish dates are compared at the microsecond precision Postgres keeps.
+func applyAttributes(existing *entities.Content, pc *domainp.ProviderContent) bool {
+	next := entities.Content{
+		Title:        pc.Title,
+		ContentType:  mapContentType(pc.ContentType),
+		Description:  strPtrOrNil(pc.Description),
+		URL:          strPtrOrNil(pc.URL),
+		ThumbnailURL: strPtrOrNil(pc.ThumbnailURL),
+		PublishedAt:  timePtrOrNil(pc.PublishedAt),
+	}
+	changed := existing.Title != next.Title ||
+		existing.ContentType != next.ContentType ||
+		strValue(existing.Description) != strValue(next.Description) ||
+		strValue(existing.URL) != strValue(next.URL) ||
+		strValue(existing.ThumbnailURL) != strValue(next.ThumbnailURL) ||
+		!sameInstant(existing.PublishedAt, next.PublishedAt)
+	if !changed {
+		return false
+	}
+	existing.Title, existing.ContentType = next.Title, next.ContentType
+	existing.Description, existing.URL, existing.ThumbnailURL = next.Description, next.URL, next.ThumbnailURL
+	existing.PublishedAt = next.PublishedAt
+	return true
+}
+
+func sameInstant(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Truncate(time.Microsecond).Equal(b.Truncate(time.Microsecond))
+}
+
+// applyLanguage sets the language of existing from pc and reports whether it changed.
+// Stored languages are only replaced by a different one the provider sets.
+func applyLanguage(existing *entities.Content, pc *domainp.ProviderContent) bool {
+	if existing.Language != nil {
+		if set := language.Normalize(pc.Language); set == "" || set == *existing.Language {
+			return false
+		}
+	}
+	lang := contentLanguage(pc)
+	existing.Language = &lang
+	return true
+}
+
+// reconcile restores the seen items that had been removed, then counts a miss on the
+// provider's stored contents a complete fetch did not return and soft-deletes the ones
+// missing for RemovalMisses syncs in a row. Nothing is counted as missing when the
+// fetch was partial, failed anywhere or returned no items at all; an empty feed is
+// far more likely an outage than the removal of everything.
+func (s *ContentSyncService) reconcile(ctx context.Context, providerID string, seen []string, partial string, res *SyncResult) {
+	s.restoreSeen(ctx, providerID, seen, res)
+	if s.RemovalMisses <= 0 {
+		return
+	}
+	if partial != "" || len(res.Errors) > 0 || len(seen) == 0 {
+		s.Logger.Debug("removal check skipped", zap.String("provider", providerID), zap.String("partial", partial), zap.Int("errors", len(res.Errors)), zap.Int("seen", len(seen)))
+		return
+	}
+	ids, err := s.Contents.MarkMissing(ctx, providerID, seen, s.RemovalMisses)
+	if err != nil {
+		s.Logger.Warn("removal check failed", zap.String("provider", providerID), zap.Error(err))
+		return
+	}
+	for _, id := range ids {
+		if err := s.Contents.SoftDelete(ctx, id); err != nil {
+			s.Logger.Warn("removing content failed", zap.Int64("content_id", id), zap.Error(err))
 			continue
 		}
-		res.NewContents++
+		res.RemovedContents++
+	}
+	if res.RemovedContents > 0 {
+		s.Logger.Info("removed contents missing upstream", zap.String("provider", providerID), zap.Int("removed", res.RemovedContents))
 	}
+}
 
+// restoreSeen clears the misses of seen items and restores the ones removed as missing.
+func (s *ContentSyncService) restoreSeen(ctx context.Context, providerID string, seen []string, res *SyncResult) {
+	if len(seen) == 0 {
+		return
+	}
+	n, err := s.Contents.RestoreSeen(ctx, providerID, seen)
+	if err != nil {
+		s.Logger.Warn("restoring reappeared contents failed", zap.String("provider", providerID), zap.Error(err))
+		return
+	}
+	res.RestoredContents += n
+	if n > 0 {
+		s.Logger.Info("restored contents returned upstream", zap.String("provider", providerID), zap.Int("restored", n))
+	}
+}
+
+// providerContentIDs returns the non-empty provider content IDs of items.
+func providerContentIDs(items []domainp.ProviderContent) []string {
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		if it.ProviderContentID != "" {
+			ids = append(ids, it.ProviderContentID)
+		}
+	}
+	return ids
+}
+
+// partialFetch keeps the first reason a provider gave for returning less than its full feed.
+type partialFetch struct {
+	mu    sync.Mutex
+	first string
+}
+
+func (p *partialFetch) record(reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.first == "" {
+		p.first = reason
+	}
+}
+
+func (p *partialFetch) reason() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.first
+}
+
+// complete derives the run status from res and persists the history row.
+func (s *ContentSyncService) complete(ctx context.Context, h *entities.SyncHistory, res *SyncResult, start time.Time) {
 	res.Duration = time.Since(start)
 	status := entities.SyncStatusSuccess
 	if len(res.Errors) > 0 {
@@ -156,15 +574,16 @@ func (s *ContentSyncService) SyncProvider(ctx context.Context, providerID string
 	h.UpdatedContents = res.UpdatedContents
 	h.SkippedContents = res.SkippedContents
 	h.FailedContents = res.FailedContents
+	h.QuarantinedContents = res.Quarantined
+	h.RemovedContents = res.RemovedContents
+	h.ChangedContents = res.ChangedContents
 	h.CompletedAt = &now
 	if len(res.Errors) > 0 {
 		msg := res.Errors[0]
 		h.ErrorMessage = &msg
 	}
 	h.DurationMs = int(res.Duration.Milliseconds())
-	s.persistHistory(ctx, &h)
-	s.Logger.Info("sync completed", zap.String("provider", providerID), zap.Int("fetched", res.TotalFetched), zap.Duration("duration", res.Duration))
-	return res, nil
+	s.persistHistory(ctx, h)
 }
 
 func (s *ContentSyncService) GetLastSyncTime(ctx context.Context, providerID string) (time.Time, error) {
@@ -179,10 +598,178 @@ func (s *ContentSyncService) GetSyncHistory(ctx context.Context, limit int) ([]e
 	return s.HistoryRepo.GetAll(ctx, limit)
 }
 
+// finishSkipped completes a history row for a run that did not process any items.
+func (s *ContentSyncService) finishSkipped(ctx context.Context, h *entities.SyncHistory, reason string, d time.Duration) {
+	now := time.Now().UTC()
+	h.SyncStatus = entities.SyncStatusSkipped
+	h.CompletedAt = &now
+	h.ErrorMessage = &reason
+	h.DurationMs = int(d.Milliseconds())
+	s.persistHistory(ctx, h)
+}
+
+// finishRateLimited records a run the provider's rate limit refused. The provider was
+// not called, so it is neither a failure nor an empty success.
+func (s *ContentSyncService) finishRateLimited(ctx context.Context, h *entities.SyncHistory, res *SyncResult, err error) {
+	res.RateLimited = true
+	res.SkipReason = err.Error()
+	res.Duration = time.Since(res.SyncedAt)
+	now := time.Now().UTC()
+	h.SyncStatus = entities.SyncStatusRateLimited
+	h.CompletedAt = &now
+	h.ErrorMessage = &res.SkipReason
+	h.DurationMs = int(res.Duration.Milliseconds())
+	s.persistHistory(ctx, h)
+	s.Logger.Info("sync skipped, rate limited", zap.String("provider", res.ProviderID), zap.Error(err))
+}
+
+// fetch pulls the provider's items, incrementally when a cursor store is configured
+// and the client supports it. The returned cursor is nil for full fetches.
+func (s *ContentSyncService) fetch(ctx context.Context, providerID string) (domainp.FetchResult, *entities.SyncCursor, error) {
+	ic, ok := s.ProviderClient.(incrementalClient)
+	if !ok {
+		items, err := s.ProviderClient.FetchFromProvider(ctx, providerID)
+		return domainp.FetchResult{Items: items}, nil, err
+	}
+	if s.CursorRepo == nil {
+		// An empty cursor fetches in full and still carries the row errors
+		res, err := ic.FetchIncremental(ctx, providerID, domainp.Cursor{})
+		return res, nil, err
+	}
+	cur := s.loadCursor(ctx, providerID)
+	validators := domainp.Cursor{
+		ETag:         cur.ETag,
+		LastModified: cur.LastModified,
+		Since:        cur.MaxPublishedAt,
+	}
+	if isForced(ctx) {
+		// A forced run asks for everything; the cursor is still advanced afterwards
+		validators = domainp.Cursor{}
+	}
+	res, err := ic.FetchIncremental(ctx, providerID, validators)
+	return res, cur, err
+}
+
+// loadCursor returns the stored cursor, or an empty one when none is stored or it
+// cannot be read.
+func (s *ContentSyncService) loadCursor(ctx context.Context, providerID string) *entities.SyncCursor {
+	cur, err := s.CursorRepo.Get(ctx, providerID)
+	if err != nil {
+		s.Logger.Warn("sync cursor load failed, fetching in full", zap.String("provider", providerID), zap.Error(err))
+		cur = nil
+	}
+	if cur == nil {
+		cur = &entities.SyncCursor{ProviderID: providerID}
+	}
+	return cur
+}
+
+// laterPublished returns the later of newest and the item's published_at.
+func laterPublished(newest *time.Time, it domainp.ProviderContent) *time.Time {
+	if it.PublishedAt.IsZero() || (newest != nil && !it.PublishedAt.After(*newest)) {
+		return newest
+	}
+	t := it.PublishedAt.UTC()
+	return &t
+}
+
+// advanceCursor stores the new validators and the newest published_at seen.
+func (s *ContentSyncService) advanceCursor(ctx context.Context, cur *entities.SyncCursor, validators domainp.Cursor, newest *time.Time) {
+	next := *cur
+	next.ETag = validators.ETag
+	next.LastModified = validators.LastModified
+	if newest != nil && (next.MaxPublishedAt == nil || newest.After(*next.MaxPublishedAt)) {
+		next.MaxPublishedAt = newest
+	}
+	if next.ETag == cur.ETag && next.LastModified == cur.LastModified && next.MaxPublishedAt == cur.MaxPublishedAt {
+		return
+	}
+	if err := s.CursorRepo.Upsert(ctx, &next); err != nil {
+		s.Logger.Warn("sync cursor save failed", zap.String("provider", cur.ProviderID), zap.Error(err))
+	}
+}
+
+// maxRecordedAttempts caps the provider requests kept on a history row
+const maxRecordedAttempts = 200
+
+// attemptLog collects the provider requests of one run for its history row.
+type attemptLog struct {
+	mu      sync.Mutex
+	entries []entities.SyncAttempt
+	retried int
+}
+
+func (l *attemptLog) record(a domainp.RequestAttempt) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if a.Attempt > 1 {
+		l.retried++
+	}
+	if len(l.entries) == maxRecordedAttempts {
+		return
+	}
+	l.entries = append(l.entries, entities.SyncAttempt{
+		URL:        a.URL,
+		Attempt:    a.Attempt,
+		StatusCode: a.StatusCode,
+		Error:      a.Error,
+		StartedAt:  a.StartedAt,
+		DurationMs: int(a.Duration.Milliseconds()),
+		BackoffMs:  int(a.Backoff.Milliseconds()),
+	})
+}
+
+func (l *attemptLog) list() []entities.SyncAttempt {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]entities.SyncAttempt(nil), l.entries...)
+}
+
+func (l *attemptLog) retries() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.retried
+}
+
+// maxReportedRowErrors caps the per-line messages kept on a SyncResult
+const maxReportedRowErrors = 100
+
+// recordRowErrors adds the malformed records a provider skipped to the result as
+// failed contents, one message per line.
+func (s *ContentSyncService) recordRowErrors(providerID string, rowErrs []domainp.RowError, res *SyncResult) {
+	if len(rowErrs) == 0 {
+		return
+	}
+	res.TotalFetched += len(rowErrs)
+	res.FailedContents += len(rowErrs)
+	for i, re := range rowErrs {
+		if i == maxReportedRowErrors {
+			res.Errors = append(res.Errors, fmt.Sprintf("... and %d more malformed rows", len(rowErrs)-i))
+			break
+		}
+		res.Errors = append(res.Errors, "malformed row "+re.String())
+	}
+	s.Logger.Warn("provider skipped malformed rows", zap.String("provider", providerID), zap.Int("rows", len(rowErrs)), zap.String("first", rowErrs[0].String()))
+}
+
+// syncTags stores the normalized provider tags of a content; failures are logged, not fatal
+func (s *ContentSyncService) syncTags(ctx context.Context, contentID int64, tags []string) {
+	if s.TagRepo == nil {
+		return
+	}
+	if err := s.TagRepo.SetContentTags(ctx, contentID, NormalizeTags(tags)); err != nil {
+		s.Logger.Warn("tag sync failed", zap.Int64("content_id", contentID), zap.Error(err))
+	}
+}
+
+// persistHistory writes h even when ctx is done: a run cancelled or over its
+// provider timeout must still end up with its outcome recorded.
 func (s *ContentSyncService) persistHistory(ctx context.Context, h *entities.SyncHistory) {
 	if h == nil {
 		return
 	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
 	var err error
 	if h.ID == 0 {
 		err = s.HistoryRepo.Create(ctx, h)
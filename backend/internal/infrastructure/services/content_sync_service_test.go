@@ -2,6 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,36 +16,141 @@ import (
 	"search_engine/internal/domain/repositories"
 )
 
-type fakeFactory struct{ items []providers.ProviderContent }
+// fakeFactory registers a fakeProvider for each of ids ("provider1" when empty);
+// streaming registers them as StreamingProviders, so syncs stream from the client.
+type fakeFactory struct {
+	items     []providers.ProviderContent
+	ids       []string
+	streaming bool
+}
 
 func (f *fakeFactory) GetAllProviders() []providers.IContentProvider {
-	return []providers.IContentProvider{&fakeProvider{items: f.items}}
+	ids := f.ids
+	if len(ids) == 0 {
+		ids = []string{"provider1"}
+	}
+	out := make([]providers.IContentProvider, 0, len(ids))
+	for _, id := range ids {
+		p, _ := f.GetProviderByID(id)
+		out = append(out, p)
+	}
+	return out
 }
 func (f *fakeFactory) GetProviderByID(id string) (providers.IContentProvider, error) {
-	return &fakeProvider{items: f.items}, nil
+	p := &fakeProvider{id: id, items: f.items}
+	if f.streaming {
+		return &streamingProvider{p}, nil
+	}
+	return p, nil
 }
 
-type fakeProvider struct{ items []providers.ProviderContent }
+type fakeProvider struct {
+	id    string
+	items []providers.ProviderContent
+}
 
-func (p *fakeProvider) FetchContents() ([]providers.ProviderContent, error) { return p.items, nil }
-func (p *fakeProvider) GetProviderID() string                               { return "provider1" }
+func (p *fakeProvider) FetchContents(ctx context.Context) ([]providers.ProviderContent, error) {
+	return p.items, nil
+}
+func (p *fakeProvider) GetProviderID() string {
+	if p.id == "" {
+		return "provider1"
+	}
+	return p.id
+}
 func (p *fakeProvider) GetRateLimit() providers.RateLimit {
 	return providers.RateLimit{RequestsPerMinute: 100}
 }
 
-type fakeProviderClient struct{ items []providers.ProviderContent }
+type streamingProvider struct{ *fakeProvider }
+
+func (p *streamingProvider) StreamContents(ctx context.Context, yield func(providers.ProviderContent) error) error {
+	return errors.New("use the client")
+}
+
+// fakeProviderClient serves items for every provider. The optional fields make a
+// fetch behave like the real providers: skip malformed rows, stop at max pages,
+// retry requests, answer conditional requests, fail or run into its timeout.
+type fakeProviderClient struct {
+	items    []providers.ProviderContent
+	rowErrs  []providers.RowError
+	partial  string // reported through ReportPartial when set
+	attempts []providers.RequestAttempt
+	etag     string // answered with NotModified when presented
+	err      error  // returned instead of the items, or after streaming them
+	hang     string // provider whose fetch blocks until its context ends
+	onYield  func(i int)
+
+	mu    sync.Mutex
+	calls []providers.Cursor
+}
 
 func (f *fakeProviderClient) FetchFromProvider(ctx context.Context, providerID string) ([]providers.ProviderContent, error) {
-	return f.items, nil
+	res, err := f.FetchIncremental(ctx, providerID, providers.Cursor{})
+	return res.Items, err
+}
+
+func (f *fakeProviderClient) FetchIncremental(ctx context.Context, providerID string, cur providers.Cursor) (providers.FetchResult, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, cur)
+	f.mu.Unlock()
+	if err := f.begin(ctx, providerID); err != nil {
+		return providers.FetchResult{}, err
+	}
+	if f.etag != "" && cur.ETag == f.etag {
+		return providers.FetchResult{NotModified: true, Cursor: cur}, nil
+	}
+	if f.err != nil {
+		return providers.FetchResult{}, f.err
+	}
+	return providers.FetchResult{Items: f.items, RowErrors: f.rowErrs, Cursor: providers.Cursor{ETag: f.etag}}, nil
+}
+
+// StreamFromProvider yields the items one at a time, then returns err.
+func (f *fakeProviderClient) StreamFromProvider(ctx context.Context, providerID string, yield func(providers.ProviderContent) error) error {
+	if err := f.begin(ctx, providerID); err != nil {
+		return err
+	}
+	for i, it := range f.items {
+		if f.onYield != nil {
+			f.onYield(i)
+		}
+		if err := yield(it); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+// begin reports the configured attempts and partial reason, and blocks for hang.
+func (f *fakeProviderClient) begin(ctx context.Context, providerID string) error {
+	for _, a := range f.attempts {
+		providers.RecordAttempt(ctx, a)
+	}
+	if f.partial != "" {
+		providers.ReportPartial(ctx, f.partial)
+	}
+	if providerID == f.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
 }
 
 type memContentRepo struct {
-	byKey map[string]*entities.Content
-	all   []*entities.Content
+	byKey   map[string]*entities.Content
+	all     []*entities.Content
+	missed  map[int64]int
+	deleted map[int64]bool
+	// createErr, when set, fails every Create
+	createErr error
 }
 
 func (m *memContentRepo) key(pid, cid string) string { return pid + "|" + cid }
 func (m *memContentRepo) Create(ctx context.Context, c *entities.Content) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
 	if m.byKey == nil {
 		m.byKey = map[string]*entities.Content{}
 	}
@@ -83,7 +192,7 @@ func (m *memContentRepo) ListIDs(ctx context.Context, offset, limit int) ([]int6
 	return nil, nil
 }
 func (m *memContentRepo) CountAll(ctx context.Context) (int64, error) { return int64(len(m.all)), nil }
-func (m *memContentRepo) SearchWithFilters(ctx context.Context, keyword string, contentType *entities.ContentType, pagination repositories.Pagination, sort repositories.SearchSort) ([]repositories.ContentWithMetrics, int64, error) {
+func (m *memContentRepo) SearchWithFilters(ctx context.Context, keyword string, filters repositories.ContentFilters, pagination repositories.Pagination, sort repositories.SearchSort) ([]repositories.ContentWithMetrics, int64, error) {
 	return nil, 0, nil
 }
 func (m *memContentRepo) GetDetailByID(ctx context.Context, id int64) (*repositories.ContentWithMetrics, error) {
@@ -96,7 +205,48 @@ func (m *memContentRepo) GetAverageScore(ctx context.Context) (float64, error) {
 func (m *memContentRepo) CountByProvider(ctx context.Context) (map[string]int64, error) {
 	return map[string]int64{}, nil
 }
-func (m *memContentRepo) SoftDelete(ctx context.Context, id int64) error { return nil }
+func (m *memContentRepo) SoftDelete(ctx context.Context, id int64) error {
+	if m.deleted == nil {
+		m.deleted = map[int64]bool{}
+	}
+	m.deleted[id] = true
+	return nil
+}
+func (m *memContentRepo) MarkMissing(ctx context.Context, providerID string, seen []string, misses int) ([]int64, error) {
+	if m.missed == nil {
+		m.missed = map[int64]int{}
+	}
+	in := map[string]bool{}
+	for _, id := range seen {
+		in[id] = true
+	}
+	var ids []int64
+	for _, c := range m.all {
+		if c.ProviderID != providerID || m.deleted[c.ID] || in[c.ProviderContentID] {
+			continue
+		}
+		m.missed[c.ID]++
+		if m.missed[c.ID] >= misses {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, nil
+}
+func (m *memContentRepo) RestoreSeen(ctx context.Context, providerID string, seen []string) (int, error) {
+	restored := 0
+	for _, id := range seen {
+		c, ok := m.byKey[m.key(providerID, id)]
+		if !ok || m.missed[c.ID] == 0 {
+			continue
+		}
+		if m.deleted[c.ID] {
+			restored++
+		}
+		delete(m.missed, c.ID)
+		delete(m.deleted, c.ID)
+	}
+	return restored, nil
+}
 func (m *memContentRepo) ListIDsByType(ctx context.Context, t entities.ContentType, offset, limit int) ([]int64, error) {
 	return nil, nil
 }
@@ -159,6 +309,9 @@ func (n *noopHistoryRepo) GetByProviderID(ctx context.Context, providerID string
 func (n *noopHistoryRepo) GetLastSync(ctx context.Context, providerID string) (*entities.SyncHistory, error) {
 	return nil, nil
 }
+func (n *noopHistoryRepo) GetLastSuccessfulSync(ctx context.Context, providerID string) (*entities.SyncHistory, error) {
+	return nil, nil
+}
 func (n *noopHistoryRepo) GetAll(ctx context.Context, limit int) ([]entities.SyncHistory, error) {
 	return nil, nil
 }
@@ -212,6 +365,367 @@ func TestContentSyncService_NewAndUpdate(t *testing.T) {
 	}
 }
 
+type memTagRepo struct {
+	byContent map[int64][]string
+}
+
+func (r *memTagRepo) SetContentTags(ctx context.Context, contentID int64, names []string) error {
+	if r.byContent == nil {
+		r.byContent = map[int64][]string{}
+	}
+	r.byContent[contentID] = names
+	return nil
+}
+func (r *memTagRepo) ListWithCounts(ctx context.Context, limit int) ([]entities.TagCount, error) {
+	return nil, nil
+}
+
+func TestContentSyncService_StoresNormalizedTags(t *testing.T) {
+	logger := zap.NewNop()
+	items := []providers.ProviderContent{
+		{ProviderID: "provider2", ProviderContentID: "v1", Title: "Docker", ContentType: "video", PublishedAt: time.Now().UTC(), Tags: []string{"DevOps", " containers ", "devops"}},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	tags := &memTagRepo{}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: &fakeProviderClient{items: items},
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    &noopHistoryRepo{},
+		TagRepo:        tags,
+	}
+	if _, err := svc.SyncProvider(context.Background(), "provider2"); err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+	got := tags.byContent[1]
+	if len(got) != 2 || got[0] != "devops" || got[1] != "containers" {
+		t.Fatalf("expected normalized tags [devops containers], got %v", got)
+	}
+}
+
+func TestContentSyncService_DetectsLanguage(t *testing.T) {
+	logger := zap.NewNop()
+	items := []providers.ProviderContent{
+		{ProviderID: "provider1", ProviderContentID: "v1", Title: "Go ile eşzamanlı programlama", Description: "Bu derste goroutine ve kanalların nasıl kullanıldığını öğreniyoruz", ContentType: "video", PublishedAt: time.Now().UTC()},
+		{ProviderID: "provider1", ProviderContentID: "v2", Title: "Kubernetes", ContentType: "video", PublishedAt: time.Now().UTC()},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	client := &fakeProviderClient{items: items}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: client,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    &noopHistoryRepo{},
+	}
+	if _, err := svc.SyncProvider(context.Background(), "provider1"); err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+	if got := crepo.byKey["provider1|v1"].Language; got == nil || *got != "tr" {
+		t.Fatalf("expected detected language tr, got %v", got)
+	}
+	if got := crepo.byKey["provider1|v2"].Language; got == nil || *got != "und" {
+		t.Fatalf("expected undetermined language for a one-word title, got %v", got)
+	}
+
+	// A language the provider sets replaces the detected one
+	client.items = []providers.ProviderContent{items[0], items[1]}
+	client.items[1].Language = "English"
+	if _, err := svc.SyncProvider(context.Background(), "provider1"); err != nil {
+		t.Fatalf("second sync error: %v", err)
+	}
+	if got := crepo.byKey["provider1|v2"].Language; got == nil || *got != "en" {
+		t.Fatalf("expected provider language en, got %v", got)
+	}
+}
+
+// memBatchRepo implements repositories.ContentBatchRepository over the in-memory repos
+type memBatchRepo struct {
+	contents      *memContentRepo
+	metrics       *memMetricsRepo
+	tags          map[int64][]string
+	loads, writes int
+	failWrites    bool
+}
+
+func (b *memBatchRepo) GetByProviderKeys(ctx context.Context, providerID string, ids []string) ([]repositories.ContentWithMetrics, error) {
+	b.loads++
+	var out []repositories.ContentWithMetrics
+	for _, id := range ids {
+		c, ok := b.contents.byKey[b.contents.key(providerID, id)]
+		if !ok {
+			continue
+		}
+		cw := repositories.ContentWithMetrics{Content: *c}
+		if m, ok := b.metrics.byID[c.ID]; ok {
+			cw.Metrics = *m
+		}
+		out = append(out, cw)
+	}
+	return out, nil
+}
+
+func (b *memBatchRepo) UpsertBatch(ctx context.Context, contents []entities.Content) error {
+	b.writes++
+	if b.failWrites {
+		return errors.New("copy failed")
+	}
+	for i := range contents {
+		c := &contents[i]
+		if c.ID == 0 {
+			stored := *c
+			_ = b.contents.Create(ctx, &stored)
+			c.ID = stored.ID
+		} else {
+			next := *c
+			next.Metrics, next.Tags = nil, nil
+			*b.contents.byKey[b.contents.key(c.ProviderID, c.ProviderContentID)] = next
+		}
+		if c.Metrics != nil {
+			m := *c.Metrics
+			m.ContentID = c.ID
+			_ = b.metrics.UpdateByContentID(ctx, c.ID, &m)
+		}
+		if b.tags == nil {
+			b.tags = map[int64][]string{}
+		}
+		b.tags[c.ID] = c.Tags
+	}
+	return nil
+}
+
+func TestContentSyncService_BatchedSync(t *testing.T) {
+	logger := zap.NewNop()
+	views := func(v int64) *int64 { return &v }
+	items := []providers.ProviderContent{
+		{ProviderID: "provider1", ProviderContentID: "v1", Title: "Go", ContentType: "video", Views: views(100), Tags: []string{"Go"}},
+		{ProviderID: "provider1", ProviderContentID: "v1", Title: "Go", ContentType: "video", Views: views(100)},
+		{ProviderID: "provider1", ProviderContentID: "v2", Title: "Rust", ContentType: "video", Views: views(100)},
+		{ProviderID: "provider1", ProviderContentID: "v3", Title: "Zig", ContentType: "text", Views: views(100)},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	batch := &memBatchRepo{contents: crepo, metrics: mrepo}
+	client := &fakeProviderClient{items: items}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: client,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    &noopHistoryRepo{},
+		Batch:          batch,
+		BatchSize:      2,
+		Thresholds:     MetricsThresholds{Percent: 5, AbsViews: 100, AbsLikes: 10, AbsReactions: 5, AbsComments: 5},
+	}
+	res, err := svc.SyncProvider(context.Background(), "provider1")
+	if err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+	if res.NewContents != 3 || res.SkippedContents != 1 || res.FailedContents != 0 {
+		t.Fatalf("expected 3 new and the repeated key skipped, got %+v", res)
+	}
+	if batch.loads != 2 || batch.writes != 2 {
+		t.Fatalf("expected one load and one write per chunk, got %d loads %d writes", batch.loads, batch.writes)
+	}
+	v1 := crepo.byKey["provider1|v1"]
+	if m := mrepo.byID[v1.ID]; m == nil || m.FinalScore != 42 || m.RecalculatedAt == nil {
+		t.Fatalf("expected scored metrics for v1, got %+v", m)
+	}
+	if got := batch.tags[v1.ID]; len(got) != 1 || got[0] != "go" {
+		t.Fatalf("expected normalized tags [go], got %v", got)
+	}
+
+	client.items = append([]providers.ProviderContent(nil), items...)
+	client.items[2].Views = views(100000)
+	res, err = svc.SyncProvider(context.Background(), "provider1")
+	if err != nil {
+		t.Fatalf("second sync error: %v", err)
+	}
+	if res.NewContents != 0 || res.UpdatedContents != 1 || res.SkippedContents != 3 {
+		t.Fatalf("expected v2 updated and the rest skipped, got %+v", res)
+	}
+	if m := mrepo.byID[crepo.byKey["provider1|v2"].ID]; m.Views != 100000 {
+		t.Fatalf("expected updated views, got %d", m.Views)
+	}
+}
+
+func TestContentSyncService_BatchWriteFailureFallsBackToItems(t *testing.T) {
+	logger := zap.NewNop()
+	items := []providers.ProviderContent{
+		{ProviderID: "provider1", ProviderContentID: "v1", Title: "Go", ContentType: "video"},
+		{ProviderID: "provider1", ProviderContentID: "v2", Title: "Rust", ContentType: "video"},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: &fakeProviderClient{items: items},
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    &noopHistoryRepo{},
+		Batch:          &memBatchRepo{contents: crepo, metrics: mrepo, failWrites: true},
+	}
+	res, err := svc.SyncProvider(context.Background(), "provider1")
+	if err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+	if res.NewContents != 2 || len(crepo.all) != 2 {
+		t.Fatalf("expected both items stored item by item, got %+v (%d rows)", res, len(crepo.all))
+	}
+}
+
+func TestContentSyncService_RemovesAndRestoresMissingItems(t *testing.T) {
+	logger := zap.NewNop()
+	items := []providers.ProviderContent{
+		{ProviderID: "provider1", ProviderContentID: "v1", Title: "Go", ContentType: "video"},
+		{ProviderID: "provider1", ProviderContentID: "v2", Title: "Rust", ContentType: "video"},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	client := &fakeProviderClient{items: items}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: client,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    &noopHistoryRepo{},
+		RemovalMisses:  2,
+	}
+	ctx := context.Background()
+	if _, err := svc.SyncProvider(ctx, "provider1"); err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+	v2 := crepo.byKey["provider1|v2"].ID
+
+	// v2 disappears: the first miss is only counted, the second removes it
+	client.items = items[:1]
+	res, _ := svc.SyncProvider(ctx, "provider1")
+	if res.RemovedContents != 0 || crepo.deleted[v2] {
+		t.Fatalf("expected no removal after one miss, got %+v", res)
+	}
+	res, _ = svc.SyncProvider(ctx, "provider1")
+	if res.RemovedContents != 1 || !crepo.deleted[v2] {
+		t.Fatalf("expected v2 removed after two misses, got %+v", res)
+	}
+
+	// An empty feed removes nothing
+	client.items = nil
+	if res, _ = svc.SyncProvider(ctx, "provider1"); res.RemovedContents != 0 {
+		t.Fatalf("expected an empty fetch to remove nothing, got %+v", res)
+	}
+
+	// v2 reappears and is restored
+	client.items = items
+	res, _ = svc.SyncProvider(ctx, "provider1")
+	if res.RestoredContents != 1 || crepo.deleted[v2] || crepo.missed[v2] != 0 {
+		t.Fatalf("expected v2 restored, got %+v", res)
+	}
+}
+
+func TestContentSyncService_PropagatesAttributeChanges(t *testing.T) {
+	for _, batched := range []bool{false, true} {
+		logger := zap.NewNop()
+		views := func(v int64) *int64 { return &v }
+		published := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+		items := []providers.ProviderContent{
+			{ProviderID: "provider1", ProviderContentID: "v1", Title: "Go", ContentType: "video", Views: views(100), PublishedAt: published},
+			{ProviderID: "provider1", ProviderContentID: "v2", Title: "Rust", ContentType: "video", Views: views(100), PublishedAt: published},
+		}
+		crepo := &memContentRepo{}
+		mrepo := &memMetricsRepo{}
+		client := &fakeProviderClient{items: items}
+		svc := &ContentSyncService{
+			Logger:         logger,
+			Factory:        &fakeFactory{items: items},
+			ProviderClient: client,
+			Contents:       crepo,
+			Metrics:        mrepo,
+			ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+			HistoryRepo:    &noopHistoryRepo{},
+			Thresholds:     MetricsThresholds{Percent: 5, AbsViews: 100, AbsLikes: 10, AbsReactions: 5, AbsComments: 5},
+		}
+		if batched {
+			svc.Batch = &memBatchRepo{contents: crepo, metrics: mrepo}
+		}
+		ctx := context.Background()
+		if _, err := svc.SyncProvider(ctx, "provider1"); err != nil {
+			t.Fatalf("batched=%v: sync error: %v", batched, err)
+		}
+
+		// v1 is retitled and moved; a nanosecond-only date difference is not a change
+		client.items = append([]providers.ProviderContent(nil), items...)
+		client.items[0].Title = "Go 1.23"
+		client.items[0].URL = "https://example.com/go"
+		client.items[1].PublishedAt = published.Add(time.Nanosecond)
+		res, err := svc.SyncProvider(ctx, "provider1")
+		if err != nil || res.UpdatedContents != 1 || res.ChangedContents != 1 || res.SkippedContents != 1 {
+			t.Fatalf("batched=%v: expected v1 changed and v2 skipped, got %+v (%v)", batched, res, err)
+		}
+		v1 := crepo.byKey["provider1|v1"]
+		if v1.Title != "Go 1.23" || v1.URL == nil || *v1.URL != "https://example.com/go" {
+			t.Fatalf("batched=%v: expected new title and URL stored, got %+v", batched, v1)
+		}
+
+		// A metric change below the thresholds is only written when forced
+		client.items[1].Views = views(101)
+		v2 := crepo.byKey["provider1|v2"].ID
+		mrepo.byID[v2].FinalScore = 0
+		res, _ = svc.SyncProvider(ctx, "provider1")
+		if res.UpdatedContents != 0 || mrepo.byID[v2].Views != 100 {
+			t.Fatalf("batched=%v: expected small change skipped, got %+v", batched, res)
+		}
+		res, _ = svc.SyncProvider(WithForce(ctx), "provider1")
+		if res.UpdatedContents != 2 || res.ChangedContents != 0 || res.SkippedContents != 0 {
+			t.Fatalf("batched=%v: expected every item rewritten when forced, got %+v", batched, res)
+		}
+		if m := mrepo.byID[v2]; m.Views != 101 || m.FinalScore != 42 {
+			t.Fatalf("batched=%v: expected forced metrics and score, got %+v", batched, m)
+		}
+	}
+}
+
+func TestContentSyncService_PartialFetchRemovesNothing(t *testing.T) {
+	logger := zap.NewNop()
+	items := []providers.ProviderContent{
+		{ProviderID: "provider1", ProviderContentID: "v1", Title: "Go", ContentType: "video"},
+		{ProviderID: "provider1", ProviderContentID: "v2", Title: "Rust", ContentType: "video"},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	client := &fakeProviderClient{items: items, partial: "stopped at max pages (1)"}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: client,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    &noopHistoryRepo{},
+		RemovalMisses:  1,
+	}
+	ctx := context.Background()
+	if _, err := svc.SyncProvider(ctx, "provider1"); err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+	client.items = items[:1]
+	if res, _ := svc.SyncProvider(ctx, "provider1"); res.RemovedContents != 0 || len(crepo.missed) != 0 {
+		t.Fatalf("expected a partial fetch to count no misses, got %+v (%v)", res, crepo.missed)
+	}
+}
+
 type mockEngine struct{}
 
 func (m *mockEngine) CalculateScore(content *entities.Content, metrics *entities.ContentMetrics) (float64, error) {
@@ -219,3 +733,221 @@ func (m *mockEngine) CalculateScore(content *entities.Content, metrics *entities
 }
 
 func intPtr(v int) *int { return &v }
+
+func TestContentSyncService_ReportsMalformedRows(t *testing.T) {
+	logger := zap.NewNop()
+	items := []providers.ProviderContent{
+		{ProviderID: "import", ProviderContentID: "a1", Title: "T1", ContentType: "text", PublishedAt: time.Now().UTC()},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	client := &fakeProviderClient{items: items, rowErrs: []providers.RowError{
+		{Source: "dump.csv", Line: 3, Message: "title is required"},
+		{Source: "dump.csv", Line: 7, Message: "views: not an integer"},
+	}}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: client,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    &noopHistoryRepo{},
+	}
+	res, err := svc.SyncProvider(context.Background(), "import")
+	if err != nil {
+		t.Fatalf("sync error: %v", err)
+	}
+	if res.NewContents != 1 || res.FailedContents != 2 || res.TotalFetched != 3 {
+		t.Fatalf("expected 1 new, 2 failed of 3, got %+v", res)
+	}
+	if len(res.Errors) != 2 || res.Errors[0] != "malformed row dump.csv:3: title is required" {
+		t.Fatalf("unexpected errors: %v", res.Errors)
+	}
+}
+
+type memCursorRepo struct {
+	byProvider map[string]entities.SyncCursor
+}
+
+func (m *memCursorRepo) Get(ctx context.Context, providerID string) (*entities.SyncCursor, error) {
+	c, ok := m.byProvider[providerID]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+func (m *memCursorRepo) Upsert(ctx context.Context, c *entities.SyncCursor) error {
+	if m.byProvider == nil {
+		m.byProvider = map[string]entities.SyncCursor{}
+	}
+	m.byProvider[c.ProviderID] = *c
+	return nil
+}
+func (m *memCursorRepo) Delete(ctx context.Context, providerID string) error {
+	delete(m.byProvider, providerID)
+	return nil
+}
+
+// recordingHistoryRepo keeps the last history row written. Like a database it
+// refuses writes whose context is already done.
+type recordingHistoryRepo struct {
+	noopHistoryRepo
+	last entities.SyncHistory
+}
+
+func (r *recordingHistoryRepo) Create(ctx context.Context, h *entities.SyncHistory) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.noopHistoryRepo.Create(ctx, h)
+}
+
+func (r *recordingHistoryRepo) Update(ctx context.Context, h *entities.SyncHistory) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.last = *h
+	return nil
+}
+
+func TestContentSyncService_IncrementalCursor(t *testing.T) {
+	logger := zap.NewNop()
+	newest := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	items := []providers.ProviderContent{
+		{ProviderID: "feed", ProviderContentID: "a1", Title: "T1", ContentType: "text", PublishedAt: newest.Add(-time.Hour)},
+		{ProviderID: "feed", ProviderContentID: "a2", Title: "T2", ContentType: "text", PublishedAt: newest},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	cursors := &memCursorRepo{}
+	history := &recordingHistoryRepo{}
+	client := &fakeProviderClient{items: items, etag: `"v1"`}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: client,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    history,
+		CursorRepo:     cursors,
+	}
+	ctx := context.Background()
+
+	res, err := svc.SyncProvider(ctx, "feed")
+	if err != nil || res.NewContents != 2 || res.NotModified {
+		t.Fatalf("first sync: res=%+v err=%v", res, err)
+	}
+	cur := cursors.byProvider["feed"]
+	if cur.ETag != `"v1"` || cur.MaxPublishedAt == nil || !cur.MaxPublishedAt.Equal(newest) {
+		t.Fatalf("cursor not advanced: %+v", cur)
+	}
+
+	res, err = svc.SyncProvider(ctx, "feed")
+	if err != nil || !res.NotModified || res.TotalFetched != 0 {
+		t.Fatalf("second sync should be skipped: res=%+v err=%v", res, err)
+	}
+	if history.last.SyncStatus != entities.SyncStatusSkipped || history.last.ErrorMessage == nil {
+		t.Fatalf("expected skipped history with reason, got %+v", history.last)
+	}
+	if len(client.calls) != 2 || client.calls[1].Since == nil || !client.calls[1].Since.Equal(newest) {
+		t.Fatalf("second fetch should resume from the cursor, got %+v", client.calls)
+	}
+
+	// A forced sync ignores the cursor and rewrites every item
+	res, err = svc.SyncProvider(WithForce(ctx), "feed")
+	if err != nil || res.NotModified || !res.Forced || res.UpdatedContents != 2 {
+		t.Fatalf("forced sync: res=%+v err=%v", res, err)
+	}
+	if last := client.calls[2]; last.ETag != "" || last.Since != nil {
+		t.Fatalf("forced fetch should not send the cursor, got %+v", last)
+	}
+}
+
+func TestContentSyncService_RecordsRequestAttempts(t *testing.T) {
+	logger := zap.NewNop()
+	items := []providers.ProviderContent{
+		{ProviderID: "provider1", ProviderContentID: "a1", Title: "T1", ContentType: "text", PublishedAt: time.Now().UTC()},
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	history := &recordingHistoryRepo{}
+	svc := &ContentSyncService{
+		Logger:  logger,
+		Factory: &fakeFactory{items: items},
+		ProviderClient: &fakeProviderClient{items: items, attempts: []providers.RequestAttempt{
+			{URL: "http://p/contents", Attempt: 1, StatusCode: 503, Backoff: 500 * time.Millisecond},
+			{URL: "http://p/contents", Attempt: 2, StatusCode: 200},
+		}},
+		Contents:    crepo,
+		Metrics:     mrepo,
+		ScoreCalc:   &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo: history,
+	}
+	res, err := svc.SyncProvider(context.Background(), "provider1")
+	if err != nil || res.Retries != 1 || res.NewContents != 1 {
+		t.Fatalf("res=%+v err=%v", res, err)
+	}
+	got := history.last.Attempts
+	if len(got) != 2 || got[0].StatusCode != 503 || got[0].BackoffMs != 500 || got[1].Attempt != 2 {
+		t.Fatalf("unexpected attempts in history: %+v", got)
+	}
+}
+
+func TestContentSyncService_StreamsInBatches(t *testing.T) {
+	logger := zap.NewNop()
+	newest := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	items := make([]providers.ProviderContent, 250)
+	for i := range items {
+		items[i] = providers.ProviderContent{ProviderID: "provider1", ProviderContentID: fmt.Sprintf("a%d", i), Title: "T", ContentType: "text", PublishedAt: newest.Add(-time.Duration(i) * time.Minute)}
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	cursors := &memCursorRepo{}
+	history := &recordingHistoryRepo{}
+	client := &fakeProviderClient{items: items, err: errors.New("connection reset")}
+	stored := 0
+	client.onYield = func(i int) {
+		if i == 150 {
+			stored = len(crepo.all)
+		}
+	}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items, streaming: true},
+		ProviderClient: client,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    history,
+		CursorRepo:     cursors,
+	}
+	ctx := context.Background()
+
+	res, err := svc.SyncProvider(ctx, "provider1")
+	if err == nil {
+		t.Fatal("the stream error should be returned")
+	}
+	if stored != streamBatchSize {
+		t.Fatalf("the first batch should be stored while streaming, had %d items at item 150", stored)
+	}
+	if res.TotalFetched != 250 || res.NewContents != 250 || len(res.Errors) != 1 || !strings.Contains(res.Errors[0], "connection reset") {
+		t.Fatalf("items before the failure should be kept: %+v", res)
+	}
+	if history.last.SyncStatus != entities.SyncStatusPartial {
+		t.Fatalf("expected a partial run, got %s", history.last.SyncStatus)
+	}
+	if _, ok := cursors.byProvider["provider1"]; ok {
+		t.Fatal("a failed stream must not advance the cursor")
+	}
+
+	client.err = nil
+	res, err = svc.SyncProvider(ctx, "provider1")
+	if err != nil || res.NewContents != 0 || res.FailedContents != 0 || history.last.SyncStatus != entities.SyncStatusSuccess {
+		t.Fatalf("second sync: res=%+v err=%v", res, err)
+	}
+	if cur := cursors.byProvider["provider1"]; cur.MaxPublishedAt == nil || !cur.MaxPublishedAt.Equal(newest) {
+		t.Fatalf("cursor not advanced to the newest item: %+v", cur)
+	}
+}
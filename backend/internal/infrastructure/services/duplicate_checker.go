@@ -5,18 +5,21 @@ type MetricsThresholds struct {
 	AbsViews     int
 	AbsLikes     int
 	AbsReactions int
+	AbsComments  int
 }
 
 type MetricsSnapshot struct {
-	Views       int64
-	Likes       int64
-	ReadingTime int
-	Reactions   int
+	Views           int64
+	Likes           int64
+	ReadingTime     int
+	Reactions       int
+	DurationSeconds int
+	Comments        int
 }
 
 func HasMetricsChanged(oldM, newM MetricsSnapshot, t MetricsThresholds) bool {
-	// Reading time: any change matters
-	if oldM.ReadingTime != newM.ReadingTime {
+	// Reading time and duration: any change matters
+	if oldM.ReadingTime != newM.ReadingTime || oldM.DurationSeconds != newM.DurationSeconds {
 		return true
 	}
 	if significantChange(oldM.Views, newM.Views, t.Percent, int64(t.AbsViews)) {
@@ -28,6 +31,10 @@ func HasMetricsChanged(oldM, newM MetricsSnapshot, t MetricsThresholds) bool {
 	if significantChange(int64(oldM.Reactions), int64(newM.Reactions), t.Percent, int64(t.AbsReactions)) {
 		return true
 	}
+	// Comments are compared only when they differ; a zero threshold would flag every item
+	if oldM.Comments != newM.Comments && significantChange(int64(oldM.Comments), int64(newM.Comments), t.Percent, int64(t.AbsComments)) {
+		return true
+	}
 	return false
 }
 
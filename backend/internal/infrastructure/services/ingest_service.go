@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/domain/repositories"
+)
+
+var (
+	// ErrIngestUnauthorized covers unknown providers, bad signatures and stale timestamps
+	ErrIngestUnauthorized = errors.New("ingest: request not authenticated")
+	// ErrDeliveryInProgress is returned while another request processes the same delivery ID
+	ErrDeliveryInProgress = errors.New("ingest: delivery is being processed")
+)
+
+// maxDeliveryIDLength matches ingest_deliveries.delivery_id
+const maxDeliveryIDLength = 100
+
+// IngestResult is the outcome of one pushed delivery. Duplicate is set when the
+// delivery ID was already processed and Result is the stored outcome.
+type IngestResult struct {
+	DeliveryID string     `json:"delivery_id"`
+	Duplicate  bool       `json:"duplicate"`
+	Result     SyncResult `json:"result"`
+}
+
+// IngestService accepts batches pushed by providers. Requests are signed with a
+// per-provider secret:
+//
+//	X-Signature: sha256=hex(HMAC-SHA256(secret, timestamp + "." + delivery_id + "." + body))
+//
+// and each delivery ID is applied once.
+type IngestService struct {
+	Sync       *ContentSyncService
+	Deliveries repositories.IngestDeliveryRepository
+	// Secrets maps provider IDs to their signing secret; providers without one cannot push
+	Secrets map[string]string
+	// MaxSkew is how far X-Timestamp may be from now (0 disables the check)
+	MaxSkew time.Duration
+	// StaleAfter is when an unfinished delivery may be claimed again (0 = 10m)
+	StaleAfter time.Duration
+	Logger     *zap.Logger
+}
+
+// ParseIngestSecrets parses comma-separated id=secret pairs.
+func ParseIngestSecrets(s string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(part, "=")
+		id, secret = strings.TrimSpace(id), strings.TrimSpace(secret)
+		if !ok || id == "" || secret == "" {
+			// The secret is deliberately left out of the error
+			return nil, fmt.Errorf("invalid ingest secret entry for %q, expected id=secret", id)
+		}
+		out[id] = secret
+	}
+	return out, nil
+}
+
+// Sign returns the X-Signature value for a request body.
+func Sign(secret, timestamp, deliveryID string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(deliveryID))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidDeliveryID reports whether id can be stored as a delivery ID.
+func ValidDeliveryID(id string) bool {
+	return id != "" && len(id) <= maxDeliveryIDLength && strings.TrimSpace(id) == id
+}
+
+// Verify checks the signature and timestamp of a pushed request.
+func (s *IngestService) Verify(providerID, deliveryID, timestamp, signature string, body []byte, now time.Time) error {
+	secret, ok := s.Secrets[providerID]
+	if !ok {
+		return ErrIngestUnauthorized
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrIngestUnauthorized
+	}
+	if s.MaxSkew > 0 {
+		skew := now.Sub(time.Unix(ts, 0))
+		if skew > s.MaxSkew || skew < -s.MaxSkew {
+			return ErrIngestUnauthorized
+		}
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, deliveryID, body)), []byte(strings.ToLower(strings.TrimSpace(signature)))) {
+		return ErrIngestUnauthorized
+	}
+	return nil
+}
+
+// Ingest applies a verified batch once per delivery ID. A completed redelivery
+// returns the stored result; a delivery still in progress returns ErrDeliveryInProgress.
+func (s *IngestService) Ingest(ctx context.Context, providerID, deliveryID string, items []domainp.ProviderContent) (IngestResult, error) {
+	out := IngestResult{DeliveryID: deliveryID}
+	staleAfter := s.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = 10 * time.Minute
+	}
+	claimed, existing, err := s.Deliveries.Claim(ctx, providerID, deliveryID, staleAfter)
+	if err != nil {
+		return out, fmt.Errorf("claim delivery: %w", err)
+	}
+	if !claimed {
+		if existing == nil || existing.Status != entities.IngestDeliveryCompleted {
+			return out, ErrDeliveryInProgress
+		}
+		out.Duplicate = true
+		if len(existing.Result) > 0 {
+			if err := json.Unmarshal(existing.Result, &out.Result); err != nil {
+				s.Logger.Warn("stored ingest result is unreadable", zap.String("provider", providerID), zap.String("delivery_id", deliveryID), zap.Error(err))
+			}
+		}
+		return out, nil
+	}
+	res, err := s.Sync.IngestBatch(ctx, providerID, items)
+	if err != nil {
+		if rerr := s.Deliveries.Release(context.WithoutCancel(ctx), providerID, deliveryID); rerr != nil {
+			s.Logger.Warn("failed to release ingest delivery", zap.String("provider", providerID), zap.String("delivery_id", deliveryID), zap.Error(rerr))
+		}
+		return out, err
+	}
+	out.Result = res
+	b, _ := json.Marshal(res)
+	if err := s.Deliveries.Complete(context.WithoutCancel(ctx), providerID, deliveryID, b); err != nil {
+		// The batch is stored; a redelivery after the stale window re-applies it harmlessly
+		s.Logger.Error("failed to complete ingest delivery", zap.String("provider", providerID), zap.String("delivery_id", deliveryID), zap.Error(err))
+	}
+	return out, nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/providers"
+)
+
+type memDeliveryRepo struct {
+	rows map[string]*entities.IngestDelivery
+}
+
+func (m *memDeliveryRepo) Claim(ctx context.Context, providerID, deliveryID string, staleAfter time.Duration) (bool, *entities.IngestDelivery, error) {
+	if m.rows == nil {
+		m.rows = map[string]*entities.IngestDelivery{}
+	}
+	key := providerID + "|" + deliveryID
+	if d, ok := m.rows[key]; ok {
+		if d.Status == entities.IngestDeliveryCompleted || time.Since(d.ReceivedAt) < staleAfter {
+			cp := *d
+			return false, &cp, nil
+		}
+	}
+	m.rows[key] = &entities.IngestDelivery{ProviderID: providerID, DeliveryID: deliveryID, Status: entities.IngestDeliveryProcessing, ReceivedAt: time.Now()}
+	return true, nil, nil
+}
+
+func (m *memDeliveryRepo) Complete(ctx context.Context, providerID, deliveryID string, result []byte) error {
+	d := m.rows[providerID+"|"+deliveryID]
+	now := time.Now()
+	d.Status, d.Result, d.CompletedAt = entities.IngestDeliveryCompleted, result, &now
+	return nil
+}
+
+func (m *memDeliveryRepo) Release(ctx context.Context, providerID, deliveryID string) error {
+	delete(m.rows, providerID+"|"+deliveryID)
+	return nil
+}
+
+func newIngestService() (*IngestService, *memContentRepo, *recordingHistoryRepo) {
+	logger := zap.NewNop()
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	history := &recordingHistoryRepo{}
+	sync := &ContentSyncService{
+		Logger:      logger,
+		Factory:     &fakeFactory{},
+		Contents:    crepo,
+		Metrics:     mrepo,
+		ScoreCalc:   &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo: history,
+		Thresholds:  MetricsThresholds{Percent: 5, AbsViews: 100, AbsLikes: 10, AbsReactions: 5},
+	}
+	return &IngestService{
+		Sync:       sync,
+		Deliveries: &memDeliveryRepo{},
+		Secrets:    map[string]string{"partner": "s3cret"},
+		MaxSkew:    5 * time.Minute,
+		Logger:     logger,
+	}, crepo, history
+}
+
+func TestIngestService_Verify(t *testing.T) {
+	svc, _, _ := newIngestService()
+	now := time.Unix(1731744000, 0)
+	body := []byte(`{"items":[]}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := Sign("s3cret", ts, "d-1", body)
+
+	if err := svc.Verify("partner", "d-1", ts, sig, body, now); err != nil {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+	cases := map[string]func() error{
+		"unknown provider": func() error { return svc.Verify("other", "d-1", ts, sig, body, now) },
+		"tampered body":    func() error { return svc.Verify("partner", "d-1", ts, sig, []byte(`{"items":[{}]}`), now) },
+		"other delivery":   func() error { return svc.Verify("partner", "d-2", ts, sig, body, now) },
+		"wrong secret":     func() error { return svc.Verify("partner", "d-1", ts, Sign("nope", ts, "d-1", body), body, now) },
+		"stale timestamp":  func() error { return svc.Verify("partner", "d-1", ts, sig, body, now.Add(6*time.Minute)) },
+		"bad timestamp":    func() error { return svc.Verify("partner", "d-1", "yesterday", sig, body, now) },
+	}
+	for name, verify := range cases {
+		if err := verify(); !errors.Is(err, ErrIngestUnauthorized) {
+			t.Errorf("%s: expected ErrIngestUnauthorized, got %v", name, err)
+		}
+	}
+}
+
+func TestIngestService_IngestIsIdempotent(t *testing.T) {
+	svc, crepo, history := newIngestService()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	items := []providers.ProviderContent{
+		{ProviderID: "spoofed", ProviderContentID: "a1", Title: "T1", ContentType: "text", PublishedAt: now, Reactions: intPtr(3)},
+		{ProviderContentID: "v1", Title: "V1", ContentType: "video", PublishedAt: now},
+		{ProviderContentID: "bad", ContentType: "text"},
+	}
+
+	first, err := svc.Ingest(ctx, "partner", "d-1", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Duplicate || first.Result.NewContents != 2 || first.Result.FailedContents != 1 || first.Result.TotalFetched != 3 {
+		t.Fatalf("unexpected first result: %+v", first)
+	}
+	if _, err := crepo.GetByProviderKey(ctx, "partner", "a1"); err != nil {
+		t.Fatalf("item should be stored under the path provider: %v", err)
+	}
+	if history.last.SyncStatus != entities.SyncStatusPartial || history.last.NewContents != 2 {
+		t.Fatalf("expected a partial history row with 2 new contents, got %+v", history.last)
+	}
+
+	again, err := svc.Ingest(ctx, "partner", "d-1", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Duplicate || again.Result.NewContents != 2 {
+		t.Fatalf("redelivery should return the stored result, got %+v", again)
+	}
+	if len(crepo.all) != 2 {
+		t.Fatalf("redelivery must not store contents again, have %d", len(crepo.all))
+	}
+
+	update := []providers.ProviderContent{{ProviderContentID: "a1", Title: "T1", ContentType: "text", PublishedAt: now, Reactions: intPtr(300)}}
+	next, err := svc.Ingest(ctx, "partner", "d-2", update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Duplicate || next.Result.UpdatedContents != 1 {
+		t.Fatalf("expected the new delivery to update a1, got %+v", next)
+	}
+}
+
+func TestIngestService_DeliveryInProgress(t *testing.T) {
+	svc, _, _ := newIngestService()
+	ctx := context.Background()
+	if _, _, err := svc.Deliveries.Claim(ctx, "partner", "d-1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	_, err := svc.Ingest(ctx, "partner", "d-1", []providers.ProviderContent{{ProviderContentID: "a1", Title: "T1", ContentType: "text"}})
+	if !errors.Is(err, ErrDeliveryInProgress) {
+		t.Fatalf("expected ErrDeliveryInProgress, got %v", err)
+	}
+}
+
+func TestParseIngestSecrets(t *testing.T) {
+	got, err := ParseIngestSecrets(" partner = abc=def , other=x ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["partner"] != "abc=def" || got["other"] != "x" {
+		t.Fatalf("unexpected secrets: %v", got)
+	}
+	if _, err := ParseIngestSecrets("partner"); err == nil {
+		t.Fatal("expected an error for an entry without a secret")
+	}
+}
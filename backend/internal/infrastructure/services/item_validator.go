@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	domainp "search_engine/internal/domain/providers"
+)
+
+// Column limits of the contents table; longer values would fail the insert.
+const (
+	maxContentIDLength = 100
+	maxTitleLength     = 255
+	maxURLLength       = 500
+)
+
+// maxFutureSkew is how far ahead of now a published_at may be, allowing for clock
+// and time zone differences at the provider.
+const maxFutureSkew = 24 * time.Hour
+
+// validateItem returns why pc cannot be stored, starting with the problems the
+// provider reported while mapping it. An empty result means the item is valid.
+func validateItem(pc domainp.ProviderContent, now time.Time) []string {
+	reasons := append([]string(nil), pc.Problems...)
+	tooLong := func(field, v string, max int) {
+		if utf8.RuneCountInString(v) > max {
+			reasons = append(reasons, fmt.Sprintf("%s exceeds %d characters", field, max))
+		}
+	}
+	if strings.TrimSpace(pc.ProviderContentID) == "" {
+		reasons = append(reasons, "provider_content_id is required")
+	}
+	tooLong("provider_content_id", pc.ProviderContentID, maxContentIDLength)
+	if strings.TrimSpace(pc.Title) == "" {
+		reasons = append(reasons, "title is required")
+	}
+	tooLong("title", pc.Title, maxTitleLength)
+	tooLong("url", pc.URL, maxURLLength)
+	tooLong("thumbnail_url", pc.ThumbnailURL, maxURLLength)
+	switch pc.ContentType {
+	case "video", "text":
+	case "":
+		reasons = append(reasons, "content_type is required")
+	default:
+		reasons = append(reasons, fmt.Sprintf("unknown content_type %q", pc.ContentType))
+	}
+	if !pc.PublishedAt.IsZero() && pc.PublishedAt.After(now.Add(maxFutureSkew)) {
+		reasons = append(reasons, fmt.Sprintf("published_at %s is in the future", pc.PublishedAt.Format(time.RFC3339)))
+	}
+
+	negative := func(field string, v *int64) {
+		if v != nil && *v < 0 {
+			reasons = append(reasons, fmt.Sprintf("%s is negative", field))
+		}
+	}
+	widen := func(v *int) *int64 {
+		if v == nil {
+			return nil
+		}
+		n := int64(*v)
+		return &n
+	}
+	negative("views", pc.Views)
+	negative("likes", pc.Likes)
+	negative("reading_time", widen(pc.ReadingTime))
+	negative("reactions", widen(pc.Reactions))
+	negative("duration_seconds", widen(pc.DurationSeconds))
+	negative("comments", widen(pc.Comments))
+	if pc.Views != nil && pc.Likes != nil && *pc.Likes > *pc.Views {
+		reasons = append(reasons, "likes exceed views")
+	}
+	return reasons
+}
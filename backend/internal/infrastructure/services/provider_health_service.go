@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/domain/repositories"
+	"search_engine/internal/infrastructure/cache"
+	"search_engine/internal/infrastructure/ratelimiter"
+)
+
+const providerHealthCacheKey = "provider_health:v1"
+
+// Health statuses of a provider. Unknown is reported for providers that cannot be
+// checked without a full fetch.
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusUnhealthy = "unhealthy"
+	HealthStatusUnknown   = "unknown"
+)
+
+// ProviderHealth is the outcome of probing one provider. IsHealthy is true only
+// when a probe succeeded.
+type ProviderHealth struct {
+	ProviderID         string     `json:"provider_id"`
+	Status             string     `json:"status"`
+	IsHealthy          bool       `json:"is_healthy"`
+	ResponseTimeMs     int64      `json:"response_time_ms"`
+	StatusCode         int        `json:"status_code"`
+	CheckedAt          time.Time  `json:"checked_at"`
+	Error              *string    `json:"error"`
+	LastSuccessfulSync *time.Time `json:"last_successful_sync"`
+}
+
+// ProviderHealthService probes every registered provider. Providers implementing
+// domainp.HealthProber are checked with a single lightweight request; the others
+// are reported as unknown rather than fetched in full.
+type ProviderHealthService struct {
+	Factory interface {
+		GetAllProviders() []domainp.IContentProvider
+	}
+	// HistoryRepo is optional; when set, results carry the last successful sync
+	HistoryRepo repositories.SyncHistoryRepository
+	Logger      *zap.Logger
+	// Timeout bounds each probe (0 = no limit beyond the caller's context)
+	Timeout time.Duration
+	// Cache is optional; results are cached for CacheTTL
+	Cache    *cache.Store
+	CacheTTL time.Duration
+	// Limits is optional; when set, probe requests take tokens from the provider's rate limit
+	Limits interface {
+		LimitRequests(ctx context.Context, providerID string, requestsPerMinute int) context.Context
+	}
+}
+
+// Check returns the cached results when present, probing otherwise.
+func (s *ProviderHealthService) Check(ctx context.Context) ([]ProviderHealth, error) {
+	var cached []ProviderHealth
+	if ok, _ := s.Cache.GetJSON(ctx, providerHealthCacheKey, &cached); ok {
+		return cached, nil
+	}
+	return s.Refresh(ctx)
+}
+
+// Refresh probes every provider concurrently and caches the results.
+func (s *ProviderHealthService) Refresh(ctx context.Context) ([]ProviderHealth, error) {
+	providers := s.Factory.GetAllProviders()
+	out := make([]ProviderHealth, len(providers))
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Add(1)
+		go func(i int, p domainp.IContentProvider) {
+			defer wg.Done()
+			out[i] = s.probe(ctx, p)
+		}(i, p)
+	}
+	wg.Wait()
+	sort.Slice(out, func(i, j int) bool { return out[i].ProviderID < out[j].ProviderID })
+	if err := s.Cache.SetJSON(ctx, providerHealthCacheKey, out, s.CacheTTL); err != nil && s.Logger != nil {
+		s.Logger.Warn("provider health cache write failed", zap.Error(err))
+	}
+	return out, nil
+}
+
+func (s *ProviderHealthService) probe(ctx context.Context, p domainp.IContentProvider) ProviderHealth {
+	h := ProviderHealth{ProviderID: p.GetProviderID()}
+	pctx, cancel := ctx, context.CancelFunc(func() {})
+	if s.Timeout > 0 {
+		pctx, cancel = context.WithTimeout(ctx, s.Timeout)
+	}
+	if s.Limits != nil {
+		pctx = s.Limits.LimitRequests(pctx, h.ProviderID, p.GetRateLimit().RequestsPerMinute)
+	}
+	start := time.Now()
+	if prober, ok := p.(domainp.HealthProber); ok {
+		var err error
+		h.StatusCode, err = prober.Probe(pctx)
+		h.ResponseTimeMs = time.Since(start).Milliseconds()
+		h.IsHealthy = err == nil
+		h.Status = HealthStatusHealthy
+		if err != nil {
+			h.Status = HealthStatusUnhealthy
+			if errors.Is(err, ratelimiter.ErrRateLimited) {
+				// The provider was not asked, so nothing is known about it
+				h.Status = HealthStatusUnknown
+			}
+			msg := err.Error()
+			h.Error = &msg
+		}
+	} else {
+		h.Status = HealthStatusUnknown
+	}
+	cancel()
+	h.CheckedAt = time.Now().UTC()
+	if s.HistoryRepo != nil {
+		if last, err := s.HistoryRepo.GetLastSuccessfulSync(ctx, h.ProviderID); err == nil && last != nil {
+			h.LastSuccessfulSync = last.CompletedAt
+		}
+	}
+	return h
+}
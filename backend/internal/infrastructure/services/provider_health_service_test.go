@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/providers"
+	"search_engine/internal/infrastructure/cache"
+)
+
+type probingProvider struct {
+	id     string
+	code   int
+	err    error
+	probes int
+}
+
+func (p *probingProvider) FetchContents(ctx context.Context) ([]providers.ProviderContent, error) {
+	return nil, errors.New("FetchContents must not be called when Probe exists")
+}
+func (p *probingProvider) GetProviderID() string { return p.id }
+func (p *probingProvider) GetRateLimit() providers.RateLimit {
+	return providers.RateLimit{RequestsPerMinute: 100}
+}
+func (p *probingProvider) Probe(ctx context.Context) (int, error) {
+	p.probes++
+	return p.code, p.err
+}
+
+type listFactory []providers.IContentProvider
+
+func (f listFactory) GetAllProviders() []providers.IContentProvider { return f }
+
+type lastSuccessHistoryRepo struct {
+	noopHistoryRepo
+	at time.Time
+}
+
+func (r *lastSuccessHistoryRepo) GetLastSuccessfulSync(ctx context.Context, providerID string) (*entities.SyncHistory, error) {
+	if providerID != "provider1" {
+		return nil, errors.New("no rows")
+	}
+	return &entities.SyncHistory{ProviderID: providerID, SyncStatus: entities.SyncStatusSuccess, CompletedAt: &r.at}, nil
+}
+
+func TestProviderHealthService_ProbesAndCaches(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	healthy := &probingProvider{id: "provider1", code: 200}
+	broken := &probingProvider{id: "provider2", code: 503, err: errors.New("status 503 from provider2")}
+	synced := time.Date(2024, 11, 16, 6, 0, 0, 0, time.UTC)
+	svc := &ProviderHealthService{
+		Factory:     listFactory{broken, healthy},
+		HistoryRepo: &lastSuccessHistoryRepo{at: synced},
+		Timeout:     time.Second,
+		Cache:       cache.NewStore(rc, nil, nil),
+		CacheTTL:    time.Minute,
+	}
+
+	res, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].ProviderID != "provider1" || res[1].ProviderID != "provider2" {
+		t.Fatalf("expected results sorted by provider, got %+v", res)
+	}
+	if !res[0].IsHealthy || res[0].Status != HealthStatusHealthy || res[0].StatusCode != 200 || res[0].Error != nil {
+		t.Fatalf("provider1 should be healthy: %+v", res[0])
+	}
+	if res[0].LastSuccessfulSync == nil || !res[0].LastSuccessfulSync.Equal(synced) {
+		t.Fatalf("expected last successful sync %v, got %v", synced, res[0].LastSuccessfulSync)
+	}
+	if res[1].IsHealthy || res[1].Status != HealthStatusUnhealthy || res[1].StatusCode != 503 || res[1].Error == nil || res[1].LastSuccessfulSync != nil {
+		t.Fatalf("provider2 should be unhealthy: %+v", res[1])
+	}
+
+	if _, err := svc.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if healthy.probes != 1 {
+		t.Fatalf("expected cached result, provider probed %d times", healthy.probes)
+	}
+	if _, err := svc.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if healthy.probes != 2 {
+		t.Fatalf("expected refresh to probe again, provider probed %d times", healthy.probes)
+	}
+}
+
+// fetchOnlyProvider has no cheap probe; fetching it would cost a full sync.
+type fetchOnlyProvider struct{ fetches int }
+
+func (p *fetchOnlyProvider) FetchContents(ctx context.Context) ([]providers.ProviderContent, error) {
+	p.fetches++
+	return nil, nil
+}
+func (p *fetchOnlyProvider) GetProviderID() string { return "push" }
+func (p *fetchOnlyProvider) GetRateLimit() providers.RateLimit {
+	return providers.RateLimit{RequestsPerMinute: 100}
+}
+
+func TestProviderHealthService_UnprobeableProviderIsUnknown(t *testing.T) {
+	p := &fetchOnlyProvider{}
+	svc := &ProviderHealthService{Factory: listFactory{p}, Timeout: time.Second}
+	res, err := svc.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.fetches != 0 {
+		t.Fatalf("health check must not fetch the provider, fetched %d times", p.fetches)
+	}
+	if len(res) != 1 || res[0].Status != HealthStatusUnknown || res[0].IsHealthy || res[0].Error != nil {
+		t.Fatalf("expected status unknown, got %+v", res)
+	}
+}
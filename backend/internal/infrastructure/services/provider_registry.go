@@ -0,0 +1,231 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/domain/repositories"
+)
+
+// ProviderRegistry keeps the provider factory in step with the providers table.
+// Enabled rows are built into providers; rows whose updated_at did not change keep
+// their existing provider so per-instance state survives a reload.
+type ProviderRegistry struct {
+	Repo    repositories.ProviderRepository
+	Factory interface {
+		Replace(list []domainp.IContentProvider)
+	}
+	Builder interface {
+		Build(row entities.Provider) (domainp.IContentProvider, error)
+	}
+	Logger *zap.Logger
+
+	mu        sync.Mutex
+	version   string
+	built     map[string]builtProvider
+	intervals map[string]time.Duration
+}
+
+type builtProvider struct {
+	provider  domainp.IContentProvider
+	updatedAt time.Time
+}
+
+// Seed reconciles the table with rows, the providers configured through the
+// environment. Missing rows are created as env rows, and env rows take the
+// environment's kind, base URL, definition and transport while keeping the enabled flag, rate
+// limit, timeout and sync interval set through the admin API. Rows the admin API
+// owns are left alone, with a warning for each one the environment disagrees with;
+// those it agrees with on every field it sets become env rows.
+// Env rows no longer configured are handed over to the admin API.
+func (r *ProviderRegistry) Seed(ctx context.Context, rows []entities.Provider) error {
+	existing, err := r.Repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]entities.Provider, len(existing))
+	for _, row := range existing {
+		stored[row.ID] = row
+	}
+	configured := make(map[string]bool, len(rows))
+	for _, want := range rows {
+		configured[want.ID] = true
+		want.Source = entities.ProviderSourceEnv
+		cur, ok := stored[want.ID]
+		if !ok {
+			if err := r.Repo.Create(ctx, &want); err != nil {
+				// Another instance starting at the same time may have created it
+				if errors.Is(err, repositories.ErrProviderExists) {
+					continue
+				}
+				return err
+			}
+			r.Logger.Info("seeded provider", zap.String("provider_id", want.ID), zap.String("kind", string(want.Kind)))
+			continue
+		}
+		diff := EnvManagedDiff(cur, want)
+		if len(diff) == 0 {
+			if cur.Source == entities.ProviderSourceEnv {
+				continue
+			}
+			// An admin row matching the environment exactly, such as one seeded before
+			// rows had a source, is claimed by the environment
+			cur.Source = entities.ProviderSourceEnv
+			if err := r.Repo.Update(ctx, &cur); err != nil && !errors.Is(err, repositories.ErrProviderNotFound) {
+				return err
+			}
+			r.Logger.Info("provider matches the environment, now managed through it", zap.String("provider_id", cur.ID))
+			continue
+		}
+		if cur.Source != entities.ProviderSourceEnv {
+			r.Logger.Warn("provider differs from the environment, keeping the admin API configuration",
+				zap.String("provider_id", cur.ID), zap.Strings("fields", diff))
+			continue
+		}
+		cur.Kind, cur.BaseURL, cur.Definition, cur.Transport = want.Kind, want.BaseURL, want.Definition, want.Transport
+		if err := r.Repo.Update(ctx, &cur); err != nil && !errors.Is(err, repositories.ErrProviderNotFound) {
+			return err
+		}
+		r.Logger.Info("provider updated from the environment", zap.String("provider_id", cur.ID), zap.Strings("fields", diff))
+	}
+	for _, row := range existing {
+		if row.Source != entities.ProviderSourceEnv || configured[row.ID] {
+			continue
+		}
+		row.Source = entities.ProviderSourceAdmin
+		if err := r.Repo.Update(ctx, &row); err != nil && !errors.Is(err, repositories.ErrProviderNotFound) {
+			return err
+		}
+		r.Logger.Info("provider no longer configured in the environment, now managed through the admin API", zap.String("provider_id", row.ID))
+	}
+	return nil
+}
+
+// EnvManagedDiff names the fields the environment sets that differ between stored
+// and want. Definitions and transports are compared as JSON, since the table
+// normalizes them.
+func EnvManagedDiff(stored, want entities.Provider) []string {
+	var diff []string
+	if stored.Kind != want.Kind {
+		diff = append(diff, "kind")
+	}
+	if stored.BaseURL != want.BaseURL {
+		diff = append(diff, "base_url")
+	}
+	if !sameJSON(stored.Definition, want.Definition) {
+		diff = append(diff, "definition")
+	}
+	if !sameJSON(stored.Transport, want.Transport) {
+		diff = append(diff, "transport")
+	}
+	return diff
+}
+
+// sameJSON reports whether a and b hold the same JSON value; absent and null are equal.
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb any
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return bytes.Equal(a, b)
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// Validate reports whether row can be built into a provider.
+func (r *ProviderRegistry) Validate(row entities.Provider) error {
+	_, err := r.Builder.Build(row)
+	return err
+}
+
+// Reload rebuilds the factory from the table. A row that fails to build is logged
+// and left out; the other providers are still registered.
+func (r *ProviderRegistry) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	version, err := r.Repo.Version(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := r.Repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	built := make(map[string]builtProvider, len(rows))
+	intervals := make(map[string]time.Duration, len(rows))
+	list := make([]domainp.IContentProvider, 0, len(rows))
+	for _, row := range rows {
+		if !row.Enabled {
+			continue
+		}
+		b, ok := r.built[row.ID]
+		if !ok || !b.updatedAt.Equal(row.UpdatedAt) {
+			p, err := r.Builder.Build(row)
+			if err != nil {
+				r.Logger.Error("provider not registered", zap.String("provider_id", row.ID), zap.Error(err))
+				continue
+			}
+			b = builtProvider{provider: p, updatedAt: row.UpdatedAt}
+		}
+		built[row.ID] = b
+		intervals[row.ID] = row.SyncInterval
+		list = append(list, b.provider)
+	}
+	r.Factory.Replace(list)
+	r.built, r.intervals, r.version = built, intervals, version
+	r.Logger.Info("provider registry loaded", zap.Int("providers", len(list)), zap.Int("rows", len(rows)))
+	return nil
+}
+
+// ReloadIfChanged reloads only when the table changed since the last load.
+func (r *ProviderRegistry) ReloadIfChanged(ctx context.Context) (bool, error) {
+	version, err := r.Repo.Version(ctx)
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	unchanged := version == r.version
+	r.mu.Unlock()
+	if unchanged {
+		return false, nil
+	}
+	return true, r.Reload(ctx)
+}
+
+// Watch polls the table every interval so changes made through another API
+// instance are picked up. It returns when ctx is done.
+func (r *ProviderRegistry) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.ReloadIfChanged(ctx); err != nil && ctx.Err() == nil {
+				r.Logger.Warn("provider registry reload failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// SyncInterval returns the provider's own sync interval, 0 when it has none.
+func (r *ProviderRegistry) SyncInterval(providerID string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.intervals[providerID]
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/providers"
+	"search_engine/internal/domain/repositories"
+	infraproviders "search_engine/internal/infrastructure/providers"
+)
+
+type memProviderRepo struct {
+	rows    map[string]entities.Provider
+	changes int
+}
+
+func (r *memProviderRepo) List(ctx context.Context) ([]entities.Provider, error) {
+	var out []entities.Provider
+	for _, row := range r.rows {
+		out = append(out, row)
+	}
+	return out, nil
+}
+func (r *memProviderRepo) Get(ctx context.Context, id string) (*entities.Provider, error) {
+	if row, ok := r.rows[id]; ok {
+		return &row, nil
+	}
+	return nil, nil
+}
+func (r *memProviderRepo) Create(ctx context.Context, p *entities.Provider) error {
+	if _, ok := r.rows[p.ID]; ok {
+		return repositories.ErrProviderExists
+	}
+	return r.put(p)
+}
+func (r *memProviderRepo) Update(ctx context.Context, p *entities.Provider) error {
+	if _, ok := r.rows[p.ID]; !ok {
+		return repositories.ErrProviderNotFound
+	}
+	return r.put(p)
+}
+func (r *memProviderRepo) Delete(ctx context.Context, id string) error {
+	if _, ok := r.rows[id]; !ok {
+		return repositories.ErrProviderNotFound
+	}
+	delete(r.rows, id)
+	r.changes++
+	return nil
+}
+func (r *memProviderRepo) Version(ctx context.Context) (string, error) {
+	return fmt.Sprint(r.changes), nil
+}
+func (r *memProviderRepo) put(p *entities.Provider) error {
+	r.changes++
+	p.UpdatedAt = time.Unix(int64(r.changes), 0)
+	r.rows[p.ID] = *p
+	return nil
+}
+
+func newTestRegistry() (*ProviderRegistry, *memProviderRepo, *infraproviders.ProviderFactory) {
+	repo := &memProviderRepo{rows: map[string]entities.Provider{}}
+	factory := infraproviders.NewProviderFactory()
+	return &ProviderRegistry{
+		Repo:    repo,
+		Factory: factory,
+		Builder: infraproviders.Builder{Timeout: time.Second},
+		Logger:  zap.NewNop(),
+	}, repo, factory
+}
+
+func TestProviderRegistry_SeedReconcilesEnvRows(t *testing.T) {
+	reg, repo, _ := newTestRegistry()
+	ctx := context.Background()
+	if err := reg.Seed(ctx, []entities.Provider{{ID: "provider1", Kind: entities.ProviderKindJSON, BaseURL: "http://a.example", Enabled: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.rows["provider1"]; got.Source != entities.ProviderSourceEnv {
+		t.Fatalf("expected seeded row to be env-managed, got %+v", got)
+	}
+	// Paused and tuned through the admin API
+	row := repo.rows["provider1"]
+	row.Enabled, row.RateLimitPerMinute = false, 5
+	_ = repo.Update(ctx, &row)
+	_ = repo.Create(ctx, &entities.Provider{ID: "provider2", Kind: entities.ProviderKindXML, BaseURL: "http://admin.example", Enabled: true, Source: entities.ProviderSourceAdmin})
+	_ = repo.Create(ctx, &entities.Provider{ID: "old", Kind: entities.ProviderKindFeed, BaseURL: "http://old.example/rss", Enabled: true, Source: entities.ProviderSourceEnv})
+	// Seeded before rows had a source, so the migration left it an admin row
+	_ = repo.Create(ctx, &entities.Provider{ID: "legacy", Kind: entities.ProviderKindJSON, BaseURL: "http://legacy.example", Enabled: true, Source: entities.ProviderSourceAdmin})
+
+	err := reg.Seed(ctx, []entities.Provider{
+		{ID: "provider1", Kind: entities.ProviderKindJSON, BaseURL: "http://new.example", Enabled: true},
+		{ID: "provider2", Kind: entities.ProviderKindXML, BaseURL: "http://env.example", Enabled: true},
+		{ID: "legacy", Kind: entities.ProviderKindJSON, BaseURL: "http://legacy.example", Enabled: true},
+		{ID: "blog", Kind: entities.ProviderKindFeed, BaseURL: "http://blog.example/rss", Enabled: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1 := repo.rows["provider1"]; p1.BaseURL != "http://new.example" || p1.Enabled || p1.RateLimitPerMinute != 5 {
+		t.Fatalf("expected the env base URL with the admin settings kept, got %+v", p1)
+	}
+	if p2 := repo.rows["provider2"]; p2.BaseURL != "http://admin.example" || p2.Source != entities.ProviderSourceAdmin {
+		t.Fatalf("admin-managed row must not be overwritten, got %+v", p2)
+	}
+	if legacy := repo.rows["legacy"]; legacy.Source != entities.ProviderSourceEnv {
+		t.Fatalf("expected an admin row matching the environment to be claimed, got %+v", legacy)
+	}
+	if blog, ok := repo.rows["blog"]; !ok || blog.Source != entities.ProviderSourceEnv {
+		t.Fatalf("expected a provider added to the environment to be seeded, got %+v", blog)
+	}
+	if old := repo.rows["old"]; old.Source != entities.ProviderSourceAdmin {
+		t.Fatalf("expected a row dropped from the environment to be handed to the admin API, got %+v", old)
+	}
+
+	// Unchanged settings do not touch the table
+	changes := repo.changes
+	_ = reg.Seed(ctx, []entities.Provider{{ID: "provider1", Kind: entities.ProviderKindJSON, BaseURL: "http://new.example", Enabled: true}, {ID: "blog", Kind: entities.ProviderKindFeed, BaseURL: "http://blog.example/rss"}, {ID: "legacy", Kind: entities.ProviderKindJSON, BaseURL: "http://legacy.example"}})
+	if repo.changes != changes {
+		t.Fatalf("expected no writes for an unchanged environment, got %d", repo.changes-changes)
+	}
+}
+
+func TestEnvManagedDiff_ComparesDefinitionsAsJSON(t *testing.T) {
+	stored := entities.Provider{ID: "g", Kind: entities.ProviderKindGeneric, Definition: []byte(`{"items_path": "items", "format": "json"}`)}
+	want := entities.Provider{ID: "g", Kind: entities.ProviderKindGeneric, Definition: []byte(`{"format":"json","items_path":"items"}`)}
+	if diff := EnvManagedDiff(stored, want); len(diff) != 0 {
+		t.Fatalf("expected equal definitions, got %v", diff)
+	}
+	want.Definition = []byte(`{"format":"xml","items_path":"items"}`)
+	if diff := EnvManagedDiff(stored, want); len(diff) != 1 || diff[0] != "definition" {
+		t.Fatalf("expected a definition diff, got %v", diff)
+	}
+}
+
+func TestProviderRegistry_ReloadRegistersEnabledRows(t *testing.T) {
+	reg, repo, factory := newTestRegistry()
+	ctx := context.Background()
+	_ = repo.Create(ctx, &entities.Provider{ID: "provider1", Kind: entities.ProviderKindJSON, BaseURL: "http://a.example", Enabled: true, SyncInterval: time.Hour})
+	_ = repo.Create(ctx, &entities.Provider{ID: "paused", Kind: entities.ProviderKindXML, BaseURL: "http://b.example", Enabled: false})
+	_ = repo.Create(ctx, &entities.Provider{ID: "broken", Kind: entities.ProviderKindXML, BaseURL: "not a url", Enabled: true})
+	if err := reg.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	all := factory.GetAllProviders()
+	if len(all) != 1 || all[0].GetProviderID() != "provider1" {
+		t.Fatalf("expected only provider1 registered, got %d providers", len(all))
+	}
+	if reg.SyncInterval("provider1") != time.Hour || reg.SyncInterval("paused") != 0 {
+		t.Fatal("sync intervals not tracked")
+	}
+	first := all[0]
+
+	// An unrelated change keeps the untouched provider instance
+	row := repo.rows["paused"]
+	row.Enabled = true
+	_ = repo.Update(ctx, &row)
+	if changed, err := reg.ReloadIfChanged(ctx); err != nil || !changed {
+		t.Fatalf("expected reload, got %v %v", changed, err)
+	}
+	if p, _ := factory.GetProviderByID("provider1"); p != first {
+		t.Fatal("unchanged provider was rebuilt")
+	}
+	if _, err := factory.GetProviderByID("paused"); err != nil {
+		t.Fatal("resumed provider not registered")
+	}
+	if changed, _ := reg.ReloadIfChanged(ctx); changed {
+		t.Fatal("reload without table changes")
+	}
+
+	// Reconfiguring a provider rebuilds it
+	row = repo.rows["provider1"]
+	row.RateLimitPerMinute = 5
+	_ = repo.Update(ctx, &row)
+	_ = reg.Reload(ctx)
+	p, _ := factory.GetProviderByID("provider1")
+	if p == first || p.GetRateLimit() != (providers.RateLimit{RequestsPerMinute: 5}) {
+		t.Fatal("reconfigured provider not rebuilt")
+	}
+}
+
+func TestProviderRegistry_SeedAppliesEnvTransport(t *testing.T) {
+	reg, repo, _ := newTestRegistry()
+	ctx := context.Background()
+	env := entities.Provider{ID: "provider1", Kind: entities.ProviderKindJSON, BaseURL: "http://a.example", Enabled: true}
+	if err := reg.Seed(ctx, []entities.Provider{env}); err != nil {
+		t.Fatal(err)
+	}
+	// PROVIDER1_TRANSPORT set after the first start
+	env.Transport = []byte(`{"auth":{"type":"api_key","header":"X-API-Key","secret":{"env":"PROVIDER_SECRET_PROVIDER1_API_KEY"}}}`)
+	if err := reg.Seed(ctx, []entities.Provider{env}); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.rows["provider1"].Transport; !sameJSON(got, env.Transport) {
+		t.Fatalf("expected the env transport on the stored row, got %s", got)
+	}
+	// and cleared again
+	env.Transport = nil
+	if err := reg.Seed(ctx, []entities.Provider{env}); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.rows["provider1"].Transport; len(got) != 0 {
+		t.Fatalf("expected the transport cleared, got %s", got)
+	}
+}
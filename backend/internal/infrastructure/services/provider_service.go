@@ -2,12 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 
 	domainp "search_engine/internal/domain/providers"
+	"search_engine/internal/infrastructure/circuitbreaker"
 	"search_engine/internal/infrastructure/ratelimiter"
 )
 
@@ -17,6 +21,13 @@ type ProviderService struct {
 		GetProviderByID(id string) (domainp.IContentProvider, error)
 	}
 	Limiter *ratelimiter.RedisLimiter
+	// MaxRateLimitWait is how long a fetch waits for its rate limit before failing
+	// with ratelimiter.ErrRateLimited; 0 fails at once
+	MaxRateLimitWait time.Duration
+	// MaxInFlight caps concurrent fetches of one provider across all instances (0 = no cap)
+	MaxInFlight int
+	// Breaker is optional; when set, providers with an open circuit are not called
+	Breaker *circuitbreaker.RedisBreaker
 	Logger  *zap.Logger
 	Timeout time.Duration
 }
@@ -35,32 +46,30 @@ func (s *ProviderService) FetchFromAllProviders(ctx context.Context) ([]domainp.
 		go func() {
 			defer wg.Done()
 			providerID := p.GetProviderID()
-			ok, err := s.Limiter.CheckLimit(ctx, providerID, p.GetRateLimit().RequestsPerMinute)
+			release, err := s.acquire(ctx, p)
 			if err != nil {
-				s.Logger.Warn("rate limit check error", zap.String("provider", providerID), zap.Error(err))
+				s.Logger.Warn("provider skipped", zap.String("provider", providerID), zap.Error(err))
+				resCh <- result{nil, err}
+				return
 			}
-			if !ok {
-				resCh <- result{nil, nil}
+			defer release()
+			if err := s.breakerAllow(ctx, providerID); err != nil {
+				s.Logger.Warn("provider skipped", zap.String("provider", providerID), zap.Error(err))
+				resCh <- result{nil, err}
 				return
 			}
-			_ = s.Limiter.RecordRequest(ctx, providerID)
-			cctx, cancel := context.WithTimeout(ctx, s.Timeout)
+			cctx, cancel := s.fetchContext(ctx, p)
 			defer cancel()
-			done := make(chan result, 1)
-			go func() {
-				items, e := p.FetchContents()
-				done <- result{items, e}
-			}()
-			select {
-			case r := <-done:
-				if r.err != nil {
-					s.Logger.Warn("provider fetch failed", zap.String("provider", providerID), zap.Error(r.err))
+			items, err := p.FetchContents(cctx)
+			s.recordOutcome(ctx, providerID, err)
+			if err != nil {
+				if cctx.Err() != nil {
+					s.Logger.Warn("provider fetch timeout", zap.String("provider", providerID), zap.Error(cctx.Err()))
+				} else {
+					s.Logger.Warn("provider fetch failed", zap.String("provider", providerID), zap.Error(err))
 				}
-				resCh <- r
-			case <-cctx.Done():
-				s.Logger.Warn("provider fetch timeout", zap.String("provider", providerID))
-				resCh <- result{nil, cctx.Err()}
 			}
+			resCh <- result{items, err}
 		}()
 	}
 	wg.Wait()
@@ -79,31 +88,189 @@ func (s *ProviderService) FetchFromProvider(ctx context.Context, providerID stri
 	if err != nil {
 		return nil, err
 	}
-	ok, err := s.Limiter.CheckLimit(ctx, providerID, p.GetRateLimit().RequestsPerMinute)
+	release, err := s.acquire(ctx, p)
 	if err != nil {
-		s.Logger.Warn("rate limit check error", zap.String("provider", providerID), zap.Error(err))
+		return nil, err
 	}
-	if !ok {
-		return []domainp.ProviderContent{}, nil
+	defer release()
+	if err := s.breakerAllow(ctx, providerID); err != nil {
+		return nil, err
 	}
-	_ = s.Limiter.RecordRequest(ctx, providerID)
-	cctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	cctx, cancel := s.fetchContext(ctx, p)
 	defer cancel()
-	done := make(chan struct {
-		items []domainp.ProviderContent
-		err   error
-	}, 1)
-	go func() {
-		items, e := p.FetchContents()
-		done <- struct {
-			items []domainp.ProviderContent
-			err   error
-		}{items, e}
-	}()
-	select {
-	case r := <-done:
-		return r.items, r.err
-	case <-cctx.Done():
+	items, err := p.FetchContents(cctx)
+	s.recordOutcome(ctx, providerID, err)
+	if err != nil && cctx.Err() != nil {
+		// Prefer the context error so callers can tell a timeout or cancellation apart
 		return nil, cctx.Err()
 	}
+	return items, err
+}
+
+// StreamFromProvider hands the provider's items to yield as they are decoded when the
+// provider implements StreamingProvider, and one by one after a full fetch otherwise.
+// The fetch timeout covers the whole stream, including the time yield takes.
+func (s *ProviderService) StreamFromProvider(ctx context.Context, providerID string, yield func(domainp.ProviderContent) error) error {
+	p, err := s.Factory.GetProviderByID(providerID)
+	if err != nil {
+		return err
+	}
+	release, err := s.acquire(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := s.breakerAllow(ctx, providerID); err != nil {
+		return err
+	}
+	cctx, cancel := s.fetchContext(ctx, p)
+	defer cancel()
+	if sp, ok := p.(domainp.StreamingProvider); ok {
+		err = sp.StreamContents(cctx, yield)
+	} else {
+		var items []domainp.ProviderContent
+		items, err = p.FetchContents(cctx)
+		for i := 0; err == nil && i < len(items); i++ {
+			err = yield(items[i])
+		}
+	}
+	s.recordOutcome(ctx, providerID, err)
+	if err != nil && cctx.Err() != nil {
+		return cctx.Err()
+	}
+	return err
+}
+
+// FetchIncremental resumes from cur when the provider implements IncrementalProvider;
+// other providers are fetched in full. Row errors of providers implementing
+// RowErrorProvider are returned with the items.
+func (s *ProviderService) FetchIncremental(ctx context.Context, providerID string, cur domainp.Cursor) (domainp.FetchResult, error) {
+	p, err := s.Factory.GetProviderByID(providerID)
+	if err != nil {
+		return domainp.FetchResult{}, err
+	}
+	release, err := s.acquire(ctx, p)
+	if err != nil {
+		return domainp.FetchResult{}, err
+	}
+	defer release()
+	if err := s.breakerAllow(ctx, providerID); err != nil {
+		return domainp.FetchResult{}, err
+	}
+	cctx, cancel := s.fetchContext(ctx, p)
+	defer cancel()
+	var res domainp.FetchResult
+	if ip, ok := p.(domainp.IncrementalProvider); ok {
+		res, err = ip.FetchSince(cctx, cur)
+	} else if rp, ok := p.(domainp.RowErrorProvider); ok {
+		res, err = rp.FetchWithRowErrors(cctx)
+	} else {
+		res.Items, err = p.FetchContents(cctx)
+	}
+	s.recordOutcome(ctx, providerID, err)
+	if err != nil && cctx.Err() != nil {
+		return domainp.FetchResult{}, cctx.Err()
+	}
+	return res, err
+}
+
+// CircuitStatuses returns the breaker state of every registered provider, sorted by ID.
+func (s *ProviderService) CircuitStatuses(ctx context.Context) []circuitbreaker.Status {
+	providers := s.Factory.GetAllProviders()
+	out := make([]circuitbreaker.Status, 0, len(providers))
+	for _, p := range providers {
+		st, err := s.Breaker.Status(ctx, p.GetProviderID())
+		if err != nil {
+			s.Logger.Warn("circuit status read failed", zap.String("provider", p.GetProviderID()), zap.Error(err))
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ProviderID < out[j].ProviderID })
+	return out
+}
+
+// breakerAllow returns an *circuitbreaker.OpenError while the provider's circuit is
+// open; breaker storage errors fail open so Redis trouble does not stop syncing.
+func (s *ProviderService) breakerAllow(ctx context.Context, providerID string) error {
+	err := s.Breaker.Allow(ctx, providerID)
+	if err == nil || errors.Is(err, circuitbreaker.ErrOpen) {
+		return err
+	}
+	s.Logger.Warn("circuit breaker check error", zap.String("provider", providerID), zap.Error(err))
+	return nil
+}
+
+// recordOutcome feeds a fetch result to the breaker. Failures caused by the caller
+// cancelling ctx or by the rate limit refusing a later request are not the
+// provider's fault and are not counted.
+func (s *ProviderService) recordOutcome(ctx context.Context, providerID string, fetchErr error) {
+	if fetchErr != nil && (ctx.Err() != nil || errors.Is(fetchErr, ratelimiter.ErrRateLimited)) {
+		return
+	}
+	st, err := s.Breaker.Record(ctx, providerID, fetchErr)
+	if err != nil {
+		s.Logger.Warn("circuit breaker record error", zap.String("provider", providerID), zap.Error(err))
+		return
+	}
+	if fetchErr != nil && st == circuitbreaker.StateOpen {
+		s.Logger.Warn("provider circuit open", zap.String("provider", providerID), zap.Error(fetchErr))
+	}
+}
+
+// acquire takes a rate limit token and an in-flight slot for p, waiting up to
+// MaxRateLimitWait. The token pays for the fetch's first request; see fetchContext.
+// A refusal is a *ratelimiter.RateLimitedError; limiter storage errors fail open so
+// Redis trouble does not stop syncing.
+func (s *ProviderService) acquire(ctx context.Context, p domainp.IContentProvider) (ratelimiter.Release, error) {
+	return s.take(ctx, p.GetProviderID(), ratelimiter.Limit{RequestsPerMinute: p.GetRateLimit().RequestsPerMinute, MaxInFlight: s.MaxInFlight})
+}
+
+// take is acquire for any limit of providerID.
+func (s *ProviderService) take(ctx context.Context, providerID string, lim ratelimiter.Limit) (ratelimiter.Release, error) {
+	var release ratelimiter.Release
+	var err error
+	if s.MaxRateLimitWait > 0 {
+		wctx, cancel := context.WithTimeout(ctx, s.MaxRateLimitWait)
+		defer cancel()
+		release, err = s.Limiter.Wait(wctx, providerID, lim)
+	} else {
+		release, err = s.Limiter.TryAcquire(ctx, providerID, lim)
+	}
+	if err == nil || errors.Is(err, ratelimiter.ErrRateLimited) || ctx.Err() != nil {
+		return release, err
+	}
+	s.Logger.Warn("rate limiter error", zap.String("provider", providerID), zap.Error(err))
+	return func() {}, nil
+}
+
+// LimitRequests returns ctx with every provider request made under it, retries
+// included, taking a token from providerID's rate limit, so probes and previews
+// draw on the bucket syncs use. A refused request fails with a
+// *ratelimiter.RateLimitedError.
+func (s *ProviderService) LimitRequests(ctx context.Context, providerID string, requestsPerMinute int) context.Context {
+	return s.limitRequests(ctx, providerID, requestsPerMinute, false)
+}
+
+// limitRequests is LimitRequests; with prepaid the first request uses the token acquire took.
+func (s *ProviderService) limitRequests(ctx context.Context, providerID string, requestsPerMinute int, prepaid bool) context.Context {
+	var paid atomic.Bool
+	paid.Store(prepaid)
+	lim := ratelimiter.Limit{RequestsPerMinute: requestsPerMinute}
+	return domainp.WithRequestGate(ctx, func(rctx context.Context) error {
+		if paid.Swap(false) {
+			return nil
+		}
+		_, err := s.take(rctx, providerID, lim)
+		return err
+	})
+}
+
+// fetchContext derives the per-fetch context; cancelling it aborts the in-flight
+// provider request. Each request after the first takes another rate limit token.
+func (s *ProviderService) fetchContext(ctx context.Context, p domainp.IContentProvider) (context.Context, context.CancelFunc) {
+	ctx = s.limitRequests(ctx, p.GetProviderID(), p.GetRateLimit().RequestsPerMinute, true)
+	if s.Timeout > 0 {
+		return context.WithTimeout(ctx, s.Timeout)
+	}
+	return context.WithCancel(ctx)
 }
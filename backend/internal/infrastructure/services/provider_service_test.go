@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/providers"
+	"search_engine/internal/infrastructure/circuitbreaker"
+	"search_engine/internal/infrastructure/ratelimiter"
+)
+
+type failingProvider struct{ calls int }
+
+func (p *failingProvider) FetchContents(ctx context.Context) ([]providers.ProviderContent, error) {
+	p.calls++
+	return nil, errors.New("status 503 from provider2")
+}
+func (p *failingProvider) GetProviderID() string { return "provider2" }
+func (p *failingProvider) GetRateLimit() providers.RateLimit {
+	return providers.RateLimit{RequestsPerMinute: 100}
+}
+
+type singleFactory struct{ p providers.IContentProvider }
+
+func (f *singleFactory) GetAllProviders() []providers.IContentProvider {
+	return []providers.IContentProvider{f.p}
+}
+func (f *singleFactory) GetProviderByID(id string) (providers.IContentProvider, error) {
+	return f.p, nil
+}
+
+func TestProviderService_CircuitOpensAndSyncIsSkipped(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	logger := zap.NewNop()
+	p := &failingProvider{}
+	factory := &singleFactory{p: p}
+	providerSvc := &ProviderService{
+		Factory: factory,
+		Limiter: ratelimiter.NewRedisLimiter(rc, false),
+		Breaker: circuitbreaker.NewRedisBreaker(rc, true, circuitbreaker.Settings{Window: time.Minute, MinRequests: 2, FailureRate: 0.5, CoolDown: time.Minute}),
+		Logger:  logger,
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	history := &recordingHistoryRepo{}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        factory,
+		ProviderClient: providerSvc,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    history,
+	}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := svc.SyncProvider(ctx, "provider2"); err == nil {
+			t.Fatalf("run %d: expected fetch failure", i+1)
+		}
+	}
+	if history.last.SyncStatus != entities.SyncStatusFailed {
+		t.Fatalf("expected failed history, got %s", history.last.SyncStatus)
+	}
+
+	res, err := svc.SyncProvider(ctx, "provider2")
+	if err != nil {
+		t.Fatalf("open circuit should skip, not fail: %v", err)
+	}
+	if p.calls != 2 {
+		t.Fatalf("provider must not be called while the circuit is open, calls=%d", p.calls)
+	}
+	if res.SkipReason == "" || history.last.SyncStatus != entities.SyncStatusSkipped || history.last.ErrorMessage == nil {
+		t.Fatalf("expected skipped run with reason, res=%+v history=%+v", res, history.last)
+	}
+	st := providerSvc.CircuitStatuses(ctx)
+	if len(st) != 1 || st[0].State != circuitbreaker.StateOpen || st[0].RetryAt == nil {
+		t.Fatalf("unexpected circuit statuses: %+v", st)
+	}
+}
+
+type limitedProvider struct{ calls int }
+
+func (p *limitedProvider) FetchContents(ctx context.Context) ([]providers.ProviderContent, error) {
+	p.calls++
+	return []providers.ProviderContent{{ProviderID: "provider1", ProviderContentID: "v1", Title: "Go", ContentType: "video", PublishedAt: time.Now()}}, nil
+}
+func (p *limitedProvider) GetProviderID() string { return "provider1" }
+func (p *limitedProvider) GetRateLimit() providers.RateLimit {
+	return providers.RateLimit{RequestsPerMinute: 1}
+}
+
+func TestProviderService_RateLimitedSyncIsRecorded(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	logger := zap.NewNop()
+	p := &limitedProvider{}
+	factory := &singleFactory{p: p}
+	providerSvc := &ProviderService{
+		Factory: factory,
+		Limiter: ratelimiter.NewRedisLimiter(rc, true),
+		// The next token is a minute away, far beyond the wait budget
+		MaxRateLimitWait: 50 * time.Millisecond,
+		Logger:           logger,
+	}
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	history := &recordingHistoryRepo{}
+	svc := &ContentSyncService{
+		Logger:         logger,
+		Factory:        factory,
+		ProviderClient: providerSvc,
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    history,
+	}
+	ctx := context.Background()
+	if _, err := svc.SyncProvider(ctx, "provider1"); err != nil {
+		t.Fatal(err)
+	}
+	if history.last.SyncStatus != entities.SyncStatusSuccess {
+		t.Fatalf("expected success, got %s", history.last.SyncStatus)
+	}
+
+	start := time.Now()
+	res, err := svc.SyncProvider(ctx, "provider1")
+	if err != nil {
+		t.Fatalf("rate limited run should not fail: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("should give up instead of waiting for the token")
+	}
+	if p.calls != 1 || !res.RateLimited || res.TotalFetched != 0 {
+		t.Fatalf("expected rate limited run without a call, calls=%d res=%+v", p.calls, res)
+	}
+	if history.last.SyncStatus != entities.SyncStatusRateLimited || history.last.ErrorMessage == nil {
+		t.Fatalf("expected rate_limited history with reason, got %+v", history.last)
+	}
+
+	_, err = providerSvc.FetchFromProvider(ctx, "provider1")
+	var rle *ratelimiter.RateLimitedError
+	if !errors.As(err, &rle) || rle.RetryAfter <= 0 {
+		t.Fatalf("expected *RateLimitedError, got %v", err)
+	}
+}
+
+func TestProviderService_RateLimitDoesNotClaimHalfOpenProbe(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	now := time.Now()
+	breaker := circuitbreaker.NewRedisBreaker(rc, true, circuitbreaker.Settings{Window: time.Minute, MinRequests: 2, FailureRate: 0.5, CoolDown: time.Minute, ProbeTimeout: time.Minute})
+	breaker.Now = func() time.Time { return now }
+	limiter := ratelimiter.NewRedisLimiter(rc, true)
+	p := &limitedProvider{}
+	svc := &ProviderService{Factory: &singleFactory{p: p}, Limiter: limiter, Breaker: breaker, Logger: zap.NewNop()}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, _ = breaker.Record(ctx, "provider1", errors.New("timeout"))
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := limiter.TryAcquire(ctx, "provider1", ratelimiter.Limit{RequestsPerMinute: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = svc.FetchFromProvider(ctx, "provider1")
+	if !errors.Is(err, ratelimiter.ErrRateLimited) || p.calls != 0 {
+		t.Fatalf("expected a rate limited fetch without a call, got %v calls=%d", err, p.calls)
+	}
+	if err := breaker.Allow(ctx, "provider1"); err != nil {
+		t.Fatalf("a rate limited fetch must leave the half-open probe free, got %v", err)
+	}
+}
+
+// pagedProvider makes one provider request per page, and one per probe.
+type pagedProvider struct {
+	pages    int
+	requests int
+}
+
+func (p *pagedProvider) FetchContents(ctx context.Context) ([]providers.ProviderContent, error) {
+	for i := 0; i < p.pages; i++ {
+		if err := providers.AwaitRequest(ctx); err != nil {
+			return nil, err
+		}
+		p.requests++
+	}
+	return nil, nil
+}
+func (p *pagedProvider) Probe(ctx context.Context) (int, error) {
+	if err := providers.AwaitRequest(ctx); err != nil {
+		return 0, err
+	}
+	p.requests++
+	return 200, nil
+}
+func (p *pagedProvider) GetProviderID() string { return "provider1" }
+func (p *pagedProvider) GetRateLimit() providers.RateLimit {
+	return providers.RateLimit{RequestsPerMinute: 3}
+}
+
+func TestProviderService_EveryRequestTakesAToken(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	now := time.Now()
+	mr.SetTime(now)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	p := &pagedProvider{pages: 4}
+	svc := &ProviderService{
+		Factory: &singleFactory{p: p},
+		Limiter: ratelimiter.NewRedisLimiter(rc, true),
+		Breaker: circuitbreaker.NewRedisBreaker(rc, true, circuitbreaker.Settings{Window: time.Minute, MinRequests: 1, FailureRate: 0.5, CoolDown: time.Minute}),
+		Logger:  zap.NewNop(),
+	}
+	health := &ProviderHealthService{Factory: listFactory{p}, Limits: svc}
+	ctx := context.Background()
+
+	// Three tokens a minute: the fourth page is refused
+	_, err = svc.FetchFromProvider(ctx, "provider1")
+	if !errors.Is(err, ratelimiter.ErrRateLimited) || p.requests != 3 {
+		t.Fatalf("expected the fourth page rate limited, got %v after %d requests", err, p.requests)
+	}
+	if st, _ := svc.Breaker.Status(ctx, "provider1"); st.State != circuitbreaker.StateClosed {
+		t.Fatalf("rate limit refusals must not count against the provider, got %s", st.State)
+	}
+	if res, _ := health.Refresh(ctx); res[0].Status != HealthStatusUnknown || p.requests != 3 {
+		t.Fatalf("expected a refused probe reported unknown, got %+v after %d requests", res[0], p.requests)
+	}
+
+	mr.SetTime(now.Add(time.Minute))
+	if res, _ := health.Refresh(ctx); res[0].Status != HealthStatusHealthy || p.requests != 4 {
+		t.Fatalf("expected a healthy probe, got %+v after %d requests", res[0], p.requests)
+	}
+	p.pages = 2
+	if _, err := svc.FetchFromProvider(ctx, "provider1"); err != nil || p.requests != 6 {
+		t.Fatalf("expected the two tokens left to cover two pages, got %v after %d requests", err, p.requests)
+	}
+	if _, err := svc.FetchFromProvider(ctx, "provider1"); !errors.Is(err, ratelimiter.ErrRateLimited) || p.requests != 6 {
+		t.Fatalf("expected the fetch refused before its first request, got %v after %d requests", err, p.requests)
+	}
+}
+
+type rowErrProvider struct {
+	fakeProvider
+	errs []providers.RowError
+}
+
+func (p *rowErrProvider) FetchWithRowErrors(ctx context.Context) (providers.FetchResult, error) {
+	return providers.FetchResult{Items: p.items, RowErrors: p.errs}, nil
+}
+
+func TestProviderService_FetchIncrementalReturnsRowErrors(t *testing.T) {
+	p := &rowErrProvider{
+		fakeProvider: fakeProvider{items: []providers.ProviderContent{{ProviderID: "import", ProviderContentID: "a1", Title: "T1"}}},
+		errs:         []providers.RowError{{Source: "dump.csv", Line: 3, Message: "title is required"}},
+	}
+	svc := &ProviderService{Factory: &singleFactory{p: p}, Limiter: ratelimiter.NewRedisLimiter(nil, false), Logger: zap.NewNop()}
+	res, err := svc.FetchIncremental(context.Background(), "import", providers.Cursor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 1 || len(res.RowErrors) != 1 || res.RowErrors[0].Line != 3 {
+		t.Fatalf("expected the item and its row error, got %+v", res)
+	}
+}
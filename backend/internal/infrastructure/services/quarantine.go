@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+	domainp "search_engine/internal/domain/providers"
+)
+
+// ErrQuarantinedItemNotFound is returned by RetryQuarantined for unknown IDs.
+var ErrQuarantinedItemNotFound = errors.New("quarantined item not found")
+
+// maxItemKeyLength matches quarantined_items.item_key
+const maxItemKeyLength = 255
+
+// quarantinedPayload is the stored form of a quarantined item. Problems travel with
+// the item so a retry without corrections fails for the same reasons.
+type quarantinedPayload struct {
+	domainp.ProviderContent
+	Problems []string `json:"problems,omitempty"`
+}
+
+// screen validates items and returns the ones that may be stored. Invalid items are
+// quarantined when a Quarantine repository is set and count as failed otherwise;
+// quarantined items that now pass validation leave the quarantine.
+func (s *ContentSyncService) screen(ctx context.Context, providerID string, items []domainp.ProviderContent, res *SyncResult) []domainp.ProviderContent {
+	var sc screening
+	valid := s.screenBatch(ctx, providerID, items, res, &sc)
+	s.finishScreening(providerID, res, &sc)
+	return valid
+}
+
+// screening carries the counters of one run across the batches passed to screenBatch.
+type screening struct {
+	// index is the position of the next item in the run
+	index   int
+	invalid int
+}
+
+// screenBatch is screen for one batch of a run; finishScreening completes the run.
+func (s *ContentSyncService) screenBatch(ctx context.Context, providerID string, items []domainp.ProviderContent, res *SyncResult, sc *screening) []domainp.ProviderContent {
+	now := time.Now()
+	valid := make([]domainp.ProviderContent, 0, len(items))
+	keys := make([]string, 0, len(items))
+	for _, pc := range items {
+		i := sc.index
+		sc.index++
+		reasons := validateItem(pc, now)
+		if len(reasons) == 0 {
+			valid = append(valid, pc)
+			keys = append(keys, itemKey(pc))
+			continue
+		}
+		if s.quarantine(ctx, providerID, pc, reasons) {
+			res.Quarantined++
+			continue
+		}
+		if sc.invalid < maxReportedRowErrors {
+			res.Errors = append(res.Errors, fmt.Sprintf("invalid item %d (%q): %s", i, pc.ProviderContentID, strings.Join(reasons, "; ")))
+		}
+		sc.invalid++
+		res.FailedContents++
+	}
+	if s.Quarantine != nil && len(keys) > 0 {
+		if err := s.Quarantine.Resolve(ctx, providerID, keys); err != nil {
+			s.Logger.Warn("failed to resolve quarantined items", zap.String("provider", providerID), zap.Error(err))
+		}
+	}
+	return valid
+}
+
+func (s *ContentSyncService) finishScreening(providerID string, res *SyncResult, sc *screening) {
+	if sc.invalid > maxReportedRowErrors {
+		res.Errors = append(res.Errors, fmt.Sprintf("... and %d more invalid items", sc.invalid-maxReportedRowErrors))
+	}
+	if res.Quarantined > 0 {
+		s.Logger.Warn("items quarantined", zap.String("provider", providerID), zap.Int("items", res.Quarantined))
+	}
+}
+
+// quarantine stores an invalid item and reports whether it was kept.
+func (s *ContentSyncService) quarantine(ctx context.Context, providerID string, pc domainp.ProviderContent, reasons []string) bool {
+	if s.Quarantine == nil {
+		return false
+	}
+	item, err := json.Marshal(quarantinedPayload{ProviderContent: pc, Problems: pc.Problems})
+	if err != nil {
+		return false
+	}
+	raw := string(pc.Raw)
+	if raw == "" {
+		raw = string(item)
+	}
+	q := entities.QuarantinedItem{
+		ProviderID:        providerID,
+		ItemKey:           itemKey(pc),
+		ProviderContentID: pc.ProviderContentID,
+		Reasons:           reasons,
+		RawPayload:        raw,
+		Item:              item,
+	}
+	if err := s.Quarantine.Upsert(ctx, &q); err != nil {
+		s.Logger.Error("failed to quarantine item", zap.String("provider", providerID), zap.String("provider_content_id", pc.ProviderContentID), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// itemKey identifies an item across runs: its content ID, or a hash of the raw
+// payload when the ID is missing or too long to be a key.
+func itemKey(pc domainp.ProviderContent) string {
+	if id := strings.TrimSpace(pc.ProviderContentID); id != "" && len(id) <= maxItemKeyLength {
+		return id
+	}
+	raw := pc.Raw
+	if len(raw) == 0 {
+		raw, _ = json.Marshal(pc)
+	}
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// RetryQuarantined validates a quarantined item again, replaced by corrected when
+// given, and stores it through the sync pipeline when it passes; the item then
+// leaves the quarantine. When it still fails, the refreshed reasons are returned.
+func (s *ContentSyncService) RetryQuarantined(ctx context.Context, id int64, corrected *domainp.ProviderContent) (SyncResult, []string, error) {
+	var res SyncResult
+	if s.Quarantine == nil {
+		return res, nil, ErrQuarantinedItemNotFound
+	}
+	q, err := s.Quarantine.Get(ctx, id)
+	if err != nil {
+		return res, nil, err
+	}
+	if q == nil {
+		return res, nil, ErrQuarantinedItemNotFound
+	}
+	var pc domainp.ProviderContent
+	if corrected != nil {
+		pc = *corrected
+	} else {
+		var stored quarantinedPayload
+		if err := json.Unmarshal(q.Item, &stored); err != nil {
+			return res, nil, fmt.Errorf("decode quarantined item: %w", err)
+		}
+		pc = stored.ProviderContent
+		pc.Problems = stored.Problems
+	}
+	pc.ProviderID = q.ProviderID
+
+	if reasons := validateItem(pc, time.Now()); len(reasons) > 0 {
+		if err := s.Quarantine.UpdateReasons(ctx, id, reasons); err != nil {
+			s.Logger.Warn("failed to update quarantine reasons", zap.Int64("id", id), zap.Error(err))
+		}
+		return res, reasons, nil
+	}
+	res = SyncResult{ProviderID: q.ProviderID, TotalFetched: 1, SyncedAt: time.Now().UTC()}
+	start := time.Now()
+	err = s.processOne(ctx, q.ProviderID, pc, isForced(ctx), &res)
+	res.Duration = time.Since(start)
+	if err != nil {
+		return res, nil, fmt.Errorf("store quarantined item: %w", err)
+	}
+	if err := s.Quarantine.Delete(ctx, id); err != nil {
+		return res, nil, err
+	}
+	s.Logger.Info("quarantined item stored", zap.String("provider", q.ProviderID), zap.String("provider_content_id", pc.ProviderContentID))
+	return res, nil, nil
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"search_engine/internal/domain/entities"
+	"search_engine/internal/domain/providers"
+)
+
+type memQuarantineRepo struct {
+	items  []*entities.QuarantinedItem
+	nextID int64
+}
+
+func (m *memQuarantineRepo) find(providerID, key string) *entities.QuarantinedItem {
+	for _, it := range m.items {
+		if it.ProviderID == providerID && it.ItemKey == key {
+			return it
+		}
+	}
+	return nil
+}
+
+func (m *memQuarantineRepo) Upsert(ctx context.Context, it *entities.QuarantinedItem) error {
+	if cur := m.find(it.ProviderID, it.ItemKey); cur != nil {
+		cur.Reasons, cur.RawPayload, cur.Item = it.Reasons, it.RawPayload, it.Item
+		cur.Occurrences++
+		*it = *cur
+		return nil
+	}
+	m.nextID++
+	it.ID, it.Status, it.Occurrences = m.nextID, entities.QuarantinePending, 1
+	cp := *it
+	m.items = append(m.items, &cp)
+	return nil
+}
+
+func (m *memQuarantineRepo) Get(ctx context.Context, id int64) (*entities.QuarantinedItem, error) {
+	for _, it := range m.items {
+		if it.ID == id {
+			cp := *it
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memQuarantineRepo) List(ctx context.Context, providerID *string, status *entities.QuarantineStatus, limit, offset int) ([]entities.QuarantinedItem, error) {
+	var out []entities.QuarantinedItem
+	for _, it := range m.items {
+		out = append(out, *it)
+	}
+	return out, nil
+}
+
+func (m *memQuarantineRepo) Count(ctx context.Context, providerID *string, status *entities.QuarantineStatus) (int64, error) {
+	return int64(len(m.items)), nil
+}
+
+func (m *memQuarantineRepo) SetStatus(ctx context.Context, id int64, status entities.QuarantineStatus) error {
+	for _, it := range m.items {
+		if it.ID == id {
+			it.Status = status
+		}
+	}
+	return nil
+}
+
+func (m *memQuarantineRepo) UpdateReasons(ctx context.Context, id int64, reasons []string) error {
+	for _, it := range m.items {
+		if it.ID == id {
+			it.Reasons = reasons
+		}
+	}
+	return nil
+}
+
+func (m *memQuarantineRepo) Delete(ctx context.Context, id int64) error {
+	for i, it := range m.items {
+		if it.ID == id {
+			m.items = append(m.items[:i], m.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memQuarantineRepo) Resolve(ctx context.Context, providerID string, itemKeys []string) error {
+	for _, key := range itemKeys {
+		if it := m.find(providerID, key); it != nil && it.Status == entities.QuarantinePending {
+			_ = m.Delete(ctx, it.ID)
+		}
+	}
+	return nil
+}
+
+func TestValidateItem(t *testing.T) {
+	now := time.Date(2024, 11, 16, 12, 0, 0, 0, time.UTC)
+	neg := int64(-1)
+	valid := providers.ProviderContent{ProviderContentID: "v1", Title: "T", ContentType: "video", PublishedAt: now.Add(-time.Hour)}
+	cases := []struct {
+		name   string
+		mutate func(*providers.ProviderContent)
+		reason string
+	}{
+		{"valid", func(*providers.ProviderContent) {}, ""},
+		{"missing id", func(pc *providers.ProviderContent) { pc.ProviderContentID = " " }, "provider_content_id is required"},
+		{"missing title", func(pc *providers.ProviderContent) { pc.Title = "" }, "title is required"},
+		{"long title", func(pc *providers.ProviderContent) { pc.Title = strings.Repeat("é", 256) }, "title exceeds 255 characters"},
+		{"unknown type", func(pc *providers.ProviderContent) { pc.ContentType = "podcast" }, `unknown content_type "podcast"`},
+		{"future date", func(pc *providers.ProviderContent) { pc.PublishedAt = now.Add(48 * time.Hour) }, "is in the future"},
+		{"negative views", func(pc *providers.ProviderContent) { pc.Views = &neg }, "views is negative"},
+		{"negative comments", func(pc *providers.ProviderContent) { pc.Comments = intPtr(-3) }, "comments is negative"},
+		{"likes over views", func(pc *providers.ProviderContent) { v, l := int64(10), int64(11); pc.Views, pc.Likes = &v, &l }, "likes exceed views"},
+		{"provider problem", func(pc *providers.ProviderContent) { pc.Problems = []string{"date: invalid"} }, "date: invalid"},
+	}
+	for _, tc := range cases {
+		pc := valid
+		tc.mutate(&pc)
+		reasons := validateItem(pc, now)
+		if tc.reason == "" {
+			if len(reasons) != 0 {
+				t.Errorf("%s: expected no reasons, got %v", tc.name, reasons)
+			}
+			continue
+		}
+		if len(reasons) != 1 || !strings.Contains(reasons[0], tc.reason) {
+			t.Errorf("%s: expected %q, got %v", tc.name, tc.reason, reasons)
+		}
+	}
+}
+
+func newQuarantineSyncService(items []providers.ProviderContent) (*ContentSyncService, *memContentRepo, *memQuarantineRepo, *recordingHistoryRepo) {
+	logger := zap.NewNop()
+	crepo := &memContentRepo{}
+	mrepo := &memMetricsRepo{}
+	q := &memQuarantineRepo{}
+	history := &recordingHistoryRepo{}
+	return &ContentSyncService{
+		Logger:         logger,
+		Factory:        &fakeFactory{items: items},
+		ProviderClient: &fakeProviderClient{items: items},
+		Contents:       crepo,
+		Metrics:        mrepo,
+		ScoreCalc:      &ScoreCalculatorService{Contents: crepo, Metrics: mrepo, Engine: &mockEngine{}, Logger: logger},
+		HistoryRepo:    history,
+		Quarantine:     q,
+	}, crepo, q, history
+}
+
+func TestContentSyncService_QuarantinesInvalidItems(t *testing.T) {
+	now := time.Now().UTC()
+	items := []providers.ProviderContent{
+		{ProviderID: "provider1", ProviderContentID: "a1", Title: "T1", ContentType: "text", PublishedAt: now},
+		{ProviderID: "provider1", ProviderContentID: "p1", Title: "Podcast", ContentType: "podcast", Raw: []byte(`{"id":"p1","type":"podcast"}`)},
+		{ProviderID: "provider1", Title: "No ID", ContentType: "text"},
+	}
+	svc, crepo, q, history := newQuarantineSyncService(items)
+	ctx := context.Background()
+
+	res, err := svc.SyncProvider(ctx, "provider1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NewContents != 1 || res.Quarantined != 2 || res.FailedContents != 0 || len(res.Errors) != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if history.last.SyncStatus != entities.SyncStatusSuccess || history.last.QuarantinedContents != 2 {
+		t.Fatalf("quarantined items should be recorded without failing the run: %+v", history.last)
+	}
+	if len(crepo.all) != 1 || len(q.items) != 2 {
+		t.Fatalf("expected 1 stored and 2 quarantined, got %d and %d", len(crepo.all), len(q.items))
+	}
+	if q.items[0].RawPayload != `{"id":"p1","type":"podcast"}` || q.items[0].Reasons[0] != `unknown content_type "podcast"` {
+		t.Fatalf("unexpected quarantined item: %+v", q.items[0])
+	}
+	if !strings.HasPrefix(q.items[1].ItemKey, "sha256:") {
+		t.Fatalf("items without an ID should be keyed by payload hash, got %q", q.items[1].ItemKey)
+	}
+
+	// The same bad items on the next run are counted, not duplicated
+	if _, err := svc.SyncProvider(ctx, "provider1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.items) != 2 || q.items[0].Occurrences != 2 {
+		t.Fatalf("expected 2 quarantined items seen twice, got %+v", q.items)
+	}
+
+	// Once the provider fixes the item it is stored and leaves the quarantine
+	items[1].ContentType = "video"
+	if _, err := svc.SyncProvider(ctx, "provider1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.items) != 1 || len(crepo.all) != 2 {
+		t.Fatalf("fixed item should be resolved, quarantine %d, contents %d", len(q.items), len(crepo.all))
+	}
+}
+
+func TestContentSyncService_RetryQuarantined(t *testing.T) {
+	items := []providers.ProviderContent{
+		{ProviderID: "provider1", ProviderContentID: "v1", Title: "Bad date", ContentType: "video", Problems: []string{`publication_date: invalid date "15/03/2024"`}},
+	}
+	svc, crepo, q, _ := newQuarantineSyncService(items)
+	ctx := context.Background()
+	if _, err := svc.SyncProvider(ctx, "provider1"); err != nil {
+		t.Fatal(err)
+	}
+	id := q.items[0].ID
+
+	_, reasons, err := svc.RetryQuarantined(ctx, id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reasons) != 1 || !strings.Contains(reasons[0], "publication_date") {
+		t.Fatalf("retry without correction should fail for the stored problem, got %v", reasons)
+	}
+
+	fixed := providers.ProviderContent{ProviderContentID: "v1", Title: "Bad date", ContentType: "video", PublishedAt: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)}
+	crepo.createErr = errors.New("connection reset")
+	_, _, err = svc.RetryQuarantined(ctx, id, &fixed)
+	if err == nil || !strings.Contains(err.Error(), "connection reset") || len(q.items) != 1 {
+		t.Fatalf("expected the store error returned and the item kept, got %v with %d quarantined", err, len(q.items))
+	}
+	crepo.createErr = nil
+
+	res, reasons, err := svc.RetryQuarantined(ctx, id, &fixed)
+	if err != nil || len(reasons) != 0 {
+		t.Fatalf("corrected retry failed: %v %v", reasons, err)
+	}
+	if res.NewContents != 1 || len(q.items) != 0 || len(crepo.all) != 1 {
+		t.Fatalf("corrected item should be stored and leave quarantine: %+v, %d left", res, len(q.items))
+	}
+	if _, _, err := svc.RetryQuarantined(ctx, id, nil); err != ErrQuarantinedItemNotFound {
+		t.Fatalf("expected ErrQuarantinedItemNotFound, got %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"search_engine/internal/domain/providers"
 	"search_engine/internal/domain/repositories"
 	"search_engine/internal/domain/scoring"
+	"search_engine/internal/infrastructure/language"
 )
 
 type ScoreCalculatorService struct {
@@ -20,26 +21,12 @@ type ScoreCalculatorService struct {
 }
 
 func (s *ScoreCalculatorService) ProcessNewContent(ctx context.Context, pc *providers.ProviderContent) (int64, float64, error) {
-	c := entities.Content{
-		ProviderID:        pc.ProviderID,
-		ProviderContentID: pc.ProviderContentID,
-		Title:             pc.Title,
-		ContentType:       mapContentType(pc.ContentType),
-		URL:               strPtrOrNil(pc.URL),
-		ThumbnailURL:      strPtrOrNil(pc.ThumbnailURL),
-		Description:       strPtrOrNil(pc.Description),
-		PublishedAt:       timePtrOrNil(pc.PublishedAt),
-	}
+	c := contentOf(pc)
 	if err := s.Contents.Create(ctx, &c); err != nil {
 		return 0, 0, err
 	}
-	m := entities.ContentMetrics{
-		ContentID:   c.ID,
-		Views:       val64(pc.Views),
-		Likes:       val64(pc.Likes),
-		ReadingTime: valInt(pc.ReadingTime),
-		Reactions:   valInt(pc.Reactions),
-	}
+	m := metricsOf(pc)
+	m.ContentID = c.ID
 	score, err := s.Engine.CalculateScore(&c, &m)
 	if err != nil {
 		return c.ID, 0, err
@@ -77,6 +64,33 @@ func (s *ScoreCalculatorService) RecalculateScore(ctx context.Context, contentID
 	return score, nil
 }
 
+// contentOf returns the content stored for a new provider item.
+func contentOf(pc *providers.ProviderContent) entities.Content {
+	return entities.Content{
+		ProviderID:        pc.ProviderID,
+		ProviderContentID: pc.ProviderContentID,
+		Title:             pc.Title,
+		ContentType:       mapContentType(pc.ContentType),
+		URL:               strPtrOrNil(pc.URL),
+		ThumbnailURL:      strPtrOrNil(pc.ThumbnailURL),
+		Description:       strPtrOrNil(pc.Description),
+		PublishedAt:       timePtrOrNil(pc.PublishedAt),
+		Language:          strPtrOrNil(contentLanguage(pc)),
+	}
+}
+
+// metricsOf returns the provider item's metrics, unscored and without a content ID.
+func metricsOf(pc *providers.ProviderContent) entities.ContentMetrics {
+	return entities.ContentMetrics{
+		Views:           val64(pc.Views),
+		Likes:           val64(pc.Likes),
+		ReadingTime:     valInt(pc.ReadingTime),
+		Reactions:       valInt(pc.Reactions),
+		DurationSeconds: valInt(pc.DurationSeconds),
+		Comments:        valInt(pc.Comments),
+	}
+}
+
 func mapContentType(t string) entities.ContentType {
 	if t == "video" || t == "Video" {
 		return entities.ContentTypeVideo
@@ -84,6 +98,22 @@ func mapContentType(t string) entities.ContentType {
 	return entities.ContentTypeText
 }
 
+// contentLanguage returns the language the provider set, when supported, and the
+// detected language of the title and description otherwise.
+func contentLanguage(pc *providers.ProviderContent) string {
+	if lang := language.Normalize(pc.Language); lang != "" {
+		return lang
+	}
+	return language.Detect(pc.Title + "\n" + pc.Description)
+}
+
+func strValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
 func strPtrOrNil(s string) *string {
 	if s == "" {
 		return nil
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const maxTagLength = 50
+
+// NormalizeTags lowercases, trims and de-duplicates tag names, dropping empty
+// or over-long ones, so provider tags and search filters compare equal.
+func NormalizeTags(raw []string) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	seen := make(map[string]struct{}, len(raw))
+	for _, t := range raw {
+		t = strings.ToLower(strings.Join(strings.Fields(t), " "))
+		if t == "" || utf8.RuneCountInString(t) > maxTagLength {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
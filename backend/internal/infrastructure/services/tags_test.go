@@ -0,0 +1,17 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{" DevOps ", "devops", "", "Machine  Learning", "kubernetes"})
+	want := []string{"devops", "machine learning", "kubernetes"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v got %v", want, got)
+	}
+	if NormalizeTags(nil) != nil {
+		t.Fatalf("expected nil for no tags")
+	}
+}
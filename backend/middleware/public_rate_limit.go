@@ -6,13 +6,16 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
+
+	"search_engine/internal/infrastructure/cache"
 )
 
 // PublicRateLimit applies a simple IP-based rate limit per window across public endpoints.
-// If enabled=false or rdb is nil or limit <=0, middleware becomes a no-op.
-func PublicRateLimit(rdb *redis.Client, enabled bool, limit int, window time.Duration) gin.HandlerFunc {
-	if !enabled || rdb == nil || limit <= 0 || window <= 0 {
+// Counters live in the store's Redis, or in its process-local memory while Redis is
+// down; when neither can count, requests pass.
+// If enabled=false or store is nil or limit <=0, middleware becomes a no-op.
+func PublicRateLimit(store *cache.Store, enabled bool, limit int, window time.Duration) gin.HandlerFunc {
+	if !enabled || store == nil || limit <= 0 || window <= 0 {
 		return func(c *gin.Context) { c.Next() }
 	}
 	return func(c *gin.Context) {
@@ -23,12 +26,9 @@ func PublicRateLimit(rdb *redis.Client, enabled bool, limit int, window time.Dur
 		}
 		bucket := time.Now().UTC().Unix() / int64(window.Seconds())
 		key := fmt.Sprintf("prl:%s:%s:%d", ip, route, bucket)
-		// Use INCR with TTL to count requests in current window bucket.
-		pipe := rdb.TxPipeline()
-		inc := pipe.Incr(c, key)
-		pipe.Expire(c, key, window+2*time.Second)
-		_, _ = pipe.Exec(c)
-		if inc.Val() > int64(limit) {
+		// Count requests in the current window bucket
+		n, err := store.Incr(c, key, window+2*time.Second)
+		if err == nil && n > int64(limit) {
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"success": false,
 				"error": gin.H{
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_content_tags_tag_id;
+DROP TABLE IF EXISTS content_tags;
+DROP TABLE IF EXISTS tags;
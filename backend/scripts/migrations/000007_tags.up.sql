@@ -0,0 +1,15 @@
+-- Normalized provider tags/categories
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS content_tags (
+    content_id BIGINT NOT NULL REFERENCES contents(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    PRIMARY KEY (content_id, tag_id)
+);
+
+-- Tag filters look up contents by tag
+CREATE INDEX IF NOT EXISTS idx_content_tags_tag_id ON content_tags(tag_id);
@@ -0,0 +1,8 @@
+-- Incremental sync state per provider
+CREATE TABLE IF NOT EXISTS sync_cursors (
+    provider_id VARCHAR(50) PRIMARY KEY,
+    etag TEXT NOT NULL DEFAULT '',
+    last_modified TEXT NOT NULL DEFAULT '',
+    max_published_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
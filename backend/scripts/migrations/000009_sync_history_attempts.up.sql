@@ -0,0 +1,2 @@
+-- Provider HTTP requests of each sync run, retries included
+ALTER TABLE sync_history ADD COLUMN IF NOT EXISTS attempts JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,3 @@
+ALTER TABLE content_metrics
+    DROP COLUMN IF EXISTS comments,
+    DROP COLUMN IF EXISTS duration_seconds;
@@ -0,0 +1,4 @@
+-- Video length and comment counts reported by providers
+ALTER TABLE content_metrics
+    ADD COLUMN IF NOT EXISTS duration_seconds INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS comments INT NOT NULL DEFAULT 0;
@@ -0,0 +1,13 @@
+-- Provider registry; seeded from the environment on first start, managed through the admin API afterwards
+CREATE TABLE IF NOT EXISTS providers (
+    id VARCHAR(50) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('json', 'xml', 'generic', 'feed', 'file')),
+    base_url TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    rate_limit_per_minute INT NOT NULL DEFAULT 0 CHECK (rate_limit_per_minute >= 0),
+    timeout_ms INT NOT NULL DEFAULT 0 CHECK (timeout_ms >= 0),
+    sync_interval_seconds INT NOT NULL DEFAULT 0 CHECK (sync_interval_seconds >= 0),
+    definition JSONB NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
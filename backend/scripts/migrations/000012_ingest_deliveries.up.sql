@@ -0,0 +1,12 @@
+-- Pushed ingest batches, keyed by the sender's delivery ID so redeliveries are applied once
+CREATE TABLE IF NOT EXISTS ingest_deliveries (
+    provider_id VARCHAR(50) NOT NULL,
+    delivery_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('processing', 'completed')),
+    result JSONB NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (provider_id, delivery_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingest_deliveries_received_at ON ingest_deliveries(received_at);
@@ -0,0 +1,2 @@
+ALTER TABLE sync_history DROP COLUMN IF EXISTS quarantined_contents;
+DROP TABLE IF EXISTS quarantined_items;
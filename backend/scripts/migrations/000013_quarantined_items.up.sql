@@ -0,0 +1,19 @@
+-- Provider items that failed validation, kept with their raw payload for retry or discard
+CREATE TABLE IF NOT EXISTS quarantined_items (
+    id BIGSERIAL PRIMARY KEY,
+    provider_id VARCHAR(50) NOT NULL,
+    item_key VARCHAR(255) NOT NULL,
+    provider_content_id TEXT NOT NULL DEFAULT '',
+    reasons TEXT[] NOT NULL,
+    raw_payload TEXT NOT NULL,
+    item JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'discarded')),
+    occurrences INT NOT NULL DEFAULT 1,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider_id, item_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quarantined_items_status ON quarantined_items(status, last_seen_at DESC);
+
+ALTER TABLE sync_history ADD COLUMN IF NOT EXISTS quarantined_contents INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Per-provider auth and HTTP settings; holds secret references, not secret values
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS transport JSONB NULL;
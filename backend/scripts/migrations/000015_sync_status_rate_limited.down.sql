@@ -0,0 +1,6 @@
+-- Enum values cannot be dropped, so the type is recreated without 'rate_limited'
+UPDATE sync_history SET sync_status = 'skipped' WHERE sync_status = 'rate_limited';
+ALTER TYPE sync_status_enum RENAME TO sync_status_enum_old;
+CREATE TYPE sync_status_enum AS ENUM ('success','partial','failed','in_progress','skipped');
+ALTER TABLE sync_history ALTER COLUMN sync_status TYPE sync_status_enum USING sync_status::text::sync_status_enum;
+DROP TYPE sync_status_enum_old;
@@ -0,0 +1,2 @@
+-- Runs refused by a provider's rate limit are recorded separately from other skips
+ALTER TYPE sync_status_enum ADD VALUE IF NOT EXISTS 'rate_limited';
@@ -0,0 +1,6 @@
+CREATE INDEX IF NOT EXISTS idx_contents_fts ON contents USING GIN (to_tsvector('english', title || ' ' || COALESCE(description, '')));
+DROP INDEX IF EXISTS idx_contents_search_vector;
+ALTER TABLE contents DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS content_ts_config(TEXT);
+DROP INDEX IF EXISTS idx_contents_language;
+ALTER TABLE contents DROP COLUMN IF EXISTS language;
@@ -0,0 +1,22 @@
+-- Content language ('en', 'tr', 'und' when undetermined), detected at ingest or set by the provider
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS language VARCHAR(8) NULL;
+CREATE INDEX IF NOT EXISTS idx_contents_language ON contents(language);
+
+-- Text search configuration of a language; must agree with language.TSConfig.
+-- Rows stored before detection (NULL) keep the english configuration they were indexed with.
+CREATE OR REPLACE FUNCTION content_ts_config(lang TEXT) RETURNS regconfig AS $$
+    SELECT CASE
+        WHEN lang IS NULL THEN 'english'::regconfig
+        WHEN lang = 'en' THEN 'english'::regconfig
+        WHEN lang = 'tr' THEN 'turkish'::regconfig
+        ELSE 'simple'::regconfig
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Per-row tsvector in the row's language; title terms weigh A, description terms B
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(content_ts_config(language), title), 'A') ||
+    setweight(to_tsvector(content_ts_config(language), COALESCE(description, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_contents_search_vector ON contents USING GIN (search_vector);
+DROP INDEX IF EXISTS idx_contents_fts;
@@ -0,0 +1,3 @@
+ALTER TABLE sync_history DROP COLUMN IF EXISTS removed_contents;
+DROP INDEX IF EXISTS idx_contents_missed_syncs;
+ALTER TABLE contents DROP COLUMN IF EXISTS missed_syncs;
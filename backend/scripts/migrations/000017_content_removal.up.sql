@@ -0,0 +1,6 @@
+-- Consecutive complete syncs a content was missing from; reset when the provider returns it again.
+-- A soft-deleted row with missed syncs was removed by sync and is restored when it reappears.
+ALTER TABLE contents ADD COLUMN IF NOT EXISTS missed_syncs INT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_contents_missed_syncs ON contents(provider_id) WHERE missed_syncs > 0;
+
+ALTER TABLE sync_history ADD COLUMN IF NOT EXISTS removed_contents INT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE sync_history DROP COLUMN IF EXISTS changed_contents;
@@ -0,0 +1,2 @@
+-- Updated items whose title, type, description, URLs or publish date changed at the provider.
+ALTER TABLE sync_history ADD COLUMN IF NOT EXISTS changed_contents INT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Who manages a provider row: 'env' rows are reconciled with the environment on every
+-- start, 'admin' rows belong to the admin API. Existing rows start as admin rows; a
+-- start claims those matching the environment and hands env rows that are no longer
+-- configured over to the admin API.
+ALTER TABLE providers ADD COLUMN IF NOT EXISTS source VARCHAR(10) NOT NULL DEFAULT 'admin' CHECK (source IN ('env', 'admin'));
@@ -17,6 +17,7 @@ import (
 
 	"search_engine/internal/api/dto"
 	"search_engine/internal/api/handlers"
+	"search_engine/internal/infrastructure/cache"
 	"search_engine/internal/infrastructure/repository/postgres"
 	"search_engine/internal/infrastructure/services"
 	"search_engine/tests"
@@ -28,7 +29,7 @@ func TestHealthEndpoint(t *testing.T) {
 	defer env.Cleanup()
 
 	// Create health handler
-	healthHandler := handlers.NewHealthHandler(env.DB, env.Redis, time.Now(), "1.0.0-test", env.Logger)
+	healthHandler := handlers.NewHealthHandler(env.DB, env.Redis, time.Now(), "1.0.0-test", env.Logger, nil, nil)
 
 	// Create router
 	router := gin.New()
@@ -113,7 +114,7 @@ func TestContentsSearchIntegration(t *testing.T) {
 		Repo:            repo,
 		DefaultPageSize: 20,
 		MaxPageSize:     100,
-		CacheClient:     env.Redis,
+		Cache:           cache.NewStore(env.Redis, nil, nil),
 		CacheEnabled:    false,
 	}
 
@@ -230,7 +231,7 @@ func TestContentsStatsIntegration(t *testing.T) {
 		Repo:            repo,
 		DefaultPageSize: 20,
 		MaxPageSize:     100,
-		CacheClient:     env.Redis,
+		Cache:           cache.NewStore(env.Redis, nil, nil),
 		CacheEnabled:    false,
 	}
 
@@ -284,7 +285,7 @@ func TestContentsDetailIntegration(t *testing.T) {
 		Repo:            repo,
 		DefaultPageSize: 20,
 		MaxPageSize:     100,
-		CacheClient:     env.Redis,
+		Cache:           cache.NewStore(env.Redis, nil, nil),
 		CacheEnabled:    false,
 	}
 
@@ -326,7 +327,7 @@ func TestErrorHandlingIntegration(t *testing.T) {
 		Repo:            repo,
 		DefaultPageSize: 20,
 		MaxPageSize:     100,
-		CacheClient:     env.Redis,
+		Cache:           cache.NewStore(env.Redis, nil, nil),
 		CacheEnabled:    false,
 	}
 
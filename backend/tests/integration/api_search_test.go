@@ -38,12 +38,12 @@ func (s *stubContentRepo) GetByProviderKey(_ context.Context, _, _ string) (*ent
 }
 func (s *stubContentRepo) ListIDs(_ context.Context, _, _ int) ([]int64, error) { return nil, nil }
 func (s *stubContentRepo) CountAll(_ context.Context) (int64, error)            { return 0, nil }
-func (s *stubContentRepo) SearchWithFilters(_ context.Context, keyword string, contentType *entities.ContentType, pagination repositories.Pagination, sort repositories.SearchSort) ([]repositories.ContentWithMetrics, int64, error) {
+func (s *stubContentRepo) SearchWithFilters(_ context.Context, keyword string, filters repositories.ContentFilters, pagination repositories.Pagination, sort repositories.SearchSort) ([]repositories.ContentWithMetrics, int64, error) {
 	// Return one predictable item
 	now := time.Now().UTC()
 	ct := entities.ContentTypeVideo
-	if contentType != nil {
-		ct = *contentType
+	if filters.ContentType != nil {
+		ct = *filters.ContentType
 	}
 	item := repositories.ContentWithMetrics{
 		Content: entities.Content{
@@ -70,6 +70,12 @@ func (s *stubContentRepo) CountByProvider(_ context.Context) (map[string]int64,
 	return map[string]int64{}, nil
 }
 func (s *stubContentRepo) SoftDelete(_ context.Context, _ int64) error { return nil }
+func (s *stubContentRepo) MarkMissing(_ context.Context, _ string, _ []string, _ int) ([]int64, error) {
+	return nil, nil
+}
+func (s *stubContentRepo) RestoreSeen(_ context.Context, _ string, _ []string) (int, error) {
+	return 0, nil
+}
 func (s *stubContentRepo) ListIDsByType(_ context.Context, _ entities.ContentType, _, _ int) ([]int64, error) {
 	return nil, nil
 }
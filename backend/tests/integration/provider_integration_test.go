@@ -18,7 +18,7 @@ func TestJSONProviderIntegration(t *testing.T) {
 	baseURL := "http://localhost:8080/mock/provider1"
 	provider := providers.NewJSONProvider(baseURL, 10*time.Second)
 
-	contents, err := provider.FetchContents()
+	contents, err := provider.FetchContents(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to fetch contents from JSON provider: %v", err)
 	}
@@ -48,7 +48,7 @@ func TestXMLProviderIntegration(t *testing.T) {
 	baseURL := "http://localhost:8080/mock/provider2"
 	provider := providers.NewXMLProvider(baseURL, 10*time.Second)
 
-	contents, err := provider.FetchContents()
+	contents, err := provider.FetchContents(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to fetch contents from XML provider: %v", err)
 	}
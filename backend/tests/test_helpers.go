@@ -176,6 +176,8 @@ func runMigrations(ctx context.Context, connStr string) error {
 			likes BIGINT NOT NULL DEFAULT 0,
 			reading_time INT NOT NULL DEFAULT 0,
 			reactions INT NOT NULL DEFAULT 0,
+			duration_seconds INT NOT NULL DEFAULT 0,
+			comments INT NOT NULL DEFAULT 0,
 			final_score NUMERIC(10,2) NOT NULL DEFAULT 0,
 			recalculated_at TIMESTAMPTZ NULL,
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -219,6 +221,21 @@ func runMigrations(ctx context.Context, connStr string) error {
 			RETURN title_similarity + desc_similarity;
 		END;
 		$$ LANGUAGE plpgsql IMMUTABLE;`,
+		`ALTER TABLE contents ADD COLUMN IF NOT EXISTS language VARCHAR(8) NULL;`,
+		`CREATE OR REPLACE FUNCTION content_ts_config(lang TEXT) RETURNS regconfig AS $$
+			SELECT CASE
+				WHEN lang IS NULL THEN 'english'::regconfig
+				WHEN lang = 'en' THEN 'english'::regconfig
+				WHEN lang = 'tr' THEN 'turkish'::regconfig
+				ELSE 'simple'::regconfig
+			END
+		$$ LANGUAGE sql IMMUTABLE;`,
+		`ALTER TABLE contents ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector(content_ts_config(language), title), 'A') ||
+			setweight(to_tsvector(content_ts_config(language), COALESCE(description, '')), 'B')
+		) STORED;`,
+		`CREATE INDEX IF NOT EXISTS idx_contents_search_vector ON contents USING GIN (search_vector);`,
+		`ALTER TABLE contents ADD COLUMN IF NOT EXISTS missed_syncs INT NOT NULL DEFAULT 0;`,
 	}
 
 	for _, migration := range migrations {